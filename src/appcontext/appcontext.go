@@ -34,16 +34,31 @@ type Context struct {
 	ThumbnailStorage        *storage.ThumbnailStorage
 	PlaylistStorage         *storage.PlaylistStorage
 	AlbumStorage            *storage.AlbumStorage
+	ArtistStorage           *storage.ArtistStorage
+	GenreStorage            *storage.GenreStorage
 	TrackListensStorage     *storage.TrackListensStorage
+	StarredItemStorage      *storage.StarredItemStorage
+	RatingStorage           *storage.RatingStorage
 	CachedMuxSongStorage    *storage.CachedMuxSongStorage
 	CachedMuxAlbumStorage   *storage.CachedMuxAlbumStorage
 	CachedMuxArtistStorage  *storage.CachedMuxArtistStorage
 	MuxedSongListensStorage *storage.MuxedSongListensStorage
+	MuxedRatingStorage      *storage.MuxedRatingStorage
+	MuxedStarredItemStorage *storage.MuxedStarredItemStorage
+	MuxedBookmarkStorage    *storage.MuxedBookmarkStorage
 	ExternalPlaylistStorage *storage.ExternalPlaylistStorage
 	LastFmSessionStorage    *storage.LastFmSessionStorage
 	YtdlpMetadataStorage    *storage.YtdlpMetadataStorage
+	PodcastStorage          *storage.PodcastStorage
+	RadioStationStorage     *storage.RadioStationStorage
+	LyricsStorage           *storage.LyricsStorage
+	PlayQueueStorage        *storage.PlayQueueStorage
+	ShareStorage            *storage.ShareStorage
+	BookmarkStorage         *storage.BookmarkStorage
 	MediaStorage            *storage.MediaStorage
 	StreamCacheStorage      *storage.StreamCacheStorage
+	UserStorage             *storage.UserStorage
+	ApiKeyStorage           *storage.ApiKeyStorage
 
 	Ytdlp  *ytdlp.Ytdlp
 	Ffmpeg *ffmpeg.Ffmpeg
@@ -55,6 +70,9 @@ type Context struct {
 
 	LastFmService *logic.LastFmService
 
+	UserService   *logic.UserService
+	ApiKeyService *logic.ApiKeyService
+
 	ThumbnailService *logic.ThumbnailService
 
 	TrackNormalizer   *logic.TrackNormalizer
@@ -64,6 +82,10 @@ type Context struct {
 	SourceService     *logic.SourceService
 	TapeService       *logic.TapeService
 	AutoImportService *logic.AutoImportService
+	PodcastService    *logic.PodcastService
+	RadioService      *logic.RadioService
+	LyricsService     *logic.LyricsService
+	ShareService      *logic.ShareService
 
 	SearchService    *logic.SearchService
 	SongCacheService *logic.SongCacheService
@@ -72,7 +94,13 @@ type Context struct {
 	SubsonicMuxer     logic.SubsonicService
 	SubsonicService   logic.SubsonicService
 
+	PlayQueueService       *logic.PlayQueueService
+	NowPlayingService      *logic.NowPlayingService
+	LastFmDiscoveryService *logic.LastFmDiscoveryService
+
 	ScrobbleService *logic.ScrobbleService
+
+	SyncLibraryHandler *tasks.SyncLibraryHandler
 }
 
 func NewContext(config *configPkg.TapesonicConfig) (*Context, error) {
@@ -121,9 +149,21 @@ func NewContext(config *configPkg.TapesonicConfig) (*Context, error) {
 	if context.AlbumStorage, err = storage.NewAlbumStorage(db); err != nil {
 		return nil, err
 	}
+	if context.ArtistStorage, err = storage.NewArtistStorage(db); err != nil {
+		return nil, err
+	}
+	if context.GenreStorage, err = storage.NewGenreStorage(db); err != nil {
+		return nil, err
+	}
 	if context.TrackListensStorage, err = storage.NewTrackListensStorage(db); err != nil {
 		return nil, err
 	}
+	if context.StarredItemStorage, err = storage.NewStarredItemStorage(db); err != nil {
+		return nil, err
+	}
+	if context.RatingStorage, err = storage.NewRatingStorage(db); err != nil {
+		return nil, err
+	}
 	if context.CachedMuxSongStorage, err = storage.NewCachedMuxSongStorage(db); err != nil {
 		return nil, err
 	}
@@ -136,6 +176,15 @@ func NewContext(config *configPkg.TapesonicConfig) (*Context, error) {
 	if context.MuxedSongListensStorage, err = storage.NewMuxedSongListensStorage(db); err != nil {
 		return nil, err
 	}
+	if context.MuxedRatingStorage, err = storage.NewMuxedRatingStorage(db); err != nil {
+		return nil, err
+	}
+	if context.MuxedStarredItemStorage, err = storage.NewMuxedStarredItemStorage(db); err != nil {
+		return nil, err
+	}
+	if context.MuxedBookmarkStorage, err = storage.NewMuxedBookmarkStorage(db); err != nil {
+		return nil, err
+	}
 	if context.ExternalPlaylistStorage, err = storage.NewExternalPlaylistStorage(db); err != nil {
 		return nil, err
 	}
@@ -145,10 +194,46 @@ func NewContext(config *configPkg.TapesonicConfig) (*Context, error) {
 	if context.YtdlpMetadataStorage, err = storage.NewYtdlpMetadataStorage(db); err != nil {
 		return nil, err
 	}
+	if context.PodcastStorage, err = storage.NewPodcastStorage(db); err != nil {
+		return nil, err
+	}
+	if context.RadioStationStorage, err = storage.NewRadioStationStorage(db); err != nil {
+		return nil, err
+	}
+	if context.LyricsStorage, err = storage.NewLyricsStorage(db); err != nil {
+		return nil, err
+	}
+	if context.PlayQueueStorage, err = storage.NewPlayQueueStorage(db); err != nil {
+		return nil, err
+	}
+	if context.ShareStorage, err = storage.NewShareStorage(db); err != nil {
+		return nil, err
+	}
+	if context.BookmarkStorage, err = storage.NewBookmarkStorage(db); err != nil {
+		return nil, err
+	}
+
+	if context.UserStorage, err = storage.NewUserStorage(db); err != nil {
+		return nil, err
+	}
+	if context.ApiKeyStorage, err = storage.NewApiKeyStorage(db); err != nil {
+		return nil, err
+	}
+
+	if err = storage.Migrate(db, config.Username); err != nil {
+		return nil, err
+	}
 
-	if err = storage.Migrate(db); err != nil {
+	if context.UserService, err = logic.NewUserService(
+		context.UserStorage,
+		path.Join(config.DataStorageDir, "encryption.key"),
+	); err != nil {
 		return nil, err
 	}
+	if err = context.UserService.EnsureUser(config.Username, config.Password); err != nil {
+		return nil, err
+	}
+	context.ApiKeyService = logic.NewApiKeyService(context.ApiKeyStorage, context.UserStorage)
 
 	context.MediaStorage = storage.NewMediaStorage(
 		db,
@@ -189,12 +274,14 @@ func NewContext(config *configPkg.TapesonicConfig) (*Context, error) {
 
 	context.ScrobbleService = logic.NewScrobbleService(
 		context.ListenBrainzClient,
+		config.Username,
 		context.LastFmService,
 	)
 
 	context.ThumbnailService = logic.NewThumbnailService(
 		context.ThumbnailStorage,
 		path.Join(config.MediaStorageDir, "thumbnails"),
+		path.Join(config.CacheDir, "thumbnails"),
 	)
 
 	context.TrackNormalizer = logic.NewTrackNormalizer()
@@ -214,44 +301,87 @@ func NewContext(config *configPkg.TapesonicConfig) (*Context, error) {
 		context.ThumbnailService,
 		context.TrackNormalizer,
 	)
-	context.TapeService = logic.NewTapeService(context.TapeStorage, context.TrackStorage)
+	context.TapeService = logic.NewTapeService(context.TapeStorage, context.TrackStorage, context.ThumbnailService)
 	context.AutoImportService = logic.NewAutoImportService(
 		context.SourceService,
 		context.TrackService,
 		context.TrackMatcher,
 	)
+	context.PodcastService = logic.NewPodcastService(
+		context.PodcastStorage,
+		context.SourceStorage,
+		context.TrackStorage,
+		context.YtdlpService,
+		context.SourceFileService,
+		context.ThumbnailService,
+		config.PodcastMaxEpisodes,
+	)
+	context.RadioService = logic.NewRadioService(
+		context.RadioStationStorage,
+		context.YtdlpService,
+		context.Ffmpeg,
+		config.RadioIdleTimeout,
+	)
+	context.LyricsService = logic.NewLyricsService(
+		context.LyricsStorage,
+		context.TrackStorage,
+		context.YtdlpService,
+	)
 
 	context.SearchService = logic.NewSearchService(context.SourceStorage, context.TrackStorage)
 
 	internalSubsonic := logic.NewSubsonicNamedService(
-		"tapesonic",
+		configPkg.InternalSubsonicName,
 		logic.NewSubsonicInternalService(
 			context.TrackStorage,
+			context.SourceStorage,
 			context.AlbumStorage,
+			context.ArtistStorage,
+			context.GenreStorage,
 			context.PlaylistStorage,
+			context.TapeStorage,
 			context.TrackListensStorage,
+			context.StarredItemStorage,
+			context.RatingStorage,
+			context.BookmarkStorage,
+			context.UserStorage,
 			context.MediaStorage,
 			context.StreamCacheStorage,
 			context.Ffmpeg,
 			context.ThumbnailService,
 			util.TakeIf(context.ScrobbleService, config.ScrobbleMode == configPkg.ScrobbleTapesonic),
 			context.YtdlpService,
+			context.PodcastService,
+			context.LyricsService,
 		),
 	)
 	context.SubsonicProviders = append(context.SubsonicProviders, internalSubsonic)
 
-	if config.SubsonicProxyUrl != "" {
+	context.ShareService = logic.NewShareService(context.ShareStorage, internalSubsonic)
+
+	scrobbledProviders := map[string]bool{
+		internalSubsonic.Name(): config.ScrobbleMode == configPkg.ScrobbleAll,
+	}
+	for _, proxy := range config.SubsonicProxies {
 		externalSubsonic := logic.NewSubsonicNamedService(
-			"proxy",
-			logic.NewSubsonicExternalService(
-				client.NewSubsonicClient(
-					config.SubsonicProxyUrl,
-					config.SubsonicProxyUsername,
-					config.SubsonicProxyPassword,
+			proxy.Name,
+			logic.NewSubsonicSharedAccountService(
+				proxy.Name,
+				logic.NewSubsonicExternalService(
+					client.NewSubsonicClient(
+						proxy.Url,
+						proxy.Username,
+						proxy.Password,
+					),
 				),
+				context.UserStorage,
+				context.MuxedStarredItemStorage,
+				context.MuxedRatingStorage,
+				context.MuxedBookmarkStorage,
 			),
 		)
 		context.SubsonicProviders = append(context.SubsonicProviders, externalSubsonic)
+		scrobbledProviders[proxy.Name] = proxy.Scrobble
 	}
 
 	context.SongCacheService = logic.NewSongCacheService(
@@ -262,13 +392,14 @@ func NewContext(config *configPkg.TapesonicConfig) (*Context, error) {
 
 	subsonicMux := logic.NewSubsonicMuxService(
 		context.MuxedSongListensStorage,
+		context.MuxedRatingStorage,
 		context.SongCacheService,
-		util.TakeIf(context.ScrobbleService, config.ScrobbleMode == configPkg.ScrobbleAll),
+		context.ScrobbleService,
 	)
 	context.SubsonicMuxer = subsonicMux
 
 	for _, subsonicProvider := range context.SubsonicProviders {
-		subsonicMux.AddService(subsonicProvider)
+		subsonicMux.AddService(subsonicProvider, scrobbledProviders[subsonicProvider.Name()])
 	}
 
 	context.SubsonicService = logic.NewSubsonicMainService(
@@ -280,6 +411,29 @@ func NewContext(config *configPkg.TapesonicConfig) (*Context, error) {
 		context.ExternalPlaylistStorage,
 	)
 
+	context.PlayQueueService = logic.NewPlayQueueService(
+		context.PlayQueueStorage,
+		context.SubsonicMuxer,
+	)
+	context.NowPlayingService = logic.NewNowPlayingService(context.SubsonicMuxer)
+	context.LastFmDiscoveryService = logic.NewLastFmDiscoveryService(
+		context.LastFmClient,
+		context.SubsonicProviders,
+		context.SubsonicMuxer,
+		context.SongCacheService,
+		context.CachedMuxArtistStorage,
+		context.YtdlpService,
+		context.AutoImportService,
+		config.LastFmAutoImport,
+	)
+
+	context.SyncLibraryHandler = tasks.NewSyncLibraryHandler(
+		context.SubsonicProviders,
+		context.CachedMuxSongStorage,
+		context.CachedMuxAlbumStorage,
+		context.CachedMuxArtistStorage,
+	)
+
 	if err = registerBackgroundTasks(&context); err != nil {
 		return nil, err
 	}
@@ -349,16 +503,19 @@ func registerBackgroundTasks(context *Context) error {
 	scheduledTasks = append(
 		scheduledTasks,
 		backgroundTaskAndConfig{
-			task: tasks.NewSyncLibraryHandler(
-				context.SubsonicProviders,
-				context.CachedMuxSongStorage,
-				context.CachedMuxAlbumStorage,
-				context.CachedMuxArtistStorage,
-			),
+			task:   context.SyncLibraryHandler,
 			config: context.Config.TasksSyncLibrary,
 		},
 	)
 
+	scheduledTasks = append(
+		scheduledTasks,
+		backgroundTaskAndConfig{
+			task:   tasks.NewRefreshPodcastsTaskHandler(context.PodcastService),
+			config: context.Config.TasksRefreshPodcasts,
+		},
+	)
+
 	for _, scheduledTask := range scheduledTasks {
 		err := setupBackgroundTask(cron, scheduledTask.task, scheduledTask.config)
 		if err != nil {
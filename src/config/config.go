@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"tapesonic/util"
@@ -18,6 +20,10 @@ const (
 	ScrobbleAll       = 2
 
 	CronDisabled = "off"
+
+	// id prefixes of the library and of playlists imported from other services, can't be used as proxy names
+	InternalSubsonicName         = "tapesonic"
+	ExternalPlaylistSubsonicName = "external"
 )
 
 type TapesonicConfig struct {
@@ -28,6 +34,9 @@ type TapesonicConfig struct {
 	Username   string
 	Password   string
 
+	// disabling it leaves only salted token and api key authentication for subsonic clients
+	AllowPasswordAuth bool
+
 	WebappDir       string
 	DataStorageDir  string
 	MediaStorageDir string
@@ -43,21 +52,45 @@ type TapesonicConfig struct {
 	TasksSyncLibrary              BackgroundTaskConfig
 	TasksListenBrainzPlaylistSync BackgroundTaskConfig
 	TasksLastFmPlaylistSync       BackgroundTaskConfig
+	TasksRefreshPodcasts          BackgroundTaskConfig
 
 	ScrobbleMode int
 
-	SubsonicProxyUrl      string
-	SubsonicProxyUsername string
-	SubsonicProxyPassword string
+	SubsonicProxies []SubsonicProxyConfig
 
 	StreamCacheSize        int64
 	StreamCacheMinLifetime time.Duration
 
+	// keyed by lowercase client name
+	TranscodingProfiles map[string]TranscodingProfile
+
 	ListenBrainzToken string
 
 	LastFmApiKey             string
 	LastFmApiSecret          string
 	LastFmTargetPlaylistSize int
+	LastFmAutoImport         bool
+
+	PodcastMaxEpisodes int
+
+	RadioIdleTimeout time.Duration
+}
+
+type SubsonicProxyConfig struct {
+	// also used as the prefix of all ids coming from the proxied server
+	Name     string
+	Url      string
+	Username string
+	Password string
+
+	// whether Tapesonic scrobbles tracks played from this server to external services
+	Scrobble bool
+}
+
+type TranscodingProfile struct {
+	Name       string
+	Format     string
+	MaxBitRate int
 }
 
 type BackgroundTaskConfig struct {
@@ -97,6 +130,19 @@ func NewConfig() (*TapesonicConfig, error) {
 		scrobbleMode = ScrobbleAll
 	}
 
+	subsonicProxies, err := getSubsonicProxies(os.Getenv("TAPESONIC_SUBSONIC_PROXIES"), scrobbleMode == ScrobbleAll)
+	if err != nil {
+		return nil, err
+	}
+
+	transcodingProfiles, err := getTranscodingProfiles(
+		os.Getenv("TAPESONIC_TRANSCODING_PROFILES"),
+		os.Getenv("TAPESONIC_TRANSCODING_CLIENTS"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	config := &TapesonicConfig{
 		LogLevel: logLevel,
 		DevMode:  getEnvBoolOrDefault("TAPESONIC_DEV_MODE", false),
@@ -105,6 +151,8 @@ func NewConfig() (*TapesonicConfig, error) {
 		Username:   os.Getenv("TAPESONIC_USERNAME"),
 		Password:   os.Getenv("TAPESONIC_PASSWORD"),
 
+		AllowPasswordAuth: getEnvBoolOrDefault("TAPESONIC_ALLOW_PASSWORD_AUTH", true),
+
 		YtdlpPath:  getEnvOrDefault("TAPESONIC_YTDLP_PATH", "yt-dlp"),
 		FfmpegPath: getEnvOrDefault("TAPESONIC_FFMPEG_PATH", "ffmpeg"),
 
@@ -120,21 +168,27 @@ func NewConfig() (*TapesonicConfig, error) {
 		TasksSyncLibrary:              getBackgroundTaskConfig("SYNC_LIBRARY", "0 */15 * * * *", 1*time.Minute, 5),
 		TasksListenBrainzPlaylistSync: getBackgroundTaskConfig("LISTENBRAINZ_PLAYLIST_SYNC", "0 0 4 * * *", 15*time.Minute, 5),
 		TasksLastFmPlaylistSync:       getBackgroundTaskConfig("LASTFM_PLAYLIST_SYNC", "0 0 4 * * *", 15*time.Minute, 5),
+		TasksRefreshPodcasts:          getBackgroundTaskConfig("REFRESH_PODCASTS", "0 0 * * * *", 15*time.Minute, 3),
 
 		ScrobbleMode: scrobbleMode,
 
-		SubsonicProxyUrl:      os.Getenv("TAPESONIC_SUBSONIC_PROXY_URL"),
-		SubsonicProxyUsername: os.Getenv("TAPESONIC_SUBSONIC_PROXY_USERNAME"),
-		SubsonicProxyPassword: os.Getenv("TAPESONIC_SUBSONIC_PROXY_PASSWORD"),
+		SubsonicProxies: subsonicProxies,
 
 		StreamCacheSize:        getEnvSizeOrDefault("TAPESONIC_STREAM_CACHE_SIZE", 512*1024*1024), // 512 MB
 		StreamCacheMinLifetime: getEnvDurationOrDefault("TAPESONIC_STREAM_CACHE_MIN_LIFETIME", 1*time.Hour),
 
+		TranscodingProfiles: transcodingProfiles,
+
 		ListenBrainzToken: os.Getenv("TAPESONIC_LISTENBRAINZ_TOKEN"),
 
 		LastFmApiKey:             os.Getenv("TAPESONIC_LASTFM_API_KEY"),
 		LastFmApiSecret:          os.Getenv("TAPESONIC_LASTFM_API_SECRET"),
 		LastFmTargetPlaylistSize: getEnvIntOrDefault("TAPESONIC_LASTFM_TARGET_PLAYLIST_SIZE", 40),
+		LastFmAutoImport:         getEnvBoolOrDefault("TAPESONIC_LASTFM_AUTO_IMPORT", false),
+
+		PodcastMaxEpisodes: getEnvIntOrDefault("TAPESONIC_PODCAST_MAX_EPISODES", 20),
+
+		RadioIdleTimeout: getEnvDurationOrDefault("TAPESONIC_RADIO_IDLE_TIMEOUT", 1*time.Minute),
 	}
 
 	return config, nil
@@ -153,6 +207,109 @@ func getBackgroundTaskConfig(
 	}
 }
 
+var subsonicProxyNameRegex = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// proxies are listed as `name1,name2,...` and configured via `TAPESONIC_SUBSONIC_PROXY_<NAME>_*` variables;
+// the single proxy configured via `TAPESONIC_SUBSONIC_PROXY_*` variables is named `proxy` to keep its ids
+func getSubsonicProxies(namesText string, defaultScrobble bool) ([]SubsonicProxyConfig, error) {
+	proxies := []SubsonicProxyConfig{}
+
+	if url := os.Getenv("TAPESONIC_SUBSONIC_PROXY_URL"); url != "" {
+		proxies = append(proxies, SubsonicProxyConfig{
+			Name:     "proxy",
+			Url:      url,
+			Username: os.Getenv("TAPESONIC_SUBSONIC_PROXY_USERNAME"),
+			Password: os.Getenv("TAPESONIC_SUBSONIC_PROXY_PASSWORD"),
+			Scrobble: getEnvBoolOrDefault("TAPESONIC_SUBSONIC_PROXY_SCROBBLE", defaultScrobble),
+		})
+	}
+
+	for _, name := range splitList(namesText) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !subsonicProxyNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("TAPESONIC_SUBSONIC_PROXIES: proxy name `%s` must only contain letters and digits", name)
+		}
+		if name == InternalSubsonicName || name == ExternalPlaylistSubsonicName || slices.ContainsFunc(proxies, func(p SubsonicProxyConfig) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("TAPESONIC_SUBSONIC_PROXIES: proxy name `%s` is already used", name)
+		}
+
+		prefix := fmt.Sprintf("TAPESONIC_SUBSONIC_PROXY_%s", strings.ToUpper(name))
+		url := os.Getenv(prefix + "_URL")
+		if url == "" {
+			return nil, fmt.Errorf("%s_URL must be set for proxy `%s`", prefix, name)
+		}
+
+		proxies = append(proxies, SubsonicProxyConfig{
+			Name:     name,
+			Url:      url,
+			Username: os.Getenv(prefix + "_USERNAME"),
+			Password: os.Getenv(prefix + "_PASSWORD"),
+			Scrobble: getEnvBoolOrDefault(prefix+"_SCROBBLE", defaultScrobble),
+		})
+	}
+
+	return proxies, nil
+}
+
+// profiles are defined as `name=format:maxBitRate,...` and assigned as `clientName=profileName,...`
+func getTranscodingProfiles(profilesText string, clientsText string) (map[string]TranscodingProfile, error) {
+	profiles := map[string]TranscodingProfile{}
+	for _, profileText := range splitList(profilesText) {
+		name, definition, ok := strings.Cut(profileText, "=")
+		if !ok {
+			return nil, fmt.Errorf("TAPESONIC_TRANSCODING_PROFILES: profile `%s` must be defined as `name=format:maxBitRate`", profileText)
+		}
+
+		format, maxBitRateText, _ := strings.Cut(definition, ":")
+		if strings.TrimSpace(format) == "raw" {
+			format = ""
+		}
+
+		maxBitRate := 0
+		if strings.TrimSpace(maxBitRateText) != "" {
+			value := util.StringToIntOrNull(strings.TrimSpace(maxBitRateText))
+			if value == nil || *value < 0 {
+				return nil, fmt.Errorf("TAPESONIC_TRANSCODING_PROFILES: max bitrate of profile `%s` is not a positive number: %s", name, maxBitRateText)
+			}
+			maxBitRate = *value
+		}
+
+		name = strings.TrimSpace(name)
+		profiles[name] = TranscodingProfile{
+			Name:       name,
+			Format:     strings.ToLower(strings.TrimSpace(format)),
+			MaxBitRate: maxBitRate,
+		}
+	}
+
+	clientProfiles := map[string]TranscodingProfile{}
+	for _, clientText := range splitList(clientsText) {
+		clientName, profileName, ok := strings.Cut(clientText, "=")
+		if !ok {
+			return nil, fmt.Errorf("TAPESONIC_TRANSCODING_CLIENTS: client `%s` must be assigned as `clientName=profileName`", clientText)
+		}
+
+		profile, ok := profiles[strings.TrimSpace(profileName)]
+		if !ok {
+			return nil, fmt.Errorf("TAPESONIC_TRANSCODING_CLIENTS: unknown profile `%s` for client `%s`", profileName, clientName)
+		}
+
+		clientProfiles[strings.ToLower(strings.TrimSpace(clientName))] = profile
+	}
+
+	return clientProfiles, nil
+}
+
+func splitList(text string) []string {
+	result := []string{}
+	for _, item := range strings.Split(text, ",") {
+		if strings.TrimSpace(item) != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 func getEnvOrDefault(name string, defaultValue string) string {
 	value := os.Getenv(name)
 	if value != "" {
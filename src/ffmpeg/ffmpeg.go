@@ -11,6 +11,7 @@ import (
 	"slices"
 	"strings"
 	"tapesonic/config"
+	"tapesonic/util"
 )
 
 var (
@@ -23,10 +24,21 @@ var (
 		"opus": "opus",
 	}
 	formatToCodecs = map[string][]string{
+		"aac":  {"mp4a"},
 		"flac": {"flac"},
 		"mp3":  {"mp3"},
 		"opus": {"opus"},
 	}
+	// raw aac can't be written to stdout without a container
+	formatToMuxer = map[string]string{
+		"aac": "adts",
+	}
+	// lossless formats can't be limited in bitrate
+	formatToMaxBitRate = map[string]int{
+		"aac":  512,
+		"mp3":  320,
+		"opus": 510,
+	}
 
 	versionRegexp = regexp.MustCompile(`ffmpeg version ([^\s]+)`)
 )
@@ -35,8 +47,23 @@ const (
 	ANY_FORMAT      = ""
 	SEEKABLE_FORMAT = "mp3" // opus produces different binaries on each encode which breaks seeking
 	FALLBACK_FORMAT = "opus"
+	SEGMENT_FORMAT  = "aac" // HLS players only support a few codecs inside of MPEG-TS, aac is the most widely supported one
 )
 
+func IsSupportedFormat(format string) bool {
+	_, ok := formatToCodecs[format]
+	return ok
+}
+
+// returns the bitrate in kbps the format will be encoded with, 0 if it won't be limited
+func GetBitRate(format string, maxBitRate int) int {
+	formatMaxBitRate, ok := formatToMaxBitRate[format]
+	if !ok || maxBitRate <= 0 {
+		return 0
+	}
+	return min(maxBitRate, formatMaxBitRate)
+}
+
 type Ffmpeg struct {
 	path string
 }
@@ -67,6 +94,7 @@ func (f *Ffmpeg) StreamFrom(
 	ctx context.Context,
 	sourceCodec string,
 	targetFormat string,
+	maxBitRate int,
 	offsetMs int64,
 	durationMs int64,
 	input string,
@@ -89,22 +117,28 @@ func (f *Ffmpeg) StreamFrom(
 
 	if targetFormat == ANY_FORMAT {
 		targetFormat = codecToFormat[sourceCodec]
-		if targetFormat == "" {
+		if targetFormat == "" || (maxBitRate > 0 && GetBitRate(targetFormat, maxBitRate) == 0) {
 			targetFormat = FALLBACK_FORMAT
 		}
 	}
 
+	bitRate := GetBitRate(targetFormat, maxBitRate)
+
 	if targetFormat == "opus" && offsetMs > 0 {
 		// ffmpeg somehow fails to copy the audio data from
 		// youtube-encoded opus if the starting position is not 0,
 		// so we have to reencode it
 		// https://stackoverflow.com/questions/60621646
 		targetFormat = "opus"
-	} else if slices.Contains(formatToCodecs[targetFormat], sourceCodec) {
+	} else if bitRate == 0 && slices.Contains(formatToCodecs[targetFormat], sourceCodec) {
 		args = append(args, "-c:a", "copy")
 	}
 
-	args = append(args, "-f", targetFormat)
+	if bitRate > 0 {
+		args = append(args, "-b:a", fmt.Sprintf("%dk", bitRate))
+	}
+
+	args = append(args, "-f", util.Coalesce(formatToMuxer[targetFormat], targetFormat))
 	args = append(args, "-")
 
 	cmd := exec.CommandContext(ctx, f.path, args...)
@@ -125,6 +159,158 @@ func (f *Ffmpeg) StreamFrom(
 	return targetFormat, &ffmpegReader{cancel: cancel, cmd: cmd, stdout: stdout}, nil
 }
 
+// encodes a part of the input as an MPEG-TS segment for HLS; timestamps are shifted by timestampOffsetMs,
+// so players can join segments which were encoded separately into a single stream
+func (f *Ffmpeg) SegmentFrom(
+	ctx context.Context,
+	maxBitRate int,
+	offsetMs int64,
+	durationMs int64,
+	timestampOffsetMs int64,
+	input string,
+) (reader io.ReadCloser, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	args := []string{}
+	args = append(args, "-v", "0")
+
+	if offsetMs > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", float32(offsetMs)/1000.0))
+	}
+
+	args = append(args, "-i", input)
+	args = append(args, "-t", fmt.Sprintf("%.3f", float32(durationMs)/1000.0))
+	args = append(args, "-vn")
+	args = append(args, "-c:a", SEGMENT_FORMAT)
+
+	if bitRate := GetBitRate(SEGMENT_FORMAT, maxBitRate); bitRate > 0 {
+		args = append(args, "-b:a", fmt.Sprintf("%dk", bitRate))
+	}
+
+	args = append(args, "-output_ts_offset", fmt.Sprintf("%.3f", float32(timestampOffsetMs)/1000.0))
+	args = append(args, "-f", "mpegts")
+	args = append(args, "-")
+
+	cmd := exec.CommandContext(ctx, f.path, args...)
+	slog.Log(context.Background(), config.LevelTrace, fmt.Sprintf("Segmenting via ffmpeg: %s", cmd.String()))
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start segmenting via `%s`: %w", cmd.String(), err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start segmenting via `%s`: %w", cmd.String(), err)
+	}
+
+	return &ffmpegReader{cancel: cancel, cmd: cmd, stdout: stdout}, nil
+}
+
+// cuts a part of the input without reencoding when possible, replacing all of its tags with the given ones
+func (f *Ffmpeg) CutFrom(
+	ctx context.Context,
+	sourceCodec string,
+	offsetMs int64,
+	durationMs int64,
+	input string,
+	metadata map[string]string,
+) (format string, reader io.ReadCloser, err error) {
+	sourceCodec = strings.Split(sourceCodec, ".")[0]
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	args := []string{}
+	args = append(args, "-v", "0")
+
+	if offsetMs > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", float32(offsetMs)/1000.0))
+	}
+
+	args = append(args, "-i", input)
+	args = append(args, "-t", fmt.Sprintf("%.3f", float32(durationMs)/1000.0))
+	args = append(args, "-vn")
+
+	// matroska can hold any codec, so it's used for everything that can't be copied to its own container
+	targetFormat := codecToFormat[sourceCodec]
+	if targetFormat == "" {
+		targetFormat = "matroska"
+	}
+
+	// same as in StreamFrom, youtube-encoded opus can't be copied from a non-zero position
+	if !(targetFormat == "opus" && offsetMs > 0) {
+		args = append(args, "-c:a", "copy")
+	}
+
+	args = append(args, "-map_metadata", "-1")
+
+	keys := []string{}
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if metadata[key] != "" {
+			args = append(args, "-metadata", fmt.Sprintf("%s=%s", key, metadata[key]))
+		}
+	}
+
+	args = append(args, "-f", targetFormat)
+	args = append(args, "-")
+
+	cmd := exec.CommandContext(ctx, f.path, args...)
+	slog.Log(context.Background(), config.LevelTrace, fmt.Sprintf("Cutting via ffmpeg: %s", cmd.String()))
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return "", nil, fmt.Errorf("failed to start cutting via `%s`: %w", cmd.String(), err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		cancel()
+		return "", nil, fmt.Errorf("failed to start cutting via `%s`: %w", cmd.String(), err)
+	}
+
+	return targetFormat, &ffmpegReader{cancel: cancel, cmd: cmd, stdout: stdout}, nil
+}
+
+// live streams are always reencoded since they usually come muxed with video in formats not suitable for audio-only clients
+func (f *Ffmpeg) StreamLive(
+	ctx context.Context,
+	targetFormat string,
+	input string,
+) (reader io.ReadCloser, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	args := []string{}
+	args = append(args, "-v", "0")
+	args = append(args, "-i", input)
+	args = append(args, "-vn")
+	args = append(args, "-f", targetFormat)
+	args = append(args, "-")
+
+	cmd := exec.CommandContext(ctx, f.path, args...)
+	slog.Log(context.Background(), config.LevelTrace, fmt.Sprintf("Streaming live via ffmpeg: %s", cmd.String()))
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start streaming via `%s`: %w", cmd.String(), err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start streaming via `%s`: %w", cmd.String(), err)
+	}
+
+	return &ffmpegReader{cancel: cancel, cmd: cmd, stdout: stdout}, nil
+}
+
 type ffmpegReader struct {
 	cancel context.CancelFunc
 	cmd    *exec.Cmd
@@ -7,6 +7,7 @@ require (
 	github.com/gorilla/mux v1.8.1
 	github.com/orandin/slog-gorm v1.0.1
 	github.com/robfig/cron/v3 v3.0.1
+	golang.org/x/image v0.18.0
 	golang.org/x/sync v0.7.0
 	golang.org/x/text v0.16.0
 	gorm.io/driver/sqlite v1.5.4
@@ -14,12 +14,17 @@ func GetHandlers(appCtx *appcontext.Context) map[string]http.HandlerFunc {
 	type PathHandler struct {
 		Path    string
 		Handler http.HandlerFunc
+
+		AllowNonAdmin bool
 	}
 
 	// todo: logging
 	rawHandlers := []PathHandler{
-		{Path: "/api/settings/lastfm/auth", Handler: util.AsHandlerFunc(handlers.NewSettingsLastFmAuthHandler(appCtx.LastFmService))},
-		{Path: "/api/settings/lastfm/create-auth-link", Handler: util.AsHandlerFunc(handlers.NewSettingsLastFmCreateAuthLinkHandler(appCtx.LastFmService))},
+		{Path: "/api/settings/lastfm/auth", Handler: util.AsHandlerFunc(handlers.NewSettingsLastFmAuthHandler(appCtx.LastFmService)), AllowNonAdmin: true},
+		{Path: "/api/settings/lastfm/create-auth-link", Handler: util.AsHandlerFunc(handlers.NewSettingsLastFmCreateAuthLinkHandler(appCtx.LastFmService)), AllowNonAdmin: true},
+
+		{Path: "/api/api-keys", Handler: util.AsHandlerFunc(handlers.NewApiKeysHandler(appCtx.ApiKeyService))},
+		{Path: "/api/api-keys/{apiKeyId}", Handler: util.AsHandlerFunc(handlers.NewApiKeyHandler(appCtx.ApiKeyService))},
 
 		{Path: "/api/tapes", Handler: util.AsHandlerFunc(handlers.NewTapesHandler(appCtx.TapeService))},
 		{Path: "/api/tapes/guess-metadata", Handler: util.AsHandlerFunc(handlers.NewGuessTapeMetadataHandler(appCtx.TapeService))},
@@ -30,9 +35,12 @@ func GetHandlers(appCtx *appcontext.Context) map[string]http.HandlerFunc {
 		{Path: "/api/sources/{sourceId}/hierarchy", Handler: util.AsHandlerFunc(handlers.NewSourceHierarchyHandler(appCtx.SourceService))},
 		{Path: "/api/sources/{sourceId}/tracks", Handler: util.AsHandlerFunc(handlers.NewSourceTracksHandler(appCtx.TrackService, appCtx.SourceService))},
 		{Path: "/api/sources/{sourceId}/file", Handler: util.AsHandlerFunc(handlers.NewSourceFileHandler(appCtx.SourceFileService))},
+		{Path: "/api/sources/{sourceId}/genres", Handler: util.AsHandlerFunc(handlers.NewSourceGenresHandler(appCtx.SourceService))},
 
 		{Path: "/api/tracks", Handler: util.AsHandlerFunc(handlers.NewTracksHandler(appCtx.TrackService, appCtx.SearchService))},
 
+		{Path: "/api/now-playing", Handler: util.AsHandlerFunc(handlers.NewNowPlayingHandler(appCtx.NowPlayingService))},
+
 		{Path: "/api/thumbnails", Handler: util.AsHandlerFunc(handlers.NewThumbnailsHandler(appCtx.ThumbnailService))},
 
 		{Path: "/media/thumbnails/{thumbnailId}", Handler: util.AsRawHandlerFunc(handlers.NewThumbnailRawHandler(appCtx.ThumbnailService))},
@@ -40,7 +48,7 @@ func GetHandlers(appCtx *appcontext.Context) map[string]http.HandlerFunc {
 
 	router := mux.NewRouter()
 	for _, pathHandler := range rawHandlers {
-		router.HandleFunc(pathHandler.Path, util.Authenticated(pathHandler.Handler, appCtx.Config))
+		router.HandleFunc(pathHandler.Path, util.Authenticated(pathHandler.Handler, appCtx.UserService, !pathHandler.AllowNonAdmin))
 	}
 
 	// todo: wow that's disgusting
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"tapesonic/logic"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type apiKeyHandler struct {
+	service *logic.ApiKeyService
+}
+
+func NewApiKeyHandler(
+	service *logic.ApiKeyService,
+) *apiKeyHandler {
+	return &apiKeyHandler{
+		service: service,
+	}
+}
+
+func (h *apiKeyHandler) Methods() []string {
+	return []string{http.MethodDelete}
+}
+
+func (h *apiKeyHandler) Handle(r *http.Request) (any, error) {
+	apiKeyId, idErr := uuid.Parse(mux.Vars(r)["apiKeyId"])
+	if idErr != nil {
+		return nil, fmt.Errorf("missing or invalid apiKeyId")
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		return nil, h.service.Revoke(apiKeyId)
+	default:
+		return nil, http.ErrNotSupported
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"tapesonic/http/admin/requests"
+	"tapesonic/http/admin/responses"
+	"tapesonic/http/admin/util"
+	"tapesonic/logic"
+)
+
+type apiKeysHandler struct {
+	service *logic.ApiKeyService
+}
+
+func NewApiKeysHandler(
+	service *logic.ApiKeyService,
+) *apiKeysHandler {
+	return &apiKeysHandler{
+		service: service,
+	}
+}
+
+func (h *apiKeysHandler) Methods() []string {
+	return []string{http.MethodGet, http.MethodPost}
+}
+
+func (h *apiKeysHandler) Handle(r *http.Request) (any, error) {
+	switch r.Method {
+	case http.MethodGet:
+		apiKeys, err := h.service.GetAll()
+		if err != nil {
+			return nil, err
+		}
+
+		return responses.ApiKeysToListDto(apiKeys), nil
+	case http.MethodPost:
+		var apiKeyRequest requests.NewApiKey
+		err := json.NewDecoder(r.Body).Decode(&apiKeyRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		username := apiKeyRequest.Username
+		if username == "" {
+			username = util.GetUsername(r)
+		}
+
+		apiKey, err := h.service.Issue(username, apiKeyRequest.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return responses.IssuedApiKeyToDto(apiKey), nil
+	default:
+		return nil, http.ErrNotSupported
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/admin/responses"
+	"tapesonic/logic"
+)
+
+type nowPlayingHandler struct {
+	nowPlaying *logic.NowPlayingService
+}
+
+func NewNowPlayingHandler(
+	nowPlaying *logic.NowPlayingService,
+) *nowPlayingHandler {
+	return &nowPlayingHandler{
+		nowPlaying: nowPlaying,
+	}
+}
+
+func (h *nowPlayingHandler) Methods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *nowPlayingHandler) Handle(r *http.Request) (any, error) {
+	switch r.Method {
+	case http.MethodGet:
+		songs, err := h.nowPlaying.GetNowPlaying()
+		if err != nil {
+			return nil, err
+		}
+
+		return responses.NowPlayingSongsToNowPlayingRs(songs), nil
+	default:
+		return nil, http.ErrNotSupported
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"tapesonic/http/admin/util"
 	"tapesonic/logic"
 	"time"
 )
@@ -35,7 +36,7 @@ func (h *settingsLastFmAuthHandler) Methods() []string {
 func (h *settingsLastFmAuthHandler) Handle(r *http.Request) (any, error) {
 	switch r.Method {
 	case http.MethodGet:
-		session, err := h.lastfm.GetCurrentSession()
+		session, err := h.lastfm.GetSession(util.GetUsername(r))
 		if err != nil {
 			return nil, err
 		}
@@ -55,7 +56,7 @@ func (h *settingsLastFmAuthHandler) Handle(r *http.Request) (any, error) {
 			return nil, err
 		}
 
-		session, err := h.lastfm.CreateSession(request.Token)
+		session, err := h.lastfm.CreateSession(util.GetUsername(r), request.Token)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"tapesonic/logic"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type sourceGenresHandler struct {
+	sources *logic.SourceService
+}
+
+func NewSourceGenresHandler(
+	sources *logic.SourceService,
+) *sourceGenresHandler {
+	return &sourceGenresHandler{
+		sources: sources,
+	}
+}
+
+func (h *sourceGenresHandler) Methods() []string {
+	return []string{http.MethodGet, http.MethodPut}
+}
+
+func (h *sourceGenresHandler) Handle(r *http.Request) (any, error) {
+	sourceId, idErr := uuid.Parse(mux.Vars(r)["sourceId"])
+	if idErr != nil {
+		return nil, fmt.Errorf("missing or invalid sourceId")
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		source, err := h.sources.GetById(sourceId)
+		if err != nil {
+			return nil, err
+		}
+
+		if source.Genres == nil {
+			return []string{}, nil
+		}
+		return source.Genres, nil
+	case http.MethodPut:
+		var genres []string
+		err := json.NewDecoder(r.Body).Decode(&genres)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.sources.SetGenres(sourceId, genres); err != nil {
+			return nil, err
+		}
+
+		source, err := h.sources.GetById(sourceId)
+		if err != nil {
+			return nil, err
+		}
+
+		return source.Genres, nil
+	default:
+		return nil, http.ErrNotSupported
+	}
+}
@@ -6,7 +6,9 @@ import (
 
 	"tapesonic/http/admin/requests"
 	"tapesonic/http/admin/responses"
+	"tapesonic/http/admin/util"
 	"tapesonic/logic"
+	"tapesonic/storage"
 )
 
 type tapesHandler struct {
@@ -42,6 +44,9 @@ func (h *tapesHandler) Handle(r *http.Request) (any, error) {
 		}
 
 		tape := requests.ModifiedTapeToModel(tapeRequest)
+		if tape.Type == storage.TAPE_TYPE_PLAYLIST {
+			tape.Owner = util.GetUsername(r)
+		}
 
 		tape, tracks, err := h.service.Create(tape)
 		if err != nil {
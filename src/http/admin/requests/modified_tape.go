@@ -17,6 +17,8 @@ type ModifiedTape struct {
 	Artist     string
 	ReleasedAt *time.Time
 
+	Genres []string
+
 	Tracks []ModifiedTapeTrack
 }
 
@@ -37,6 +39,7 @@ func ModifiedTapeToModel(modifiedTape ModifiedTape) storage.Tape {
 		ThumbnailId: modifiedTape.ThumbnailId,
 		Artist:      modifiedTape.Artist,
 		ReleasedAt:  modifiedTape.ReleasedAt,
+		Genres:      modifiedTape.Genres,
 		Tracks:      tapeToTracks,
 	}
 }
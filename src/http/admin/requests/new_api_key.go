@@ -0,0 +1,7 @@
+package requests
+
+type NewApiKey struct {
+	// defaults to the user issuing the key
+	Username string
+	Name     string
+}
@@ -0,0 +1,47 @@
+package responses
+
+import (
+	"tapesonic/logic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ApiKeyRs struct {
+	Id         uuid.UUID
+	Username   string
+	Name       string
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type IssuedApiKeyRs struct {
+	ApiKeyRs
+	// only returned once, only a hash of it is stored
+	Key string
+}
+
+func ApiKeysToListDto(apiKeys []logic.ApiKey) []ApiKeyRs {
+	result := []ApiKeyRs{}
+	for _, apiKey := range apiKeys {
+		result = append(result, ApiKeyToDto(apiKey))
+	}
+	return result
+}
+
+func ApiKeyToDto(apiKey logic.ApiKey) ApiKeyRs {
+	return ApiKeyRs{
+		Id:         apiKey.Id,
+		Username:   apiKey.Username,
+		Name:       apiKey.Name,
+		LastUsedAt: apiKey.LastUsedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+func IssuedApiKeyToDto(apiKey logic.IssuedApiKey) IssuedApiKeyRs {
+	return IssuedApiKeyRs{
+		ApiKeyRs: ApiKeyToDto(apiKey.ApiKey),
+		Key:      apiKey.Key,
+	}
+}
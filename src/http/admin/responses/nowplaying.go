@@ -0,0 +1,32 @@
+package responses
+
+import "tapesonic/logic"
+
+type NowPlayingRs struct {
+	Username   string
+	ClientName string
+	MinutesAgo int
+
+	SongId   string
+	Artist   string
+	Album    string
+	Title    string
+	CoverArt string
+}
+
+func NowPlayingSongsToNowPlayingRs(songs []logic.NowPlayingSong) []NowPlayingRs {
+	result := []NowPlayingRs{}
+	for _, song := range songs {
+		result = append(result, NowPlayingRs{
+			Username:   song.Username,
+			ClientName: song.ClientName,
+			MinutesAgo: song.MinutesAgo(),
+			SongId:     song.Song.Id,
+			Artist:     song.Song.Artist,
+			Album:      song.Song.Album,
+			Title:      song.Song.Title,
+			CoverArt:   song.Song.CoverArt,
+		})
+	}
+	return result
+}
@@ -23,6 +23,8 @@ type FullSourceRs struct {
 
 	ReleaseDate *time.Time
 
+	Genres []string
+
 	ThumbnailId *uuid.UUID
 }
 
@@ -43,6 +45,8 @@ func SourceToFullSourceRs(source storage.Source) FullSourceRs {
 
 		ReleaseDate: source.ReleaseDate,
 
+		Genres: source.Genres,
+
 		ThumbnailId: source.ThumbnailId,
 	}
 }
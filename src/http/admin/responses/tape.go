@@ -17,6 +17,8 @@ type Tape struct {
 	Artist     string
 	ReleasedAt *time.Time
 
+	Genres []string
+
 	Tracks []TrackRs
 }
 
@@ -28,6 +30,7 @@ func TapeToDto(tape storage.Tape, tracks []storage.Track) Tape {
 		ThumbnailId: tape.ThumbnailId,
 		Artist:      tape.Artist,
 		ReleasedAt:  tape.ReleasedAt,
+		Genres:      tape.Genres,
 		Tracks:      TracksToTrackRs(tracks),
 	}
 }
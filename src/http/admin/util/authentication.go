@@ -1,21 +1,49 @@
 package util
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http"
 
-	"tapesonic/config"
+	"tapesonic/logic"
 )
 
-func Authenticated(handler http.HandlerFunc, config *config.TapesonicConfig) http.HandlerFunc {
+// stream-only users can only reach handlers that don't require admin, like their own last.fm settings
+func Authenticated(handler http.HandlerFunc, users *logic.UserService, requireAdmin bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		username, password, ok := r.BasicAuth()
+		if !ok {
+			unauthorized(w)
+			return
+		}
+
+		credentials, err := users.GetCredentials(username)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to get credentials of user %s: %s", username, err.Error()))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if credentials == nil || password != credentials.Password {
+			unauthorized(w)
+			return
+		}
 
-		if !ok || username != config.Username || password != config.Password {
-			w.Header().Add("WWW-Authenticate", "Basic realm=\"master\", charset=\"UTF-8\"")
-			w.WriteHeader(http.StatusUnauthorized)
+		if requireAdmin && !credentials.IsAdmin() {
+			w.WriteHeader(http.StatusForbidden)
 			return
 		}
 
 		handler(w, r)
 	}
 }
+
+func GetUsername(r *http.Request) string {
+	username, _, _ := r.BasicAuth()
+	return username
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Add("WWW-Authenticate", "Basic realm=\"master\", charset=\"UTF-8\"")
+	w.WriteHeader(http.StatusUnauthorized)
+}
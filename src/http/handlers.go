@@ -6,6 +6,8 @@ import (
 
 	"tapesonic/appcontext"
 	"tapesonic/http/admin"
+	"tapesonic/http/radio"
+	"tapesonic/http/share"
 	"tapesonic/http/subsonic"
 
 	"net/http/pprof"
@@ -22,6 +24,14 @@ func GetHandlers(appCtx *appcontext.Context) map[string]http.HandlerFunc {
 		handlers[path] = handler
 	}
 
+	for path, handler := range radio.GetHandlers(appCtx) {
+		handlers[path] = handler
+	}
+
+	for path, handler := range share.GetHandlers(appCtx) {
+		handlers[path] = handler
+	}
+
 	handlers["/assets/"] = http.FileServer(http.Dir(appCtx.Config.WebappDir)).ServeHTTP
 	handlers["/"] = func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, path.Join(appCtx.Config.WebappDir, "index.html"))
@@ -134,6 +134,83 @@ func (c *LastFmClient) Scrobble(sessionKey string, request ScrobbleRq) error {
 	return extractError(res)
 }
 
+func (c *LastFmClient) ArtistGetSimilar(artist string, limit int) (SimilarArtistsWrapper, error) {
+	params := url.Values{}
+	params.Add("artist", artist)
+	params.Add("limit", fmt.Sprint(limit))
+	params.Add("autocorrect", "1")
+
+	var result SimilarArtistsWrapper
+	return result, c.callPublicMethod("artist.getSimilar", params, &result)
+}
+
+func (c *LastFmClient) ArtistGetTopTracks(artist string, limit int) (TopTracksWrapper, error) {
+	params := url.Values{}
+	params.Add("artist", artist)
+	params.Add("limit", fmt.Sprint(limit))
+	params.Add("autocorrect", "1")
+
+	var result TopTracksWrapper
+	return result, c.callPublicMethod("artist.getTopTracks", params, &result)
+}
+
+func (c *LastFmClient) ArtistGetInfo(artist string) (ArtistInfoWrapper, error) {
+	params := url.Values{}
+	params.Add("artist", artist)
+	params.Add("autocorrect", "1")
+
+	var result ArtistInfoWrapper
+	return result, c.callPublicMethod("artist.getInfo", params, &result)
+}
+
+func (c *LastFmClient) TrackGetSimilar(artist string, track string, limit int) (SimilarTracksWrapper, error) {
+	params := url.Values{}
+	params.Add("artist", artist)
+	params.Add("track", track)
+	params.Add("limit", fmt.Sprint(limit))
+	params.Add("autocorrect", "1")
+
+	var result SimilarTracksWrapper
+	return result, c.callPublicMethod("track.getSimilar", params, &result)
+}
+
+// read-only methods which require neither a session nor a signature
+func (c *LastFmClient) callPublicMethod(method string, params url.Values, result any) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	params.Add("method", method)
+	params.Add("api_key", c.apiKey)
+	params.Add("format", "json")
+	req.URL.RawQuery = params.Encode()
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if err = extractError(res); err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	// some errors like unknown artists are reported with http 200
+	var apiError ApiError
+	if err := json.Unmarshal(body, &apiError); err == nil && apiError.Error != 0 {
+		return fmt.Errorf("last.fm error %d: %s", apiError.Error, apiError.Message)
+	}
+
+	return json.Unmarshal(body, result)
+}
+
 func (c *LastFmClient) createSignature(params url.Values) string {
 	sortedKeys := make([]string, len(params))
 	for key := range params {
@@ -47,3 +47,56 @@ type Playlink struct {
 	Affiliate string `json:"affiliate"`
 	Url       string `json:"url"`
 }
+
+type ApiError struct {
+	Error   int    `json:"error"`
+	Message string `json:"message"`
+}
+
+type Image struct {
+	Url  string `json:"#text"`
+	Size string `json:"size"`
+}
+
+type ArtistInfo struct {
+	Name  string  `json:"name"`
+	Mbid  string  `json:"mbid"`
+	Url   string  `json:"url"`
+	Image []Image `json:"image"`
+}
+
+type TrackInfo struct {
+	Name   string     `json:"name"`
+	Mbid   string     `json:"mbid"`
+	Url    string     `json:"url"`
+	Artist ArtistInfo `json:"artist"`
+}
+
+type SimilarArtistsWrapper struct {
+	SimilarArtists struct {
+		Artist []ArtistInfo `json:"artist"`
+	} `json:"similarartists"`
+}
+
+type SimilarTracksWrapper struct {
+	SimilarTracks struct {
+		Track []TrackInfo `json:"track"`
+	} `json:"similartracks"`
+}
+
+type TopTracksWrapper struct {
+	TopTracks struct {
+		Track []TrackInfo `json:"track"`
+	} `json:"toptracks"`
+}
+
+type ArtistInfoWrapper struct {
+	Artist struct {
+		ArtistInfo
+
+		Bio struct {
+			Summary string `json:"summary"`
+			Content string `json:"content"`
+		} `json:"bio"`
+	} `json:"artist"`
+}
@@ -0,0 +1,39 @@
+package radio
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"tapesonic/appcontext"
+
+	"github.com/google/uuid"
+)
+
+// clients play radio stream urls as-is without passing any subsonic credentials,
+// so these are served without authentication; random station ids are the only protection here
+func GetHandlers(appCtx *appcontext.Context) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"/radio/": func(w http.ResponseWriter, r *http.Request) {
+			id, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/radio/"))
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+
+			stream, err := appCtx.RadioService.Listen(r.Context(), id)
+			if err != nil {
+				slog.Warn(fmt.Sprintf("Failed to start listening to radio station id=%s: %s", id, err))
+				http.Error(w, "Failed to start listening to the radio station", http.StatusInternalServerError)
+				return
+			}
+			defer stream.Reader.Close()
+
+			w.Header().Add("Content-Type", stream.MimeType)
+			w.Header().Add("Cache-Control", "no-cache")
+			io.Copy(w, stream.Reader)
+		},
+	}
+}
@@ -0,0 +1,134 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"tapesonic/appcontext"
+	"tapesonic/logic"
+
+	"github.com/google/uuid"
+)
+
+var pageTemplate = template.Must(template.New("share").Funcs(template.FuncMap{
+	"duration": func(seconds int) string {
+		return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ if .Description }}{{ .Description }}{{ else }}Shared by {{ .Username }}{{ end }}</title>
+	<style>
+		body { font-family: sans-serif; max-width: 40em; margin: 2em auto; padding: 0 1em; }
+		audio { width: 100%; }
+		li { padding: 0.3em 0; cursor: pointer; }
+		li.playing { font-weight: bold; }
+		.duration { color: gray; }
+	</style>
+</head>
+<body>
+	<h1>{{ if .Description }}{{ .Description }}{{ else }}Shared by {{ .Username }}{{ end }}</h1>
+	<audio id="player" controls preload="none"></audio>
+	<ol id="tracks">
+		{{ range .Entries }}
+		<li data-src="/share/{{ $.Id }}/stream/{{ .Id }}">{{ .Artist }} - {{ .Title }} <span class="duration">{{ duration .Duration }}</span></li>
+		{{ end }}
+	</ol>
+	<script>
+		const player = document.getElementById("player");
+		const tracks = Array.from(document.querySelectorAll("#tracks li"));
+		let current = -1;
+
+		function play(index) {
+			if (index < 0 || index >= tracks.length) {
+				return;
+			}
+			tracks.forEach((track, i) => track.classList.toggle("playing", i === index));
+			current = index;
+			player.src = tracks[index].dataset.src;
+			player.play();
+		}
+
+		tracks.forEach((track, i) => track.addEventListener("click", () => play(i)));
+		player.addEventListener("ended", () => play(current + 1));
+		player.addEventListener("play", () => { if (current < 0) play(0); });
+	</script>
+</body>
+</html>
+`))
+
+// share links are opened by people without an account, so these are served without authentication;
+// random share ids are the only protection here, same as with radio stations
+func GetHandlers(appCtx *appcontext.Context) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"/share/": func(w http.ResponseWriter, r *http.Request) {
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/share/"), "/")
+
+			id, err := uuid.Parse(parts[0])
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+
+			switch {
+			case len(parts) == 1:
+				servePage(appCtx.ShareService, w, r, id)
+			case len(parts) == 3 && parts[1] == "stream":
+				serveStream(appCtx.ShareService, w, r, id, parts[2])
+			default:
+				http.NotFound(w, r)
+			}
+		},
+	}
+}
+
+func servePage(shares *logic.ShareService, w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	share, err := shares.Visit(id)
+	if err != nil {
+		handleError(w, r, id, err)
+		return
+	}
+
+	w.Header().Add("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, share); err != nil {
+		slog.Warn(fmt.Sprintf("Failed to render share id=%s: %s", id, err))
+	}
+}
+
+func serveStream(shares *logic.ShareService, w http.ResponseWriter, r *http.Request, id uuid.UUID, songId string) {
+	stream, err := shares.Stream(r.Context(), id, songId)
+	if err != nil {
+		handleError(w, r, id, err)
+		return
+	}
+	defer stream.Reader.Close()
+
+	if stream.MimeType != "" {
+		w.Header().Add("Content-Type", stream.MimeType)
+	}
+
+	readSeeker, isSeekable := stream.Reader.(io.ReadSeeker)
+	if isSeekable {
+		http.ServeContent(w, r, "", time.Time{}, readSeeker)
+	} else {
+		io.Copy(w, stream.Reader)
+	}
+}
+
+func handleError(w http.ResponseWriter, r *http.Request, id uuid.UUID, err error) {
+	if errors.Is(err, logic.ErrShareNotFound) || errors.Is(err, logic.ErrShareItemNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+
+	slog.Warn(fmt.Sprintf("Failed to serve share id=%s: %s", id, err))
+	http.Error(w, "Failed to serve the share", http.StatusInternalServerError)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	mimePkg "mime"
 	"net/http"
 	"net/url"
 	"tapesonic/http/subsonic/responses"
@@ -87,6 +88,22 @@ func (c *SubsonicClient) GetRandomSongs(size int, genre string, fromYear *int, t
 	return res.RandomSongs, nil
 }
 
+func (c *SubsonicClient) GetSongsByGenre(genre string, count int, offset int) (*responses.SongsByGenre, error) {
+	res, err := c.doParsedQuery(
+		"/rest/getSongsByGenre",
+		map[string]string{
+			"genre":  genre,
+			"count":  fmt.Sprint(count),
+			"offset": fmt.Sprint(offset),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.SongsByGenre, nil
+}
+
 func (c *SubsonicClient) GetAlbum(id string) (*responses.AlbumId3, error) {
 	res, err := c.doParsedQuery("/rest/getAlbum", map[string]string{"id": id})
 	if err != nil {
@@ -102,6 +119,7 @@ func (c *SubsonicClient) GetAlbumList2(
 	offset int,
 	fromYear *int,
 	toYear *int,
+	genre string,
 ) (*responses.AlbumList2, error) {
 	params := map[string]string{
 		"type":   type_,
@@ -114,6 +132,9 @@ func (c *SubsonicClient) GetAlbumList2(
 	if toYear != nil {
 		params["toYear"] = fmt.Sprint(*toYear)
 	}
+	if genre != "" {
+		params["genre"] = genre
+	}
 
 	res, err := c.doParsedQuery("/rest/getAlbumList2", params)
 	if err != nil {
@@ -141,6 +162,64 @@ func (c *SubsonicClient) GetPlaylists() (*responses.SubsonicPlaylists, error) {
 	return res.Playlists, nil
 }
 
+func (c *SubsonicClient) CreatePlaylist(playlistId string, name string, songIds []string) (*responses.SubsonicPlaylist, error) {
+	params := url.Values{
+		"songId": songIds,
+	}
+	if playlistId != "" {
+		params.Set("playlistId", playlistId)
+	}
+	if name != "" {
+		params.Set("name", name)
+	}
+
+	res, err := c.doParsedMultiValueQuery("/rest/createPlaylist", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Playlist, nil
+}
+
+func (c *SubsonicClient) UpdatePlaylist(playlistId string, name string, songIdsToAdd []string, songIndexesToRemove []int) error {
+	params := url.Values{
+		"playlistId":  []string{playlistId},
+		"songIdToAdd": songIdsToAdd,
+	}
+	if name != "" {
+		params.Set("name", name)
+	}
+	for _, index := range songIndexesToRemove {
+		params.Add("songIndexToRemove", fmt.Sprint(index))
+	}
+
+	_, err := c.doParsedMultiValueQuery("/rest/updatePlaylist", params)
+	return err
+}
+
+func (c *SubsonicClient) DeletePlaylist(playlistId string) error {
+	_, err := c.doParsedQuery("/rest/deletePlaylist", map[string]string{"id": playlistId})
+	return err
+}
+
+func (c *SubsonicClient) GetGenres() (*responses.Genres, error) {
+	res, err := c.doParsedQuery("/rest/getGenres", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Genres, nil
+}
+
+func (c *SubsonicClient) GetArtists() (*responses.Artists, error) {
+	res, err := c.doParsedQuery("/rest/getArtists", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Artists, nil
+}
+
 func (c *SubsonicClient) GetArtist(id string) (*responses.Artist, error) {
 	res, err := c.doParsedQuery("/rest/getArtist", map[string]string{"id": id})
 	if err != nil {
@@ -159,12 +238,196 @@ func (c *SubsonicClient) Scrobble(id string, time_ time.Time, submission bool) e
 	return err
 }
 
-func (c *SubsonicClient) GetCoverArt(id string) (mime string, reader io.ReadCloser, err error) {
-	return c.doRawQuery("/rest/getCoverArt", map[string]string{"id": id})
+func (c *SubsonicClient) Star(ids []string, albumIds []string, artistIds []string) error {
+	_, err := c.doParsedMultiValueQuery("/rest/star", url.Values{
+		"id":       ids,
+		"albumId":  albumIds,
+		"artistId": artistIds,
+	})
+	return err
+}
+
+func (c *SubsonicClient) Unstar(ids []string, albumIds []string, artistIds []string) error {
+	_, err := c.doParsedMultiValueQuery("/rest/unstar", url.Values{
+		"id":       ids,
+		"albumId":  albumIds,
+		"artistId": artistIds,
+	})
+	return err
+}
+
+func (c *SubsonicClient) SetRating(id string, rating int) error {
+	_, err := c.doParsedQuery("/rest/setRating", map[string]string{
+		"id":     id,
+		"rating": fmt.Sprint(rating),
+	})
+	return err
+}
+
+func (c *SubsonicClient) CreateBookmark(id string, positionMs int64, comment string) error {
+	_, err := c.doParsedQuery("/rest/createBookmark", map[string]string{
+		"id":       id,
+		"position": fmt.Sprint(positionMs),
+		"comment":  comment,
+	})
+	return err
+}
+
+func (c *SubsonicClient) GetBookmarks() (*responses.Bookmarks, error) {
+	res, err := c.doParsedQuery("/rest/getBookmarks", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Bookmarks, nil
+}
+
+func (c *SubsonicClient) DeleteBookmark(id string) error {
+	_, err := c.doParsedQuery("/rest/deleteBookmark", map[string]string{"id": id})
+	return err
+}
+
+func (c *SubsonicClient) GetStarred2() (*responses.Starred2, error) {
+	res, err := c.doParsedQuery("/rest/getStarred2", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Starred2, nil
+}
+
+func (c *SubsonicClient) GetPodcasts(id string, includeEpisodes bool) (*responses.Podcasts, error) {
+	params := map[string]string{"includeEpisodes": fmt.Sprint(includeEpisodes)}
+	if id != "" {
+		params["id"] = id
+	}
+
+	res, err := c.doParsedQuery("/rest/getPodcasts", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Podcasts, nil
+}
+
+func (c *SubsonicClient) GetNewestPodcasts(count int) (*responses.NewestPodcasts, error) {
+	res, err := c.doParsedQuery("/rest/getNewestPodcasts", map[string]string{"count": fmt.Sprint(count)})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.NewestPodcasts, nil
+}
+
+func (c *SubsonicClient) CreatePodcastChannel(url string) error {
+	_, err := c.doParsedQuery("/rest/createPodcastChannel", map[string]string{"url": url})
+	return err
+}
+
+func (c *SubsonicClient) DeletePodcastChannel(id string) error {
+	_, err := c.doParsedQuery("/rest/deletePodcastChannel", map[string]string{"id": id})
+	return err
+}
+
+func (c *SubsonicClient) RefreshPodcasts() error {
+	_, err := c.doParsedQuery("/rest/refreshPodcasts", map[string]string{})
+	return err
 }
 
-func (c *SubsonicClient) Stream(id string) (mime string, reader io.ReadCloser, err error) {
-	return c.doRawQuery("/rest/stream", map[string]string{"id": id})
+func (c *SubsonicClient) DownloadPodcastEpisode(id string) error {
+	_, err := c.doParsedQuery("/rest/downloadPodcastEpisode", map[string]string{"id": id})
+	return err
+}
+
+func (c *SubsonicClient) GetLyrics(artist string, title string) (*responses.Lyrics, error) {
+	res, err := c.doParsedQuery("/rest/getLyrics", map[string]string{"artist": artist, "title": title})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Lyrics, nil
+}
+
+func (c *SubsonicClient) GetLyricsBySongId(id string) (*responses.LyricsList, error) {
+	res, err := c.doParsedQuery("/rest/getLyricsBySongId", map[string]string{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.LyricsList, nil
+}
+
+func (c *SubsonicClient) GetMusicFolders() (*responses.MusicFolders, error) {
+	res, err := c.doParsedQuery("/rest/getMusicFolders", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.MusicFolders, nil
+}
+
+func (c *SubsonicClient) GetIndexes(musicFolderId string) (*responses.Indexes, error) {
+	params := map[string]string{}
+	if musicFolderId != "" {
+		params["musicFolderId"] = musicFolderId
+	}
+
+	res, err := c.doParsedQuery("/rest/getIndexes", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Indexes, nil
+}
+
+func (c *SubsonicClient) GetMusicDirectory(id string) (*responses.Directory, error) {
+	res, err := c.doParsedQuery("/rest/getMusicDirectory", map[string]string{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Directory, nil
+}
+
+func (c *SubsonicClient) GetCoverArt(id string, size int) (mime string, reader io.ReadCloser, err error) {
+	params := map[string]string{"id": id}
+	if size > 0 {
+		params["size"] = fmt.Sprint(size)
+	}
+
+	return c.doRawQuery("/rest/getCoverArt", params)
+}
+
+func (c *SubsonicClient) Stream(id string, format string, maxBitRate int, timeOffsetSeconds int64, estimateContentLength bool) (mime string, reader io.ReadCloser, err error) {
+	params := map[string]string{"id": id}
+	if format != "" {
+		params["format"] = format
+	}
+	if maxBitRate > 0 {
+		params["maxBitRate"] = fmt.Sprint(maxBitRate)
+	}
+	if timeOffsetSeconds > 0 {
+		params["timeOffset"] = fmt.Sprint(timeOffsetSeconds)
+	}
+	if estimateContentLength {
+		params["estimateContentLength"] = "true"
+	}
+
+	return c.doRawQuery("/rest/stream", params)
+}
+
+// the file name is taken from the upstream Content-Disposition header if there is one
+func (c *SubsonicClient) Download(id string) (mime string, fileName string, reader io.ReadCloser, err error) {
+	res, err := c.doRawResponseQuery("/rest/download", toMultiValueParams(map[string]string{"id": id}))
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if _, params, err := mimePkg.ParseMediaType(res.Header.Get("Content-Disposition")); err == nil {
+		fileName = params["filename"]
+	}
+
+	return res.Header.Get("Content-Type"), fileName, res.Body, nil
 }
 
 func (c *SubsonicClient) GetLicense() (*responses.License, error) {
@@ -177,7 +440,11 @@ func (c *SubsonicClient) GetLicense() (*responses.License, error) {
 }
 
 func (c *SubsonicClient) doParsedQuery(path string, params map[string]string) (*responses.SubsonicResponse, error) {
-	_, body, err := c.doRawQuery(path, params)
+	return c.doParsedMultiValueQuery(path, toMultiValueParams(params))
+}
+
+func (c *SubsonicClient) doParsedMultiValueQuery(path string, params url.Values) (*responses.SubsonicResponse, error) {
+	_, body, err := c.doRawMultiValueQuery(path, params)
 	if err != nil {
 		return nil, err
 	}
@@ -199,23 +466,41 @@ func (c *SubsonicClient) doParsedQuery(path string, params map[string]string) (*
 }
 
 func (c *SubsonicClient) doRawQuery(path string, params map[string]string) (string, io.ReadCloser, error) {
-	req, err := http.NewRequest("GET", c.baseUrl+path, nil)
+	return c.doRawMultiValueQuery(path, toMultiValueParams(params))
+}
+
+func (c *SubsonicClient) doRawMultiValueQuery(path string, params url.Values) (string, io.ReadCloser, error) {
+	res, err := c.doRawResponseQuery(path, params)
 	if err != nil {
 		return "", nil, err
 	}
 
+	return res.Header.Get("Content-Type"), res.Body, nil
+}
+
+func (c *SubsonicClient) doRawResponseQuery(path string, params url.Values) (*http.Response, error) {
+	req, err := http.NewRequest("GET", c.baseUrl+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	query := prepareQueryParams(*req.URL, c.username, c.password)
-	for paramName, paramValue := range params {
-		query.Add(paramName, paramValue)
+	for paramName, paramValues := range params {
+		for _, paramValue := range paramValues {
+			query.Add(paramName, paramValue)
+		}
 	}
 	req.URL.RawQuery = query.Encode()
 
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", nil, err
-	}
+	return http.DefaultClient.Do(req)
+}
 
-	return res.Header.Get("Content-Type"), res.Body, nil
+func toMultiValueParams(params map[string]string) url.Values {
+	result := url.Values{}
+	for paramName, paramValue := range params {
+		result.Set(paramName, paramValue)
+	}
+	return result
 }
 
 func prepareQueryParams(url url.URL, username string, password string) url.Values {
@@ -7,39 +7,102 @@ import (
 	"tapesonic/appcontext"
 	"tapesonic/http/subsonic/handlers"
 	"tapesonic/http/subsonic/util"
+	"tapesonic/logic"
 )
 
 func GetHandlers(appCtx *appcontext.Context) map[string]http.HandlerFunc {
 	rawHandlers := map[string]http.HandlerFunc{
 		"/ping": util.AsHandlerFunc(handlers.Ping),
 
-		"/getAlbumList2":            util.AsHandlerFunc(handlers.NewGetAlbumList2Handler(appCtx.SubsonicService).Handle),
-		"/getAlbum":                 util.AsHandlerFunc(handlers.NewGetAlbumHandler(appCtx.SubsonicService).Handle),
-		"/getArtists":               util.AsHandlerFunc(handlers.NewGetArtistsHandler().Handle),
-		"/getArtist":                util.AsHandlerFunc(handlers.NewGetArtistHandler(appCtx.SubsonicService).Handle),
-		"/getGenres":                util.AsHandlerFunc(handlers.NewGetGenresHandler().Handle),
-		"/getInternetRadioStations": util.AsHandlerFunc(handlers.NewGetInternetRadioStationsHandler().Handle),
-		"/getLicense":               util.AsHandlerFunc(handlers.NewGetLicenseHandler(appCtx.SubsonicService).Handle),
-		"/getMusicFolders":          util.AsHandlerFunc(handlers.NewGetMusicFoldersHandler().Handle),
-		"/getNewestPodcasts":        util.AsHandlerFunc(handlers.NewGetNewestPodcastsHandler().Handle),
-		"/getPlaylists":             util.AsHandlerFunc(handlers.NewGetPlaylistsHandler(appCtx.SubsonicService).Handle),
-		"/getPlaylist":              util.AsHandlerFunc(handlers.NewGetPlaylistHandler(appCtx.SubsonicService).Handle),
-		"/getPodcasts":              util.AsHandlerFunc(handlers.NewGetPodcastsHandler().Handle),
-		"/getRandomSongs":           util.AsHandlerFunc(handlers.NewGetRandomSongsHandler(appCtx.SubsonicService).Handle),
-		"/getScanStatus":            util.AsHandlerFunc(handlers.NewGetScanStatusHandler().Handle),
-		"/getSong":                  util.AsHandlerFunc(handlers.NewGetSongHandler(appCtx.SubsonicService).Handle),
-		"/getStarred2":              util.AsHandlerFunc(handlers.NewGetStarred2Handler().Handle),
-		"/search3":                  util.AsHandlerFunc(handlers.NewSearch3Handler(appCtx.SubsonicService).Handle),
-
-		"/scrobble": util.AsHandlerFunc(handlers.NewScrobbleHandler(appCtx.SubsonicService).Handle),
-
-		"/stream":      util.AsRawHandlerFunc(handlers.NewStreamHandler(appCtx.SubsonicService).Handle),
+		"/getAlbumList":               util.AsHandlerFunc(handlers.NewGetAlbumListHandler(appCtx.SubsonicService).Handle),
+		"/getAlbumList2":              util.AsHandlerFunc(handlers.NewGetAlbumList2Handler(appCtx.SubsonicService).Handle),
+		"/getAlbum":                   util.AsHandlerFunc(handlers.NewGetAlbumHandler(appCtx.SubsonicService).Handle),
+		"/getArtists":                 util.AsHandlerFunc(handlers.NewGetArtistsHandler(appCtx.SubsonicService).Handle),
+		"/getArtist":                  util.AsHandlerFunc(handlers.NewGetArtistHandler(appCtx.SubsonicService).Handle),
+		"/getArtistInfo":              util.AsHandlerFunc(handlers.NewGetArtistInfoHandler(appCtx.LastFmDiscoveryService).Handle),
+		"/getArtistInfo2":             util.AsHandlerFunc(handlers.NewGetArtistInfo2Handler(appCtx.LastFmDiscoveryService).Handle),
+		"/getGenres":                  util.AsHandlerFunc(handlers.NewGetGenresHandler(appCtx.SubsonicService).Handle),
+		"/getIndexes":                 util.AsHandlerFunc(handlers.NewGetIndexesHandler(appCtx.SubsonicService).Handle),
+		"/getInternetRadioStations":   util.AsHandlerFunc(handlers.NewGetInternetRadioStationsHandler(appCtx.RadioService).Handle),
+		"/createInternetRadioStation": util.AdminOnly(util.AsHandlerFunc(handlers.NewCreateInternetRadioStationHandler(appCtx.RadioService).Handle)),
+		"/updateInternetRadioStation": util.AdminOnly(util.AsHandlerFunc(handlers.NewUpdateInternetRadioStationHandler(appCtx.RadioService).Handle)),
+		"/deleteInternetRadioStation": util.AdminOnly(util.AsHandlerFunc(handlers.NewDeleteInternetRadioStationHandler(appCtx.RadioService).Handle)),
+		"/getLicense":                 util.AsHandlerFunc(handlers.NewGetLicenseHandler(appCtx.SubsonicService).Handle),
+		"/getLyrics":                  util.AsHandlerFunc(handlers.NewGetLyricsHandler(appCtx.SubsonicService).Handle),
+		"/getLyricsBySongId":          util.AsHandlerFunc(handlers.NewGetLyricsBySongIdHandler(appCtx.SubsonicService).Handle),
+		"/getMusicFolders":            util.AsHandlerFunc(handlers.NewGetMusicFoldersHandler(appCtx.SubsonicService).Handle),
+		"/getMusicDirectory":          util.AsHandlerFunc(handlers.NewGetMusicDirectoryHandler(appCtx.SubsonicService).Handle),
+		"/getNowPlaying":              util.AsHandlerFunc(handlers.NewGetNowPlayingHandler(appCtx.NowPlayingService).Handle),
+		"/getNewestPodcasts":          util.AsHandlerFunc(handlers.NewGetNewestPodcastsHandler(appCtx.SubsonicService).Handle),
+		"/getPlayQueue":               util.AsHandlerFunc(handlers.NewGetPlayQueueHandler(appCtx.PlayQueueService).Handle),
+		"/savePlayQueue":              util.AsHandlerFunc(handlers.NewSavePlayQueueHandler(appCtx.PlayQueueService).Handle),
+		"/getPlaylists":               util.AsHandlerFunc(handlers.NewGetPlaylistsHandler(appCtx.SubsonicService).Handle),
+		"/getPlaylist":                util.AsHandlerFunc(handlers.NewGetPlaylistHandler(appCtx.SubsonicService).Handle),
+		"/createPlaylist":             util.AsHandlerFunc(handlers.NewCreatePlaylistHandler(appCtx.SubsonicService).Handle),
+		"/updatePlaylist":             util.AsHandlerFunc(handlers.NewUpdatePlaylistHandler(appCtx.SubsonicService).Handle),
+		"/deletePlaylist":             util.AsHandlerFunc(handlers.NewDeletePlaylistHandler(appCtx.SubsonicService).Handle),
+		"/getPodcasts":                util.AsHandlerFunc(handlers.NewGetPodcastsHandler(appCtx.SubsonicService).Handle),
+		"/createPodcastChannel":       util.AdminOnly(util.AsHandlerFunc(handlers.NewCreatePodcastChannelHandler(appCtx.SubsonicService).Handle)),
+		"/deletePodcastChannel":       util.AdminOnly(util.AsHandlerFunc(handlers.NewDeletePodcastChannelHandler(appCtx.SubsonicService).Handle)),
+		"/refreshPodcasts":            util.AdminOnly(util.AsHandlerFunc(handlers.NewRefreshPodcastsHandler(appCtx.SubsonicService).Handle)),
+		"/downloadPodcastEpisode":     util.AdminOnly(util.AsHandlerFunc(handlers.NewDownloadPodcastEpisodeHandler(appCtx.SubsonicService).Handle)),
+		"/getRandomSongs":             util.AsHandlerFunc(handlers.NewGetRandomSongsHandler(appCtx.SubsonicService).Handle),
+		"/getScanStatus":              util.AsHandlerFunc(handlers.NewGetScanStatusHandler(appCtx.SyncLibraryHandler).Handle),
+		"/startScan":                  util.AdminOnly(util.AsHandlerFunc(handlers.NewStartScanHandler(appCtx.SyncLibraryHandler).Handle)),
+		"/getSimilarSongs":            util.AsHandlerFunc(handlers.NewGetSimilarSongsHandler(appCtx.LastFmDiscoveryService).Handle),
+		"/getSimilarSongs2":           util.AsHandlerFunc(handlers.NewGetSimilarSongs2Handler(appCtx.LastFmDiscoveryService).Handle),
+		"/getSong":                    util.AsHandlerFunc(handlers.NewGetSongHandler(appCtx.SubsonicService).Handle),
+		"/getSongsByGenre":            util.AsHandlerFunc(handlers.NewGetSongsByGenreHandler(appCtx.SubsonicService).Handle),
+		"/getStarred2":                util.AsHandlerFunc(handlers.NewGetStarred2Handler(appCtx.SubsonicService).Handle),
+		"/getTopSongs":                util.AsHandlerFunc(handlers.NewGetTopSongsHandler(appCtx.LastFmDiscoveryService).Handle),
+		"/search2":                    util.AsHandlerFunc(handlers.NewSearch2Handler(appCtx.SubsonicService).Handle),
+		"/search3":                    util.AsHandlerFunc(handlers.NewSearch3Handler(appCtx.SubsonicService).Handle),
+
+		"/getShares":   util.AsHandlerFunc(handlers.NewGetSharesHandler(appCtx.ShareService).Handle),
+		"/createShare": util.AdminOnly(util.AsHandlerFunc(handlers.NewCreateShareHandler(appCtx.ShareService).Handle)),
+		"/updateShare": util.AdminOnly(util.AsHandlerFunc(handlers.NewUpdateShareHandler(appCtx.ShareService).Handle)),
+		"/deleteShare": util.AdminOnly(util.AsHandlerFunc(handlers.NewDeleteShareHandler(appCtx.ShareService).Handle)),
+
+		"/getUser":    util.AsHandlerFunc(handlers.NewGetUserHandler(appCtx.UserService).Handle),
+		"/getUsers":   util.AdminOnly(util.AsHandlerFunc(handlers.NewGetUsersHandler(appCtx.UserService).Handle)),
+		"/createUser": util.AdminOnly(util.AsHandlerFunc(handlers.NewCreateUserHandler(appCtx.UserService).Handle)),
+		"/updateUser": util.AdminOnly(util.AsHandlerFunc(handlers.NewUpdateUserHandler(appCtx.UserService).Handle)),
+		"/deleteUser": util.AdminOnly(util.AsHandlerFunc(handlers.NewDeleteUserHandler(appCtx.UserService).Handle)),
+
+		"/getBookmarks":   util.AsHandlerFunc(handlers.NewGetBookmarksHandler(appCtx.SubsonicService).Handle),
+		"/createBookmark": util.AsHandlerFunc(handlers.NewCreateBookmarkHandler(appCtx.SubsonicService).Handle),
+		"/deleteBookmark": util.AsHandlerFunc(handlers.NewDeleteBookmarkHandler(appCtx.SubsonicService).Handle),
+
+		"/scrobble":  util.AsHandlerFunc(handlers.NewScrobbleHandler(appCtx.SubsonicService, appCtx.NowPlayingService).Handle),
+		"/star":      util.AsHandlerFunc(handlers.NewStarHandler(appCtx.SubsonicService).Handle),
+		"/unstar":    util.AsHandlerFunc(handlers.NewUnstarHandler(appCtx.SubsonicService).Handle),
+		"/setRating": util.AsHandlerFunc(handlers.NewSetRatingHandler(appCtx.SubsonicService).Handle),
+
+		"/stream":      util.AsRawHandlerFunc(handlers.NewStreamHandler(appCtx.SubsonicService, appCtx.Config.TranscodingProfiles).Handle),
+		"/download":    util.AdminOnly(util.AsRawHandlerFunc(handlers.NewDownloadHandler(appCtx.SubsonicService).Handle)),
 		"/getCoverArt": util.AsRawHandlerFunc(handlers.NewGetCoverArtHandler(appCtx.SubsonicService).Handle),
+		"/hls.m3u8":    util.AsRawHandlerFunc(handlers.NewHlsHandler(appCtx.SubsonicService, appCtx.Config.TranscodingProfiles).Handle),
+		"/hls.ts":      util.AsRawHandlerFunc(handlers.NewHlsSegmentHandler(appCtx.SubsonicService).Handle),
+	}
+
+	// OpenSubsonic requires these to be accessible without authentication
+	publicHandlers := map[string]http.HandlerFunc{
+		"/getOpenSubsonicExtensions": util.AsHandlerFunc(handlers.GetOpenSubsonicExtensions),
+	}
+
+	users := &userProvider{
+		users:   appCtx.UserService,
+		apiKeys: appCtx.ApiKeyService,
 	}
 
 	resultHandlers := map[string]http.HandlerFunc{}
 	for path, handler := range rawHandlers {
-		wrappedHandler := util.Logged(util.Authenticated(handler, appCtx.Config))
+		wrappedHandler := util.WithFormParams(util.Logged(util.Authenticated(handler, users, appCtx.Config.AllowPasswordAuth)))
+		resultHandlers["/rest"+path] = wrappedHandler
+		resultHandlers["/rest"+path+".view"] = wrappedHandler
+	}
+	for path, handler := range publicHandlers {
+		wrappedHandler := util.WithFormParams(util.Logged(handler))
 		resultHandlers["/rest"+path] = wrappedHandler
 		resultHandlers["/rest"+path+".view"] = wrappedHandler
 	}
@@ -53,3 +116,38 @@ func GetHandlers(appCtx *appcontext.Context) map[string]http.HandlerFunc {
 
 	return resultHandlers
 }
+
+type userProvider struct {
+	users   *logic.UserService
+	apiKeys *logic.ApiKeyService
+}
+
+func (p *userProvider) FindUser(username string) (*util.User, error) {
+	credentials, err := p.users.GetCredentials(username)
+	if err != nil || credentials == nil {
+		return nil, err
+	}
+
+	return &util.User{
+		Username: credentials.Username,
+		Password: credentials.Password,
+		IsAdmin:  credentials.IsAdmin(),
+	}, nil
+}
+
+func (p *userProvider) FindUserByApiKey(apiKey string) (*util.User, error) {
+	username, err := p.apiKeys.FindUsername(apiKey)
+	if err != nil || username == "" {
+		return nil, err
+	}
+
+	user, err := p.users.Get(username)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	return &util.User{
+		Username: user.Username,
+		IsAdmin:  user.IsAdmin(),
+	}, nil
+}
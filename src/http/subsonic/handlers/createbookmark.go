@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type createBookmarkHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewCreateBookmarkHandler(subsonic logic.SubsonicService) *createBookmarkHandler {
+	return &createBookmarkHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *createBookmarkHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	query := r.URL.Query()
+
+	id := query.Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	position := util.StringToInt64OrNull(query.Get("position"))
+	if position == nil {
+		return responses.NewParameterMissingResponse("position"), nil
+	}
+
+	return responses.NewOkResponse(), h.subsonic.CreateBookmark(subsonicUtil.GetUsername(r), id, *position, query.Get("comment"))
+}
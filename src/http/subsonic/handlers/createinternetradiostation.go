@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type createInternetRadioStationHandler struct {
+	radio *logic.RadioService
+}
+
+func NewCreateInternetRadioStationHandler(radio *logic.RadioService) *createInternetRadioStationHandler {
+	return &createInternetRadioStationHandler{
+		radio: radio,
+	}
+}
+
+func (h *createInternetRadioStationHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	streamUrl := r.URL.Query().Get("streamUrl")
+	if streamUrl == "" {
+		return responses.NewParameterMissingResponse("streamUrl"), nil
+	}
+
+	name := r.URL.Query().Get("name")
+	homepageUrl := r.URL.Query().Get("homepageUrl")
+
+	_, err := h.radio.CreateStation(r.Context(), streamUrl, name, homepageUrl)
+	if err != nil {
+		if errors.Is(err, logic.ErrNotLiveStream) {
+			return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, err.Error()), nil
+		}
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type createPlaylistHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewCreatePlaylistHandler(subsonic logic.SubsonicService) *createPlaylistHandler {
+	return &createPlaylistHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *createPlaylistHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	// playlistId means that the existing playlist has to be overwritten
+	playlistId := r.URL.Query().Get("playlistId")
+	name := r.URL.Query().Get("name")
+	if playlistId == "" && name == "" {
+		return responses.NewParameterMissingResponse("name"), nil
+	}
+
+	songIds := r.URL.Query()["songId"]
+
+	playlist, err := h.subsonic.CreatePlaylist(subsonicUtil.GetUsername(r), playlistId, name, songIds)
+	if err != nil {
+		if errors.Is(err, logic.ErrReadOnlyPlaylist) {
+			return responses.NewNotAuthorizedResponse(err.Error()), nil
+		}
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.Playlist = playlist
+	return response, nil
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type createPodcastChannelHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewCreatePodcastChannelHandler(subsonic logic.SubsonicService) *createPodcastChannelHandler {
+	return &createPodcastChannelHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *createPodcastChannelHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	url := r.URL.Query().Get("url")
+	if url == "" {
+		return responses.NewParameterMissingResponse("url"), nil
+	}
+
+	if err := h.subsonic.CreatePodcastChannel(url); err != nil {
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
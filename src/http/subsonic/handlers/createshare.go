@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type createShareHandler struct {
+	shares *logic.ShareService
+}
+
+func NewCreateShareHandler(shares *logic.ShareService) *createShareHandler {
+	return &createShareHandler{
+		shares: shares,
+	}
+}
+
+func (h *createShareHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	query := r.URL.Query()
+
+	ids := query["id"]
+	if len(ids) == 0 {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	share, err := h.shares.Create(
+		subsonicUtil.GetUsername(r),
+		ids,
+		query.Get("description"),
+		parseShareExpiry(query.Get("expires")),
+	)
+	if err != nil {
+		if errors.Is(err, logic.ErrShareItemUnsupported) {
+			return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, err.Error()), nil
+		}
+		if errors.Is(err, logic.ErrShareItemNotFound) {
+			return responses.NewNotFoundResponse("shared item"), nil
+		}
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.Shares = responses.NewShares([]responses.Share{toShareResponse(r, share)})
+	return response, nil
+}
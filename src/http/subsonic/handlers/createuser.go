@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/storage"
+)
+
+type createUserHandler struct {
+	users *logic.UserService
+}
+
+func NewCreateUserHandler(users *logic.UserService) *createUserHandler {
+	return &createUserHandler{
+		users: users,
+	}
+}
+
+func (h *createUserHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		return responses.NewParameterMissingResponse("username"), nil
+	}
+
+	rawPassword := r.URL.Query().Get("password")
+	if rawPassword == "" {
+		return responses.NewParameterMissingResponse("password"), nil
+	}
+	password, err := subsonicUtil.DecodePassword(rawPassword)
+	if err != nil {
+		return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, "Invalid password encoding"), nil
+	}
+
+	role := storage.USER_ROLE_STREAM
+	if r.URL.Query().Get("adminRole") == "true" {
+		role = storage.USER_ROLE_ADMIN
+	}
+
+	_, err = h.users.Create(username, password, r.URL.Query().Get("email"), role)
+	if err != nil {
+		if errors.Is(err, logic.ErrUserAlreadyExists) {
+			return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, err.Error()), nil
+		}
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type deleteBookmarkHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewDeleteBookmarkHandler(subsonic logic.SubsonicService) *deleteBookmarkHandler {
+	return &deleteBookmarkHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *deleteBookmarkHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	return responses.NewOkResponse(), h.subsonic.DeleteBookmark(subsonicUtil.GetUsername(r), id)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+
+	"github.com/google/uuid"
+)
+
+type deleteInternetRadioStationHandler struct {
+	radio *logic.RadioService
+}
+
+func NewDeleteInternetRadioStationHandler(radio *logic.RadioService) *deleteInternetRadioStationHandler {
+	return &deleteInternetRadioStationHandler{
+		radio: radio,
+	}
+}
+
+func (h *deleteInternetRadioStationHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	rawId := r.URL.Query().Get("id")
+	if rawId == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		return responses.NewNotFoundResponse("radio station"), nil
+	}
+
+	if err := h.radio.DeleteStation(id); err != nil {
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
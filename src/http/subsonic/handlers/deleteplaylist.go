@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type deletePlaylistHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewDeletePlaylistHandler(subsonic logic.SubsonicService) *deletePlaylistHandler {
+	return &deletePlaylistHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *deletePlaylistHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	err := h.subsonic.DeletePlaylist(subsonicUtil.GetUsername(r), id)
+	if err != nil {
+		if errors.Is(err, logic.ErrReadOnlyPlaylist) {
+			return responses.NewNotAuthorizedResponse(err.Error()), nil
+		}
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
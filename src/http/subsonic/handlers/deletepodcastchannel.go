@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type deletePodcastChannelHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewDeletePodcastChannelHandler(subsonic logic.SubsonicService) *deletePodcastChannelHandler {
+	return &deletePodcastChannelHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *deletePodcastChannelHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	if err := h.subsonic.DeletePodcastChannel(id); err != nil {
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+
+	"github.com/google/uuid"
+)
+
+type deleteShareHandler struct {
+	shares *logic.ShareService
+}
+
+func NewDeleteShareHandler(shares *logic.ShareService) *deleteShareHandler {
+	return &deleteShareHandler{
+		shares: shares,
+	}
+}
+
+func (h *deleteShareHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	rawId := r.URL.Query().Get("id")
+	if rawId == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		return responses.NewNotFoundResponse("share"), nil
+	}
+
+	if err := h.shares.Delete(subsonicUtil.GetUsername(r), id); err != nil {
+		if errors.Is(err, logic.ErrShareNotFound) {
+			return responses.NewNotFoundResponse("share"), nil
+		}
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type deleteUserHandler struct {
+	users *logic.UserService
+}
+
+func NewDeleteUserHandler(users *logic.UserService) *deleteUserHandler {
+	return &deleteUserHandler{
+		users: users,
+	}
+}
+
+func (h *deleteUserHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		return responses.NewParameterMissingResponse("username"), nil
+	}
+
+	if username == subsonicUtil.GetUsername(r) {
+		return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, "Users can't delete themselves"), nil
+	}
+
+	user, err := h.users.Get(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return responses.NewNotFoundResponse("User"), nil
+	}
+
+	if err := h.users.Delete(username); err != nil {
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type downloadHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewDownloadHandler(
+	subsonic logic.SubsonicService,
+) *downloadHandler {
+	return &downloadHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *downloadHandler) Handle(w http.ResponseWriter, r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	stream, err := h.subsonic.Download(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer stream.Reader.Close()
+
+	if stream.MimeType != "" {
+		w.Header().Add("Content-Type", stream.MimeType)
+	}
+	if stream.FileName != "" {
+		w.Header().Add("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": stream.FileName}))
+	}
+
+	readSeeker, isSeekable := stream.Reader.(io.ReadSeeker)
+	if isSeekable {
+		http.ServeContent(w, r, stream.FileName, time.Time{}, readSeeker)
+	} else {
+		io.Copy(w, stream.Reader)
+	}
+
+	return nil, nil
+}
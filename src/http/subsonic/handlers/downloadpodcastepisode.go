@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type downloadPodcastEpisodeHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewDownloadPodcastEpisodeHandler(subsonic logic.SubsonicService) *downloadPodcastEpisodeHandler {
+	return &downloadPodcastEpisodeHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *downloadPodcastEpisodeHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	if err := h.subsonic.DownloadPodcastEpisode(id); err != nil {
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
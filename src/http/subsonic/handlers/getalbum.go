@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 )
 
@@ -25,7 +26,7 @@ func (h *getAlbumHandler) Handle(r *http.Request) (*responses.SubsonicResponse,
 		return responses.NewParameterMissingResponse("id"), nil
 	}
 
-	album, err := h.subsonic.GetAlbum(id)
+	album, err := h.subsonic.GetAlbum(subsonicUtil.GetUsername(r), id)
 	if err != nil {
 		return nil, err
 	}
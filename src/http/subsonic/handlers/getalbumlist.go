@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getAlbumListHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewGetAlbumListHandler(subsonic logic.SubsonicService) *getAlbumListHandler {
+	return &getAlbumListHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *getAlbumListHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	listType := r.URL.Query().Get("type")
+	if listType == "" {
+		return responses.NewParameterMissingResponse("type"), nil
+	}
+
+	size := max(util.StringToIntOrDefault(r.URL.Query().Get("size"), 10), 0)
+	offset := max(util.StringToIntOrDefault(r.URL.Query().Get("offset"), 0), 0)
+	fromYear := util.StringToIntOrNull(r.URL.Query().Get("fromYear"))
+	toYear := util.StringToIntOrNull(r.URL.Query().Get("toYear"))
+	genre := r.URL.Query().Get("genre")
+
+	albumList2, err := h.subsonic.GetAlbumList2(subsonicUtil.GetUsername(r), listType, size, offset, fromYear, toYear, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := []responses.SubsonicChild{}
+	for _, album := range albumList2.Album {
+		albums = append(albums, logic.NewAlbumChild(album))
+	}
+
+	response := responses.NewOkResponse()
+	response.AlbumList = responses.NewAlbumList(albums)
+	return response, nil
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 	"tapesonic/util"
 )
@@ -24,12 +25,13 @@ func (h *getAlbumList2Handler) Handle(r *http.Request) (*responses.SubsonicRespo
 		return responses.NewParameterMissingResponse("type"), nil
 	}
 
-	size := util.StringToIntOrDefault(r.URL.Query().Get("size"), 10)
-	offset := util.StringToIntOrDefault(r.URL.Query().Get("offset"), 0)
+	size := max(util.StringToIntOrDefault(r.URL.Query().Get("size"), 10), 0)
+	offset := max(util.StringToIntOrDefault(r.URL.Query().Get("offset"), 0), 0)
 	fromYear := util.StringToIntOrNull(r.URL.Query().Get("fromYear"))
 	toYear := util.StringToIntOrNull(r.URL.Query().Get("toYear"))
+	genre := r.URL.Query().Get("genre")
 
-	albums, err := h.subsonic.GetAlbumList2(listType, size, offset, fromYear, toYear)
+	albums, err := h.subsonic.GetAlbumList2(subsonicUtil.GetUsername(r), listType, size, offset, fromYear, toYear, genre)
 	if err != nil {
 		return nil, err
 	}
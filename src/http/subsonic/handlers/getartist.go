@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 )
 
@@ -25,7 +26,7 @@ func (h *getArtistHandler) Handle(r *http.Request) (*responses.SubsonicResponse,
 		return responses.NewParameterMissingResponse("id"), nil
 	}
 
-	artist, err := h.subsonic.GetArtist(id)
+	artist, err := h.subsonic.GetArtist(subsonicUtil.GetUsername(r), id)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getArtistInfoHandler struct {
+	discovery *logic.LastFmDiscoveryService
+}
+
+func NewGetArtistInfoHandler(discovery *logic.LastFmDiscoveryService) *getArtistInfoHandler {
+	return &getArtistInfoHandler{
+		discovery: discovery,
+	}
+}
+
+func (h *getArtistInfoHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	count := util.StringToIntOrDefault(r.URL.Query().Get("count"), 20)
+	includeNotPresent := util.StringToBoolOrDefault(r.URL.Query().Get("includeNotPresent"), false)
+
+	artistInfo2, err := h.discovery.GetArtistInfo(subsonicUtil.GetUsername(r), id, count, includeNotPresent)
+	if err != nil {
+		return nil, err
+	}
+
+	artistInfo := responses.NewArtistInfo(artistInfo2.SimilarArtist)
+	artistInfo.Biography = artistInfo2.Biography
+	artistInfo.MusicBrainzId = artistInfo2.MusicBrainzId
+	artistInfo.LastFmUrl = artistInfo2.LastFmUrl
+	artistInfo.SmallImageUrl = artistInfo2.SmallImageUrl
+	artistInfo.MediumImageUrl = artistInfo2.MediumImageUrl
+	artistInfo.LargeImageUrl = artistInfo2.LargeImageUrl
+
+	response := responses.NewOkResponse()
+	response.ArtistInfo = artistInfo
+	return response, nil
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getArtistInfo2Handler struct {
+	discovery *logic.LastFmDiscoveryService
+}
+
+func NewGetArtistInfo2Handler(discovery *logic.LastFmDiscoveryService) *getArtistInfo2Handler {
+	return &getArtistInfo2Handler{
+		discovery: discovery,
+	}
+}
+
+func (h *getArtistInfo2Handler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	count := util.StringToIntOrDefault(r.URL.Query().Get("count"), 20)
+	includeNotPresent := util.StringToBoolOrDefault(r.URL.Query().Get("includeNotPresent"), false)
+
+	artistInfo, err := h.discovery.GetArtistInfo(subsonicUtil.GetUsername(r), id, count, includeNotPresent)
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.ArtistInfo2 = artistInfo
+	return response, nil
+}
@@ -4,18 +4,29 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
 )
 
 type getArtistsHandler struct {
+	subsonic logic.SubsonicService
 }
 
-func NewGetArtistsHandler() *getArtistsHandler {
-	return &getArtistsHandler{}
+func NewGetArtistsHandler(
+	subsonic logic.SubsonicService,
+) *getArtistsHandler {
+	return &getArtistsHandler{
+		subsonic: subsonic,
+	}
 }
 
 func (h *getArtistsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	// todo
+	artists, err := h.subsonic.GetArtists(subsonicUtil.GetUsername(r))
+	if err != nil {
+		return nil, err
+	}
+
 	response := responses.NewOkResponse()
-	response.Artists = responses.NewArtists("", []responses.IndexId3{})
+	response.Artists = artists
 	return response, nil
 }
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type getBookmarksHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewGetBookmarksHandler(subsonic logic.SubsonicService) *getBookmarksHandler {
+	return &getBookmarksHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *getBookmarksHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	bookmarks, err := h.subsonic.GetBookmarks(subsonicUtil.GetUsername(r))
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.Bookmarks = bookmarks
+	return response, nil
+}
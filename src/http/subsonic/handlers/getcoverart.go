@@ -7,6 +7,7 @@ import (
 
 	"tapesonic/http/subsonic/responses"
 	"tapesonic/logic"
+	"tapesonic/util"
 )
 
 type getCoverArtHandler struct {
@@ -27,7 +28,7 @@ func (h *getCoverArtHandler) Handle(w http.ResponseWriter, r *http.Request) (*re
 		return responses.NewParameterMissingResponse("id"), nil
 	}
 
-	mimeType, reader, err := h.subsonic.GetCoverArt(id)
+	mimeType, reader, err := h.subsonic.GetCoverArt(id, util.StringToIntOrDefault(r.URL.Query().Get("size"), 0))
 	if err != nil {
 		return nil, err
 	}
@@ -4,18 +4,26 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
 )
 
 type getGenresHandler struct {
+	subsonic logic.SubsonicService
 }
 
-func NewGetGenresHandler() *getGenresHandler {
-	return &getGenresHandler{}
+func NewGetGenresHandler(subsonic logic.SubsonicService) *getGenresHandler {
+	return &getGenresHandler{
+		subsonic: subsonic,
+	}
 }
 
 func (h *getGenresHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	// todo
+	genres, err := h.subsonic.GetGenres()
+	if err != nil {
+		return nil, err
+	}
+
 	response := responses.NewOkResponse()
-	response.Genres = responses.NewGenres([]responses.Genre{})
+	response.Genres = genres
 	return response, nil
 }
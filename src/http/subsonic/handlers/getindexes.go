@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getIndexesHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewGetIndexesHandler(subsonic logic.SubsonicService) *getIndexesHandler {
+	return &getIndexesHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *getIndexesHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	indexes, err := h.subsonic.GetIndexes(subsonicUtil.GetUsername(r), r.URL.Query().Get("musicFolderId"))
+	if err != nil {
+		return nil, err
+	}
+
+	// clients only need the directories again when something has changed since the last request
+	ifModifiedSince := int64(util.StringToIntOrDefault(r.URL.Query().Get("ifModifiedSince"), 0))
+	if ifModifiedSince > 0 && indexes.LastModified <= ifModifiedSince {
+		indexes.Index = []responses.IndexId3{}
+	}
+
+	response := responses.NewOkResponse()
+	response.Indexes = indexes
+	return response, nil
+}
@@ -1,21 +1,46 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
 )
 
 type getInternetRadioStationsHandler struct {
+	radio *logic.RadioService
 }
 
-func NewGetInternetRadioStationsHandler() *getInternetRadioStationsHandler {
-	return &getInternetRadioStationsHandler{}
+func NewGetInternetRadioStationsHandler(radio *logic.RadioService) *getInternetRadioStationsHandler {
+	return &getInternetRadioStationsHandler{
+		radio: radio,
+	}
 }
 
 func (h *getInternetRadioStationsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	// todo
+	stations, err := h.radio.GetStations()
+	if err != nil {
+		return nil, err
+	}
+
+	stationsResponse := []responses.InternetRadioStation{}
+	for _, station := range stations {
+		stationResponse := responses.NewInternetRadioStation(
+			station.Id.String(),
+			station.Name,
+			getRadioStreamUrl(r, station.Id.String()),
+		)
+		stationResponse.HomePageUrl = station.HomepageUrl
+
+		stationsResponse = append(stationsResponse, *stationResponse)
+	}
+
 	response := responses.NewOkResponse()
-	response.InternetRadioStations = responses.NewInternetRadioStations([]responses.InternetRadioStation{})
+	response.InternetRadioStations = responses.NewInternetRadioStations(stationsResponse)
 	return response, nil
 }
+
+func getRadioStreamUrl(r *http.Request, id string) string {
+	return getPublicUrl(r, fmt.Sprintf("/radio/%s", id))
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type getLyricsHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewGetLyricsHandler(subsonic logic.SubsonicService) *getLyricsHandler {
+	return &getLyricsHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *getLyricsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	artist := r.URL.Query().Get("artist")
+	title := r.URL.Query().Get("title")
+
+	lyrics, err := h.subsonic.GetLyrics(artist, title)
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.Lyrics = lyrics
+	return response, nil
+}
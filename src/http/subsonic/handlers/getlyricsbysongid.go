@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type getLyricsBySongIdHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewGetLyricsBySongIdHandler(subsonic logic.SubsonicService) *getLyricsBySongIdHandler {
+	return &getLyricsBySongIdHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *getLyricsBySongIdHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	lyricsList, err := h.subsonic.GetLyricsBySongId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.LyricsList = lyricsList
+	return response, nil
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type getMusicDirectoryHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewGetMusicDirectoryHandler(subsonic logic.SubsonicService) *getMusicDirectoryHandler {
+	return &getMusicDirectoryHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *getMusicDirectoryHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	directory, err := h.subsonic.GetMusicDirectory(subsonicUtil.GetUsername(r), id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.Directory = directory
+	return response, nil
+}
@@ -4,18 +4,26 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
 )
 
 type getMusicFoldersHandler struct {
+	subsonic logic.SubsonicService
 }
 
-func NewGetMusicFoldersHandler() *getMusicFoldersHandler {
-	return &getMusicFoldersHandler{}
+func NewGetMusicFoldersHandler(subsonic logic.SubsonicService) *getMusicFoldersHandler {
+	return &getMusicFoldersHandler{
+		subsonic: subsonic,
+	}
 }
 
 func (h *getMusicFoldersHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	// todo
+	musicFolders, err := h.subsonic.GetMusicFolders()
+	if err != nil {
+		return nil, err
+	}
+
 	response := responses.NewOkResponse()
-	response.MusicFolders = responses.NewMusicFolders([]responses.MusicFolder{})
+	response.MusicFolders = musicFolders
 	return response, nil
 }
@@ -4,18 +4,29 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+	"tapesonic/util"
 )
 
 type getNewestPodcastsHandler struct {
+	subsonic logic.SubsonicService
 }
 
-func NewGetNewestPodcastsHandler() *getNewestPodcastsHandler {
-	return &getNewestPodcastsHandler{}
+func NewGetNewestPodcastsHandler(subsonic logic.SubsonicService) *getNewestPodcastsHandler {
+	return &getNewestPodcastsHandler{
+		subsonic: subsonic,
+	}
 }
 
 func (h *getNewestPodcastsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	// todo
+	count := max(util.StringToIntOrDefault(r.URL.Query().Get("count"), 20), 0)
+
+	podcasts, err := h.subsonic.GetNewestPodcasts(count)
+	if err != nil {
+		return nil, err
+	}
+
 	response := responses.NewOkResponse()
-	response.NewestPodcasts = responses.NewNewestPodcasts()
+	response.NewestPodcasts = podcasts
 	return response, nil
 }
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type getNowPlayingHandler struct {
+	nowPlaying *logic.NowPlayingService
+}
+
+func NewGetNowPlayingHandler(nowPlaying *logic.NowPlayingService) *getNowPlayingHandler {
+	return &getNowPlayingHandler{
+		nowPlaying: nowPlaying,
+	}
+}
+
+func (h *getNowPlayingHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	songs, err := h.nowPlaying.GetNowPlaying()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []responses.NowPlayingEntry{}
+	for _, song := range songs {
+		entry := responses.NewNowPlayingEntry(
+			song.Song,
+			song.Username,
+			song.MinutesAgo(),
+			song.PlayerId,
+		)
+		entry.PlayerName = song.ClientName
+
+		entries = append(entries, *entry)
+	}
+
+	response := responses.NewOkResponse()
+	response.NowPlaying = responses.NewNowPlaying(entries)
+	return response, nil
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+)
+
+var supportedOpenSubsonicExtensions = []responses.OpenSubsonicExtension{
+	*responses.NewOpenSubsonicExtension("apiKeyAuthentication", []int{1}),
+	*responses.NewOpenSubsonicExtension("formPost", []int{1}),
+	*responses.NewOpenSubsonicExtension("songLyrics", []int{1}),
+	*responses.NewOpenSubsonicExtension("transcodeOffset", []int{1}),
+}
+
+func GetOpenSubsonicExtensions(r *http.Request) (*responses.SubsonicResponse, error) {
+	response := responses.NewOkResponse()
+	response.OpenSubsonicExtensions = supportedOpenSubsonicExtensions
+	return response, nil
+}
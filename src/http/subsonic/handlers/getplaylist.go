@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 )
 
@@ -25,7 +26,7 @@ func (h *getPlaylistHandler) Handle(r *http.Request) (*responses.SubsonicRespons
 		return responses.NewParameterMissingResponse("id"), nil
 	}
 
-	playlist, err := h.subsonic.GetPlaylist(id)
+	playlist, err := h.subsonic.GetPlaylist(subsonicUtil.GetUsername(r), id)
 	if err != nil {
 		return nil, err
 	}
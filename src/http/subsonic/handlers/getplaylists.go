@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 )
 
@@ -20,7 +21,7 @@ func NewGetPlaylistsHandler(
 }
 
 func (h *getPlaylistsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	playlists, err := h.subsonic.GetPlaylists()
+	playlists, err := h.subsonic.GetPlaylists(subsonicUtil.GetUsername(r))
 	if err != nil {
 		return nil, err
 	}
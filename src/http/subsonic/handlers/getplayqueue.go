@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type getPlayQueueHandler struct {
+	playQueue *logic.PlayQueueService
+}
+
+func NewGetPlayQueueHandler(playQueue *logic.PlayQueueService) *getPlayQueueHandler {
+	return &getPlayQueueHandler{
+		playQueue: playQueue,
+	}
+}
+
+func (h *getPlayQueueHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	playQueue, err := h.playQueue.Get(subsonicUtil.GetUsername(r))
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.PlayQueue = playQueue
+	return response, nil
+}
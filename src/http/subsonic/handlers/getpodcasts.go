@@ -4,18 +4,30 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+	"tapesonic/util"
 )
 
 type getPodcastsHandler struct {
+	subsonic logic.SubsonicService
 }
 
-func NewGetPodcastsHandler() *getPodcastsHandler {
-	return &getPodcastsHandler{}
+func NewGetPodcastsHandler(subsonic logic.SubsonicService) *getPodcastsHandler {
+	return &getPodcastsHandler{
+		subsonic: subsonic,
+	}
 }
 
 func (h *getPodcastsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	// todo
+	id := r.URL.Query().Get("id")
+	includeEpisodes := util.StringToBoolOrDefault(r.URL.Query().Get("includeEpisodes"), true)
+
+	podcasts, err := h.subsonic.GetPodcasts(id, includeEpisodes)
+	if err != nil {
+		return nil, err
+	}
+
 	response := responses.NewOkResponse()
-	response.Podcasts = responses.NewPodcasts()
+	response.Podcasts = podcasts
 	return response, nil
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 	"tapesonic/util"
 )
@@ -24,7 +25,7 @@ func (h *getRandomSongsHandler) Handle(r *http.Request) (*responses.SubsonicResp
 	fromYear := util.StringToIntOrNull(r.URL.Query().Get("fromYear"))
 	toYear := util.StringToIntOrNull(r.URL.Query().Get("toYear"))
 
-	songs, err := h.subsonic.GetRandomSongs(size, genre, fromYear, toYear)
+	songs, err := h.subsonic.GetRandomSongs(subsonicUtil.GetUsername(r), size, genre, fromYear, toYear)
 	if err != nil {
 		return nil, err
 	}
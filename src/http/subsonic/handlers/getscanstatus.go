@@ -4,18 +4,23 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	"tapesonic/tasks"
 )
 
 type getScanStatusHandler struct {
+	syncLibrary *tasks.SyncLibraryHandler
 }
 
-func NewGetScanStatusHandler() *getScanStatusHandler {
-	return &getScanStatusHandler{}
+func NewGetScanStatusHandler(syncLibrary *tasks.SyncLibraryHandler) *getScanStatusHandler {
+	return &getScanStatusHandler{
+		syncLibrary: syncLibrary,
+	}
 }
 
 func (h *getScanStatusHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	// todo
+	scanning, count := h.syncLibrary.GetScanStatus()
+
 	response := responses.NewOkResponse()
-	response.ScanStatus = responses.NewScanStatus(false, 0)
+	response.ScanStatus = responses.NewScanStatus(scanning, count)
 	return response, nil
 }
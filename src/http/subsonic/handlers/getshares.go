@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getSharesHandler struct {
+	shares *logic.ShareService
+}
+
+func NewGetSharesHandler(shares *logic.ShareService) *getSharesHandler {
+	return &getSharesHandler{
+		shares: shares,
+	}
+}
+
+func (h *getSharesHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	shares, err := h.shares.GetAll(subsonicUtil.GetUsername(r))
+	if err != nil {
+		return nil, err
+	}
+
+	sharesResponse := []responses.Share{}
+	for _, share := range shares {
+		sharesResponse = append(sharesResponse, toShareResponse(r, share))
+	}
+
+	response := responses.NewOkResponse()
+	response.Shares = responses.NewShares(sharesResponse)
+	return response, nil
+}
+
+func toShareResponse(r *http.Request, share logic.Share) responses.Share {
+	shareResponse := responses.NewShare(
+		share.Id.String(),
+		getPublicUrl(r, fmt.Sprintf("/share/%s", share.Id)),
+		share.Username,
+		share.CreatedAt,
+		share.VisitCount,
+	)
+	shareResponse.Description = share.Description
+	shareResponse.Expires = share.ExpiresAt
+	shareResponse.LastVisited = share.LastVisitedAt
+	shareResponse.Entry = share.Entries
+
+	return *shareResponse
+}
+
+// expires is passed in milliseconds since epoch, zero or nothing means that the share never expires
+func parseShareExpiry(rawExpires string) *time.Time {
+	expires := util.StringToInt64OrDefault(rawExpires, 0)
+	if expires <= 0 {
+		return nil
+	}
+
+	expiresAt := time.UnixMilli(expires)
+	return &expiresAt
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getSimilarSongsHandler struct {
+	discovery *logic.LastFmDiscoveryService
+}
+
+func NewGetSimilarSongsHandler(discovery *logic.LastFmDiscoveryService) *getSimilarSongsHandler {
+	return &getSimilarSongsHandler{
+		discovery: discovery,
+	}
+}
+
+func (h *getSimilarSongsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	count := max(util.StringToIntOrDefault(r.URL.Query().Get("count"), 50), 0)
+
+	songs, err := h.discovery.GetSimilarSongs(subsonicUtil.GetUsername(r), id, count)
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.SimilarSongs = responses.NewSimilarSongs(songs)
+	return response, nil
+}
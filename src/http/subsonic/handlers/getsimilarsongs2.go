@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getSimilarSongs2Handler struct {
+	discovery *logic.LastFmDiscoveryService
+}
+
+func NewGetSimilarSongs2Handler(discovery *logic.LastFmDiscoveryService) *getSimilarSongs2Handler {
+	return &getSimilarSongs2Handler{
+		discovery: discovery,
+	}
+}
+
+func (h *getSimilarSongs2Handler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	count := max(util.StringToIntOrDefault(r.URL.Query().Get("count"), 50), 0)
+
+	songs, err := h.discovery.GetSimilarSongsByArtist(subsonicUtil.GetUsername(r), id, count)
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.SimilarSongs2 = responses.NewSimilarSongs2(songs)
+	return response, nil
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 )
 
@@ -25,7 +26,7 @@ func (h *getSongHandler) Handle(r *http.Request) (*responses.SubsonicResponse, e
 		return responses.NewParameterMissingResponse("id"), nil
 	}
 
-	song, err := h.subsonic.GetSong(id)
+	song, err := h.subsonic.GetSong(subsonicUtil.GetUsername(r), id)
 	if err != nil {
 		return nil, err
 	}
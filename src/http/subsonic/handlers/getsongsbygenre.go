@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getSongsByGenreHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewGetSongsByGenreHandler(subsonic logic.SubsonicService) *getSongsByGenreHandler {
+	return &getSongsByGenreHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *getSongsByGenreHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	genre := r.URL.Query().Get("genre")
+	if genre == "" {
+		return responses.NewParameterMissingResponse("genre"), nil
+	}
+
+	count := min(max(util.StringToIntOrDefault(r.URL.Query().Get("count"), 10), 0), 500)
+	offset := max(util.StringToIntOrDefault(r.URL.Query().Get("offset"), 0), 0)
+
+	songs, err := h.subsonic.GetSongsByGenre(subsonicUtil.GetUsername(r), genre, count, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.SongsByGenre = songs
+	return response, nil
+}
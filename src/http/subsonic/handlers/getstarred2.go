@@ -4,18 +4,29 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
 )
 
 type getStarred2Handler struct {
+	subsonic logic.SubsonicService
 }
 
-func NewGetStarred2Handler() *getStarred2Handler {
-	return &getStarred2Handler{}
+func NewGetStarred2Handler(
+	subsonic logic.SubsonicService,
+) *getStarred2Handler {
+	return &getStarred2Handler{
+		subsonic: subsonic,
+	}
 }
 
 func (h *getStarred2Handler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	// todo
+	starred, err := h.subsonic.GetStarred2(subsonicUtil.GetUsername(r))
+	if err != nil {
+		return nil, err
+	}
+
 	response := responses.NewOkResponse()
-	response.Starred2 = responses.NewStarred2()
+	response.Starred2 = starred
 	return response, nil
 }
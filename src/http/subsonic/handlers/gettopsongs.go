@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type getTopSongsHandler struct {
+	discovery *logic.LastFmDiscoveryService
+}
+
+func NewGetTopSongsHandler(discovery *logic.LastFmDiscoveryService) *getTopSongsHandler {
+	return &getTopSongsHandler{
+		discovery: discovery,
+	}
+}
+
+func (h *getTopSongsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	artist := r.URL.Query().Get("artist")
+	if artist == "" {
+		return responses.NewParameterMissingResponse("artist"), nil
+	}
+
+	count := max(util.StringToIntOrDefault(r.URL.Query().Get("count"), 50), 0)
+
+	songs, err := h.discovery.GetTopSongs(subsonicUtil.GetUsername(r), artist, count)
+	if err != nil {
+		return nil, err
+	}
+
+	response := responses.NewOkResponse()
+	response.TopSongs = responses.NewTopSongs(songs)
+	return response, nil
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type getUserHandler struct {
+	users *logic.UserService
+}
+
+func NewGetUserHandler(users *logic.UserService) *getUserHandler {
+	return &getUserHandler{
+		users: users,
+	}
+}
+
+func (h *getUserHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		return responses.NewParameterMissingResponse("username"), nil
+	}
+
+	if username != subsonicUtil.GetUsername(r) && !subsonicUtil.IsAdmin(r) {
+		return responses.NewNotAuthorizedResponse("Only admins can view other users"), nil
+	}
+
+	user, err := h.users.Get(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return responses.NewNotFoundResponse("User"), nil
+	}
+
+	response := responses.NewOkResponse()
+	response.User = toUserResponse(*user)
+	return response, nil
+}
+
+func toUserResponse(user logic.User) *responses.User {
+	return responses.NewUser(user.Username, user.Email, user.IsAdmin())
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type getUsersHandler struct {
+	users *logic.UserService
+}
+
+func NewGetUsersHandler(users *logic.UserService) *getUsersHandler {
+	return &getUsersHandler{
+		users: users,
+	}
+}
+
+func (h *getUsersHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	users, err := h.users.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	userResponses := []responses.User{}
+	for _, user := range users {
+		userResponses = append(userResponses, *toUserResponse(user))
+	}
+
+	response := responses.NewOkResponse()
+	response.Users = responses.NewUsers(userResponses)
+	return response, nil
+}
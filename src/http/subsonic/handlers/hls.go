@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"tapesonic/config"
+	"tapesonic/ffmpeg"
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type hlsHandler struct {
+	subsonic logic.SubsonicService
+	profiles map[string]config.TranscodingProfile
+}
+
+func NewHlsHandler(
+	subsonic logic.SubsonicService,
+	profiles map[string]config.TranscodingProfile,
+) *hlsHandler {
+	return &hlsHandler{
+		subsonic: subsonic,
+		profiles: profiles,
+	}
+}
+
+func (h *hlsHandler) Handle(w http.ResponseWriter, r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	segments, err := h.subsonic.GetStreamSegments(id)
+	if err != nil {
+		if errors.Is(err, logic.ErrHlsUnsupported) {
+			return responses.NewFailedResponse(0, err.Error()), nil
+		}
+		return nil, err
+	}
+
+	bitRates := getHlsBitRates(r)
+
+	w.Header().Add("Content-Type", "application/vnd.apple.mpegurl")
+
+	// several bitrates make a master playlist so the player can switch between them
+	if len(bitRates) > 1 {
+		fmt.Fprintln(w, "#EXTM3U")
+		for _, bitRate := range bitRates {
+			fmt.Fprintf(w, "#EXT-X-STREAM-INF:BANDWIDTH=%d,CODECS=\"mp4a.40.2\"\n", bitRate*1000)
+			fmt.Fprintln(w, "hls.m3u8?"+h.getQuery(r, id, bitRate).Encode())
+		}
+		return nil, nil
+	}
+
+	maxBitRate := 0
+	if len(bitRates) == 1 {
+		maxBitRate = bitRates[0]
+	} else if profile, ok := h.profiles[strings.ToLower(subsonicUtil.GetClientName(r))]; ok {
+		maxBitRate = profile.MaxBitRate
+	}
+	maxBitRate = ffmpeg.GetBitRate(ffmpeg.SEGMENT_FORMAT, maxBitRate)
+
+	fmt.Fprintln(w, "#EXTM3U")
+	fmt.Fprintln(w, "#EXT-X-VERSION:3")
+	fmt.Fprintf(w, "#EXT-X-TARGETDURATION:%d\n", (logic.HLS_SEGMENT_DURATION_MS+999)/1000)
+	fmt.Fprintln(w, "#EXT-X-MEDIA-SEQUENCE:0")
+	fmt.Fprintln(w, "#EXT-X-PLAYLIST-TYPE:VOD")
+	for index, durationMs := range segments {
+		query := h.getQuery(r, id, maxBitRate)
+		query.Set("segment", fmt.Sprint(index))
+
+		fmt.Fprintf(w, "#EXTINF:%.3f,\n", float64(durationMs)/1000)
+		fmt.Fprintln(w, "hls.ts?"+query.Encode())
+	}
+	fmt.Fprintln(w, "#EXT-X-ENDLIST")
+
+	return nil, nil
+}
+
+// urls are relative, so they work the same way behind a reverse proxy
+func (h *hlsHandler) getQuery(r *http.Request, id string, bitRate int) url.Values {
+	query := subsonicUtil.GetAuthParams(r)
+	query.Set("id", id)
+	if bitRate > 0 {
+		query.Set("bitRate", fmt.Sprint(bitRate))
+	}
+	return query
+}
+
+// bitrates may come with a video resolution like `1000@480x360`, which doesn't matter for audio
+func getHlsBitRates(r *http.Request) []int {
+	result := []int{}
+	for _, raw := range r.URL.Query()["bitRate"] {
+		raw, _, _ = strings.Cut(raw, "@")
+		if bitRate := util.StringToIntOrDefault(raw, 0); bitRate > 0 {
+			result = append(result, bitRate)
+		}
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type hlsSegmentHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewHlsSegmentHandler(
+	subsonic logic.SubsonicService,
+) *hlsSegmentHandler {
+	return &hlsSegmentHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *hlsSegmentHandler) Handle(w http.ResponseWriter, r *http.Request) (*responses.SubsonicResponse, error) {
+	query := r.URL.Query()
+
+	id := query.Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	segment := util.StringToIntOrNull(query.Get("segment"))
+	if segment == nil {
+		return responses.NewParameterMissingResponse("segment"), nil
+	}
+
+	stream, err := h.subsonic.StreamSegment(r.Context(), id, *segment, util.StringToIntOrDefault(query.Get("bitRate"), 0))
+	if err != nil {
+		if errors.Is(err, logic.ErrHlsUnsupported) {
+			return responses.NewFailedResponse(0, err.Error()), nil
+		}
+		return nil, err
+	}
+
+	defer stream.Reader.Close()
+
+	w.Header().Add("Content-Type", stream.MimeType)
+
+	readSeeker, isSeekable := stream.Reader.(io.ReadSeeker)
+	if isSeekable {
+		http.ServeContent(w, r, "", time.Time{}, readSeeker)
+	} else {
+		io.Copy(w, stream.Reader)
+	}
+
+	return nil, nil
+}
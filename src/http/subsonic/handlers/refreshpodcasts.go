@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+)
+
+type refreshPodcastsHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewRefreshPodcastsHandler(subsonic logic.SubsonicService) *refreshPodcastsHandler {
+	return &refreshPodcastsHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *refreshPodcastsHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	if err := h.subsonic.RefreshPodcasts(); err != nil {
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
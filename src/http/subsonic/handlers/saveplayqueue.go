@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type savePlayQueueHandler struct {
+	playQueue *logic.PlayQueueService
+}
+
+func NewSavePlayQueueHandler(playQueue *logic.PlayQueueService) *savePlayQueueHandler {
+	return &savePlayQueueHandler{
+		playQueue: playQueue,
+	}
+}
+
+func (h *savePlayQueueHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	ids := r.URL.Query()["id"]
+	current := r.URL.Query().Get("current")
+	position := util.StringToInt64OrDefault(r.URL.Query().Get("position"), 0)
+
+	err := h.playQueue.Save(
+		subsonicUtil.GetUsername(r),
+		ids,
+		current,
+		position,
+		subsonicUtil.GetClientName(r),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
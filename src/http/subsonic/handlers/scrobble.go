@@ -5,16 +5,18 @@ import (
 	"time"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 	"tapesonic/util"
 )
 
 type scrobbleHandler struct {
-	subsonic logic.SubsonicService
+	subsonic   logic.SubsonicService
+	nowPlaying *logic.NowPlayingService
 }
 
-func NewScrobbleHandler(subsonic logic.SubsonicService) *scrobbleHandler {
-	return &scrobbleHandler{subsonic: subsonic}
+func NewScrobbleHandler(subsonic logic.SubsonicService, nowPlaying *logic.NowPlayingService) *scrobbleHandler {
+	return &scrobbleHandler{subsonic: subsonic, nowPlaying: nowPlaying}
 }
 
 func (h *scrobbleHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
@@ -29,5 +31,14 @@ func (h *scrobbleHandler) Handle(r *http.Request) (*responses.SubsonicResponse,
 	submissionStr := r.URL.Query().Get("submission")
 	submission := util.StringToBoolOrDefault(submissionStr, true)
 
-	return responses.NewOkResponse(), h.subsonic.Scrobble(id, time_, submission)
+	if err := h.subsonic.Scrobble(subsonicUtil.GetUsername(r), id, time_, submission); err != nil {
+		return nil, err
+	}
+
+	// clients only send a "now playing" notification when the playback starts
+	if !submission {
+		h.nowPlaying.SetNowPlaying(subsonicUtil.GetUsername(r), subsonicUtil.GetClientName(r), id, time_)
+	}
+
+	return responses.NewOkResponse(), nil
 }
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type search2Handler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewSearch2Handler(subsonic logic.SubsonicService) *search2Handler {
+	return &search2Handler{subsonic: subsonic}
+}
+
+func (h *search2Handler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	searchResult3, err := h.subsonic.Search3(subsonicUtil.GetUsername(r),
+		r.URL.Query().Get("query"),
+		util.StringToIntOrDefault(r.URL.Query().Get("artistCount"), 20),
+		util.StringToIntOrDefault(r.URL.Query().Get("artistOffset"), 0),
+		util.StringToIntOrDefault(r.URL.Query().Get("albumCount"), 20),
+		util.StringToIntOrDefault(r.URL.Query().Get("albumOffset"), 0),
+		util.StringToIntOrDefault(r.URL.Query().Get("songCount"), 20),
+		util.StringToIntOrDefault(r.URL.Query().Get("songOffset"), 0),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := []responses.SubsonicChild{}
+	for _, album := range searchResult3.Album {
+		albums = append(albums, logic.NewAlbumChild(album))
+	}
+
+	response := responses.NewOkResponse()
+	response.SearchResult2 = responses.NewSearchResult2(searchResult3.Artist, albums, searchResult3.Song)
+	return response, nil
+}
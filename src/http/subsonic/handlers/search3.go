@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
 	"tapesonic/util"
 )
@@ -17,7 +18,7 @@ func NewSearch3Handler(subsonic logic.SubsonicService) *search3Handler {
 }
 
 func (h *search3Handler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
-	searchResult3, err := h.subsonic.Search3(
+	searchResult3, err := h.subsonic.Search3(subsonicUtil.GetUsername(r),
 		r.URL.Query().Get("query"),
 		util.StringToIntOrDefault(r.URL.Query().Get("artistCount"), 20),
 		util.StringToIntOrDefault(r.URL.Query().Get("artistOffset"), 0),
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/util"
+)
+
+type setRatingHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewSetRatingHandler(subsonic logic.SubsonicService) *setRatingHandler {
+	return &setRatingHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *setRatingHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	rating := util.StringToIntOrNull(r.URL.Query().Get("rating"))
+	if rating == nil {
+		return responses.NewParameterMissingResponse("rating"), nil
+	}
+	if *rating < 0 || *rating > 5 {
+		return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, "Rating must be between 0 and 5"), nil
+	}
+
+	return responses.NewOkResponse(), h.subsonic.SetRating(subsonicUtil.GetUsername(r), id, *rating)
+}
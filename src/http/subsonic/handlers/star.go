@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type starHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewStarHandler(subsonic logic.SubsonicService) *starHandler {
+	return &starHandler{subsonic: subsonic}
+}
+
+func (h *starHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	ids := r.URL.Query()["id"]
+	albumIds := r.URL.Query()["albumId"]
+	artistIds := r.URL.Query()["artistId"]
+	if len(ids) == 0 && len(albumIds) == 0 && len(artistIds) == 0 {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	return responses.NewOkResponse(), h.subsonic.Star(subsonicUtil.GetUsername(r), ids, albumIds, artistIds)
+}
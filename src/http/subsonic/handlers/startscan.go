@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/tasks"
+)
+
+type startScanHandler struct {
+	syncLibrary *tasks.SyncLibraryHandler
+}
+
+func NewStartScanHandler(syncLibrary *tasks.SyncLibraryHandler) *startScanHandler {
+	return &startScanHandler{
+		syncLibrary: syncLibrary,
+	}
+}
+
+func (h *startScanHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	if !h.syncLibrary.StartScan() {
+		slog.Debug("Library cache sync is already running, not starting another one")
+	}
+
+	scanning, count := h.syncLibrary.GetScanStatus()
+
+	response := responses.NewOkResponse()
+	response.ScanStatus = responses.NewScanStatus(scanning, count)
+	return response, nil
+}
@@ -1,33 +1,49 @@
 package handlers
 
 import (
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
+	"tapesonic/config"
+	"tapesonic/ffmpeg"
 	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
 	"tapesonic/logic"
+	"tapesonic/util"
 )
 
 type streamHandler struct {
 	subsonic logic.SubsonicService
+	profiles map[string]config.TranscodingProfile
 }
 
 func NewStreamHandler(
 	subsonic logic.SubsonicService,
+	profiles map[string]config.TranscodingProfile,
 ) *streamHandler {
 	return &streamHandler{
 		subsonic: subsonic,
+		profiles: profiles,
 	}
 }
 
 func (h *streamHandler) Handle(w http.ResponseWriter, r *http.Request) (*responses.SubsonicResponse, error) {
-	id := r.URL.Query().Get("id")
+	query := r.URL.Query()
+
+	id := query.Get("id")
 	if id == "" {
 		return responses.NewParameterMissingResponse("id"), nil
 	}
 
-	stream, err := h.subsonic.Stream(r.Context(), id)
+	options := h.getStreamOptions(r)
+	if options.Format != "" && !ffmpeg.IsSupportedFormat(options.Format) {
+		return responses.NewFailedResponse(0, fmt.Sprintf("Unsupported format `%s`", options.Format)), nil
+	}
+
+	stream, err := h.subsonic.Stream(r.Context(), id, options)
 	if err != nil {
 		return nil, err
 	}
@@ -42,8 +58,57 @@ func (h *streamHandler) Handle(w http.ResponseWriter, r *http.Request) (*respons
 	if isSeekable {
 		http.ServeContent(w, r, "", time.Time{}, readSeeker)
 	} else {
+		if options.EstimateContentLength && stream.BitRate > 0 {
+			h.setEstimatedContentLength(w, id, stream.BitRate, options.TimeOffsetMs)
+		}
 		io.Copy(w, stream.Reader)
 	}
 
 	return nil, nil
 }
+
+// the client's profile is applied first, an explicitly requested format overrides it while the bitrate can only be lowered
+func (h *streamHandler) getStreamOptions(r *http.Request) logic.StreamOptions {
+	query := r.URL.Query()
+
+	options := logic.StreamOptions{
+		TimeOffsetMs:          int64(max(util.StringToIntOrDefault(query.Get("timeOffset"), 0), 0)) * 1000,
+		EstimateContentLength: util.StringToBoolOrDefault(query.Get("estimateContentLength"), false),
+	}
+
+	if profile, ok := h.profiles[strings.ToLower(subsonicUtil.GetClientName(r))]; ok {
+		options.Format = profile.Format
+		options.MaxBitRate = profile.MaxBitRate
+	}
+
+	// raw asks for the original file, so the profile is ignored entirely
+	if format := strings.ToLower(query.Get("format")); format == "raw" {
+		options.Format = ""
+		options.MaxBitRate = 0
+	} else if format != "" {
+		options.Format = format
+	}
+
+	if maxBitRate := util.StringToIntOrDefault(query.Get("maxBitRate"), 0); maxBitRate > 0 {
+		if options.MaxBitRate == 0 {
+			options.MaxBitRate = maxBitRate
+		} else {
+			options.MaxBitRate = min(options.MaxBitRate, maxBitRate)
+		}
+	}
+
+	return options
+}
+
+func (h *streamHandler) setEstimatedContentLength(w http.ResponseWriter, id string, bitRate int, timeOffsetMs int64) {
+	song, err := h.subsonic.GetSong("", id)
+	if err != nil {
+		// the estimate is optional, the stream can be served without it
+		return
+	}
+
+	durationMs := int64(song.Duration)*1000 - timeOffsetMs
+	if durationMs > 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(durationMs*int64(bitRate)/8))
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+
+	"github.com/google/uuid"
+)
+
+type updateInternetRadioStationHandler struct {
+	radio *logic.RadioService
+}
+
+func NewUpdateInternetRadioStationHandler(radio *logic.RadioService) *updateInternetRadioStationHandler {
+	return &updateInternetRadioStationHandler{
+		radio: radio,
+	}
+}
+
+func (h *updateInternetRadioStationHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	rawId := r.URL.Query().Get("id")
+	if rawId == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		return responses.NewNotFoundResponse("radio station"), nil
+	}
+
+	streamUrl := r.URL.Query().Get("streamUrl")
+	if streamUrl == "" {
+		return responses.NewParameterMissingResponse("streamUrl"), nil
+	}
+
+	name := r.URL.Query().Get("name")
+	homepageUrl := r.URL.Query().Get("homepageUrl")
+
+	err = h.radio.UpdateStation(r.Context(), id, streamUrl, name, homepageUrl)
+	if err != nil {
+		if errors.Is(err, logic.ErrNotLiveStream) {
+			return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, err.Error()), nil
+		}
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
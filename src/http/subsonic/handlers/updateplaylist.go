@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+)
+
+type updatePlaylistHandler struct {
+	subsonic logic.SubsonicService
+}
+
+func NewUpdatePlaylistHandler(subsonic logic.SubsonicService) *updatePlaylistHandler {
+	return &updatePlaylistHandler{
+		subsonic: subsonic,
+	}
+}
+
+func (h *updatePlaylistHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	playlistId := r.URL.Query().Get("playlistId")
+	if playlistId == "" {
+		return responses.NewParameterMissingResponse("playlistId"), nil
+	}
+
+	name := r.URL.Query().Get("name")
+	songIdsToAdd := r.URL.Query()["songIdToAdd"]
+
+	songIndexesToRemove := []int{}
+	for _, rawIndex := range r.URL.Query()["songIndexToRemove"] {
+		index, err := strconv.Atoi(rawIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		songIndexesToRemove = append(songIndexesToRemove, index)
+	}
+
+	err := h.subsonic.UpdatePlaylist(subsonicUtil.GetUsername(r), playlistId, name, songIdsToAdd, songIndexesToRemove)
+	if err != nil {
+		if errors.Is(err, logic.ErrReadOnlyPlaylist) {
+			return responses.NewNotAuthorizedResponse(err.Error()), nil
+		}
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
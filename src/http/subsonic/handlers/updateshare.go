@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+
+	"github.com/google/uuid"
+)
+
+type updateShareHandler struct {
+	shares *logic.ShareService
+}
+
+func NewUpdateShareHandler(shares *logic.ShareService) *updateShareHandler {
+	return &updateShareHandler{
+		shares: shares,
+	}
+}
+
+func (h *updateShareHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	query := r.URL.Query()
+
+	rawId := query.Get("id")
+	if rawId == "" {
+		return responses.NewParameterMissingResponse("id"), nil
+	}
+
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		return responses.NewNotFoundResponse("share"), nil
+	}
+
+	err = h.shares.Update(
+		subsonicUtil.GetUsername(r),
+		id,
+		query.Get("description"),
+		parseShareExpiry(query.Get("expires")),
+	)
+	if err != nil {
+		if errors.Is(err, logic.ErrShareNotFound) {
+			return responses.NewNotFoundResponse("share"), nil
+		}
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"tapesonic/http/subsonic/responses"
+	subsonicUtil "tapesonic/http/subsonic/util"
+	"tapesonic/logic"
+	"tapesonic/storage"
+)
+
+type updateUserHandler struct {
+	users *logic.UserService
+}
+
+func NewUpdateUserHandler(users *logic.UserService) *updateUserHandler {
+	return &updateUserHandler{
+		users: users,
+	}
+}
+
+func (h *updateUserHandler) Handle(r *http.Request) (*responses.SubsonicResponse, error) {
+	query := r.URL.Query()
+
+	username := query.Get("username")
+	if username == "" {
+		return responses.NewParameterMissingResponse("username"), nil
+	}
+
+	user, err := h.users.Get(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return responses.NewNotFoundResponse("User"), nil
+	}
+
+	password, err := subsonicUtil.DecodePassword(query.Get("password"))
+	if err != nil {
+		return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, "Invalid password encoding"), nil
+	}
+
+	email := user.Email
+	if query.Has("email") {
+		email = query.Get("email")
+	}
+
+	role := user.Role
+	if query.Has("adminRole") {
+		isAdmin, err := strconv.ParseBool(query.Get("adminRole"))
+		if err != nil {
+			return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, "adminRole must be true or false"), nil
+		}
+
+		if isAdmin {
+			role = storage.USER_ROLE_ADMIN
+		} else if username == subsonicUtil.GetUsername(r) {
+			return responses.NewFailedResponse(responses.ERROR_CODE_GENERIC, "Admins can't take away their own admin role"), nil
+		} else {
+			role = storage.USER_ROLE_STREAM
+		}
+	}
+
+	if err := h.users.Update(username, password, email, role); err != nil {
+		return nil, err
+	}
+
+	return responses.NewOkResponse(), nil
+}
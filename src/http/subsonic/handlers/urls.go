@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"tapesonic/util"
+)
+
+// urls opened outside of subsonic clients (radio streams, shares) are used as-is, so they have to point back to this server
+func getPublicUrl(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	scheme = util.Coalesce(r.Header.Get("X-Forwarded-Proto"), scheme)
+	host := util.Coalesce(r.Header.Get("X-Forwarded-Host"), r.Host)
+
+	return fmt.Sprintf("%s://%s%s", scheme, host, path)
+}
@@ -18,7 +18,11 @@ type AlbumId3 struct {
 	Created time.Time  `json:"created" xml:"created,attr"`
 	Starred *time.Time `json:"starred" xml:"starred,attr,omitempty"`
 
-	Year int `json:"year" xml:"year,attr"`
+	UserRating    int     `json:"userRating,omitempty" xml:"userRating,attr,omitempty"`
+	AverageRating float64 `json:"averageRating,omitempty" xml:"averageRating,attr,omitempty"`
+
+	Year  int    `json:"year" xml:"year,attr"`
+	Genre string `json:"genre,omitempty" xml:"genre,attr,omitempty"`
 
 	ReleaseDate *ItemDate `json:"releaseDate" xml:"releaseDate"`
 
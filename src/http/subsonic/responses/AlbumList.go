@@ -0,0 +1,11 @@
+package responses
+
+type AlbumList struct {
+	Album []SubsonicChild `json:"album" xml:"album"`
+}
+
+func NewAlbumList(albums []SubsonicChild) *AlbumList {
+	return &AlbumList{
+		Album: albums,
+	}
+}
@@ -6,8 +6,11 @@ type Artist struct {
 	Id   string `json:"id" xml:"id,attr"`
 	Name string `json:"name" xml:"name,attr"`
 
+	CoverArt       string `json:"coverArt" xml:"coverArt,attr"`
 	ArtistImageUrl string `json:"artistImageUrl" xml:"artistImageUrl,attr"`
 
+	AlbumCount int `json:"albumCount" xml:"albumCount,attr"`
+
 	Starred *time.Time `json:"starred" xml:"starred,attr,omitempty"`
 
 	UserRating    int     `json:"userRating" xml:"userRating,attr"`
@@ -0,0 +1,18 @@
+package responses
+
+type ArtistInfo2 struct {
+	Biography      string `json:"biography,omitempty" xml:"biography,omitempty"`
+	MusicBrainzId  string `json:"musicBrainzId,omitempty" xml:"musicBrainzId,omitempty"`
+	LastFmUrl      string `json:"lastFmUrl,omitempty" xml:"lastFmUrl,omitempty"`
+	SmallImageUrl  string `json:"smallImageUrl,omitempty" xml:"smallImageUrl,omitempty"`
+	MediumImageUrl string `json:"mediumImageUrl,omitempty" xml:"mediumImageUrl,omitempty"`
+	LargeImageUrl  string `json:"largeImageUrl,omitempty" xml:"largeImageUrl,omitempty"`
+
+	SimilarArtist []ArtistId3 `json:"similarArtist,omitempty" xml:"similarArtist"`
+}
+
+func NewArtistInfo2(similarArtist []ArtistId3) *ArtistInfo2 {
+	return &ArtistInfo2{
+		SimilarArtist: similarArtist,
+	}
+}
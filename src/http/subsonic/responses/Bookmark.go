@@ -0,0 +1,31 @@
+package responses
+
+import "time"
+
+type Bookmark struct {
+	Position int64     `json:"position" xml:"position,attr"`
+	Username string    `json:"username" xml:"username,attr"`
+	Comment  string    `json:"comment,omitempty" xml:"comment,attr,omitempty"`
+	Created  time.Time `json:"created" xml:"created,attr"`
+	Changed  time.Time `json:"changed" xml:"changed,attr"`
+
+	Entry SubsonicChild `json:"entry" xml:"entry"`
+}
+
+func NewBookmark(
+	positionMs int64,
+	username string,
+	comment string,
+	created time.Time,
+	changed time.Time,
+	entry SubsonicChild,
+) *Bookmark {
+	return &Bookmark{
+		Position: positionMs,
+		Username: username,
+		Comment:  comment,
+		Created:  created,
+		Changed:  changed,
+		Entry:    entry,
+	}
+}
@@ -0,0 +1,11 @@
+package responses
+
+type Bookmarks struct {
+	Bookmark []Bookmark `json:"bookmark" xml:"bookmark"`
+}
+
+func NewBookmarks(bookmarks []Bookmark) *Bookmarks {
+	return &Bookmarks{
+		Bookmark: bookmarks,
+	}
+}
@@ -0,0 +1,21 @@
+package responses
+
+type Directory struct {
+	Id     string `json:"id" xml:"id,attr"`
+	Parent string `json:"parent,omitempty" xml:"parent,attr,omitempty"`
+	Name   string `json:"name" xml:"name,attr"`
+
+	Child []SubsonicChild `json:"child" xml:"child"`
+}
+
+func NewDirectory(
+	id string,
+	name string,
+	children []SubsonicChild,
+) *Directory {
+	return &Directory{
+		Id:    id,
+		Name:  name,
+		Child: children,
+	}
+}
@@ -0,0 +1,19 @@
+package responses
+
+type Indexes struct {
+	LastModified    int64      `json:"lastModified" xml:"lastModified,attr"`
+	IgnoredArticles string     `json:"ignoredArticles" xml:"ignoredArticles,attr"`
+	Index           []IndexId3 `json:"index" xml:"index"`
+}
+
+func NewIndexes(
+	lastModified int64,
+	ignoredArticles string,
+	index []IndexId3,
+) *Indexes {
+	return &Indexes{
+		LastModified:    lastModified,
+		IgnoredArticles: ignoredArticles,
+		Index:           index,
+	}
+}
@@ -0,0 +1,19 @@
+package responses
+
+type Lyrics struct {
+	Artist string `json:"artist,omitempty" xml:"artist,attr,omitempty"`
+	Title  string `json:"title,omitempty" xml:"title,attr,omitempty"`
+	Value  string `json:"value" xml:",chardata"`
+}
+
+func NewLyrics(
+	artist string,
+	title string,
+	value string,
+) *Lyrics {
+	return &Lyrics{
+		Artist: artist,
+		Title:  title,
+		Value:  value,
+	}
+}
@@ -0,0 +1,13 @@
+package responses
+
+type LyricsLine struct {
+	Start *int64 `json:"start,omitempty" xml:"start,attr,omitempty"`
+	Value string `json:"value" xml:",chardata"`
+}
+
+func NewLyricsLine(start *int64, value string) *LyricsLine {
+	return &LyricsLine{
+		Start: start,
+		Value: value,
+	}
+}
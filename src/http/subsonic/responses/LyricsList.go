@@ -0,0 +1,11 @@
+package responses
+
+type LyricsList struct {
+	StructuredLyrics []StructuredLyrics `json:"structuredLyrics" xml:"structuredLyrics"`
+}
+
+func NewLyricsList(structuredLyrics []StructuredLyrics) *LyricsList {
+	return &LyricsList{
+		StructuredLyrics: structuredLyrics,
+	}
+}
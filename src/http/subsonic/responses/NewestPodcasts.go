@@ -1,8 +1,11 @@
 package responses
 
 type NewestPodcasts struct {
+	Episode []PodcastEpisode `json:"episode" xml:"episode"`
 }
 
-func NewNewestPodcasts() *NewestPodcasts {
-	return &NewestPodcasts{}
+func NewNewestPodcasts(episodes []PodcastEpisode) *NewestPodcasts {
+	return &NewestPodcasts{
+		Episode: episodes,
+	}
 }
@@ -0,0 +1,11 @@
+package responses
+
+type NowPlaying struct {
+	Entry []NowPlayingEntry `json:"entry" xml:"entry"`
+}
+
+func NewNowPlaying(entries []NowPlayingEntry) *NowPlaying {
+	return &NowPlaying{
+		Entry: entries,
+	}
+}
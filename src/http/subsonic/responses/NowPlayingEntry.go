@@ -0,0 +1,24 @@
+package responses
+
+type NowPlayingEntry struct {
+	SubsonicChild
+
+	Username   string `json:"username" xml:"username,attr"`
+	MinutesAgo int    `json:"minutesAgo" xml:"minutesAgo,attr"`
+	PlayerId   int    `json:"playerId" xml:"playerId,attr"`
+	PlayerName string `json:"playerName,omitempty" xml:"playerName,attr,omitempty"`
+}
+
+func NewNowPlayingEntry(
+	child SubsonicChild,
+	username string,
+	minutesAgo int,
+	playerId int,
+) *NowPlayingEntry {
+	return &NowPlayingEntry{
+		SubsonicChild: child,
+		Username:      username,
+		MinutesAgo:    minutesAgo,
+		PlayerId:      playerId,
+	}
+}
@@ -0,0 +1,16 @@
+package responses
+
+type OpenSubsonicExtension struct {
+	Name     string `json:"name" xml:"name,attr"`
+	Versions []int  `json:"versions" xml:"versions"`
+}
+
+func NewOpenSubsonicExtension(
+	name string,
+	versions []int,
+) *OpenSubsonicExtension {
+	return &OpenSubsonicExtension{
+		Name:     name,
+		Versions: versions,
+	}
+}
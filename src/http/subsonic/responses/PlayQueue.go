@@ -0,0 +1,27 @@
+package responses
+
+import "time"
+
+type PlayQueue struct {
+	Current   string    `json:"current,omitempty" xml:"current,attr,omitempty"`
+	Position  int64     `json:"position,omitempty" xml:"position,attr,omitempty"`
+	Username  string    `json:"username" xml:"username,attr"`
+	Changed   time.Time `json:"changed" xml:"changed,attr"`
+	ChangedBy string    `json:"changedBy" xml:"changedBy,attr"`
+
+	Entry []SubsonicChild `json:"entry" xml:"entry"`
+}
+
+func NewPlayQueue(
+	username string,
+	changed time.Time,
+	changedBy string,
+	entry []SubsonicChild,
+) *PlayQueue {
+	return &PlayQueue{
+		Username:  username,
+		Changed:   changed,
+		ChangedBy: changedBy,
+		Entry:     entry,
+	}
+}
@@ -0,0 +1,28 @@
+package responses
+
+type PodcastChannel struct {
+	Id               string `json:"id" xml:"id,attr"`
+	Url              string `json:"url" xml:"url,attr"`
+	Title            string `json:"title" xml:"title,attr"`
+	Description      string `json:"description" xml:"description,attr"`
+	CoverArt         string `json:"coverArt,omitempty" xml:"coverArt,attr,omitempty"`
+	OriginalImageUrl string `json:"originalImageUrl,omitempty" xml:"originalImageUrl,attr,omitempty"`
+	Status           string `json:"status" xml:"status,attr"`
+	ErrorMessage     string `json:"errorMessage,omitempty" xml:"errorMessage,attr,omitempty"`
+
+	Episode []PodcastEpisode `json:"episode,omitempty" xml:"episode"`
+}
+
+func NewPodcastChannel(
+	id string,
+	url string,
+	title string,
+	status string,
+) *PodcastChannel {
+	return &PodcastChannel{
+		Id:     id,
+		Url:    url,
+		Title:  title,
+		Status: status,
+	}
+}
@@ -0,0 +1,25 @@
+package responses
+
+import "time"
+
+type PodcastEpisode struct {
+	SubsonicChild
+
+	StreamId    string     `json:"streamId,omitempty" xml:"streamId,attr,omitempty"`
+	ChannelId   string     `json:"channelId" xml:"channelId,attr"`
+	Description string     `json:"description" xml:"description,attr"`
+	Status      string     `json:"status" xml:"status,attr"`
+	PublishDate *time.Time `json:"publishDate,omitempty" xml:"publishDate,attr,omitempty"`
+}
+
+func NewPodcastEpisode(
+	child SubsonicChild,
+	channelId string,
+	status string,
+) *PodcastEpisode {
+	return &PodcastEpisode{
+		SubsonicChild: child,
+		ChannelId:     channelId,
+		Status:        status,
+	}
+}
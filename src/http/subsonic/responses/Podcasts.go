@@ -1,8 +1,11 @@
 package responses
 
 type Podcasts struct {
+	Channel []PodcastChannel `json:"channel" xml:"channel"`
 }
 
-func NewPodcasts() *Podcasts {
-	return &Podcasts{}
+func NewPodcasts(channels []PodcastChannel) *Podcasts {
+	return &Podcasts{
+		Channel: channels,
+	}
 }
@@ -0,0 +1,10 @@
+package responses
+
+type ReplayGain struct {
+	TrackGain    *float64 `json:"trackGain,omitempty" xml:"trackGain,attr,omitempty"`
+	AlbumGain    *float64 `json:"albumGain,omitempty" xml:"albumGain,attr,omitempty"`
+	TrackPeak    *float64 `json:"trackPeak,omitempty" xml:"trackPeak,attr,omitempty"`
+	AlbumPeak    *float64 `json:"albumPeak,omitempty" xml:"albumPeak,attr,omitempty"`
+	BaseGain     *float64 `json:"baseGain,omitempty" xml:"baseGain,attr,omitempty"`
+	FallbackGain *float64 `json:"fallbackGain,omitempty" xml:"fallbackGain,attr,omitempty"`
+}
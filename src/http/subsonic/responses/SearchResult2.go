@@ -0,0 +1,19 @@
+package responses
+
+type SearchResult2 struct {
+	Artist []ArtistId3     `json:"artist" xml:"artist"`
+	Album  []SubsonicChild `json:"album" xml:"album"`
+	Song   []SubsonicChild `json:"song" xml:"song"`
+}
+
+func NewSearchResult2(
+	artists []ArtistId3,
+	albums []SubsonicChild,
+	songs []SubsonicChild,
+) *SearchResult2 {
+	return &SearchResult2{
+		Artist: artists,
+		Album:  albums,
+		Song:   songs,
+	}
+}
@@ -0,0 +1,32 @@
+package responses
+
+import "time"
+
+type Share struct {
+	Id          string     `json:"id" xml:"id,attr"`
+	Url         string     `json:"url" xml:"url,attr"`
+	Description string     `json:"description,omitempty" xml:"description,attr,omitempty"`
+	Username    string     `json:"username" xml:"username,attr"`
+	Created     time.Time  `json:"created" xml:"created,attr"`
+	Expires     *time.Time `json:"expires,omitempty" xml:"expires,attr,omitempty"`
+	LastVisited *time.Time `json:"lastVisited,omitempty" xml:"lastVisited,attr,omitempty"`
+	VisitCount  int        `json:"visitCount" xml:"visitCount,attr"`
+
+	Entry []SubsonicChild `json:"entry,omitempty" xml:"entry"`
+}
+
+func NewShare(
+	id string,
+	url string,
+	username string,
+	created time.Time,
+	visitCount int,
+) *Share {
+	return &Share{
+		Id:         id,
+		Url:        url,
+		Username:   username,
+		Created:    created,
+		VisitCount: visitCount,
+	}
+}
@@ -0,0 +1,11 @@
+package responses
+
+type Shares struct {
+	Share []Share `json:"share" xml:"share"`
+}
+
+func NewShares(shares []Share) *Shares {
+	return &Shares{
+		Share: shares,
+	}
+}
@@ -0,0 +1,11 @@
+package responses
+
+type SimilarSongs struct {
+	Song []SubsonicChild `json:"song" xml:"song"`
+}
+
+func NewSimilarSongs(songs []SubsonicChild) *SimilarSongs {
+	return &SimilarSongs{
+		Song: songs,
+	}
+}
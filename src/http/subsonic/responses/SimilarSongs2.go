@@ -0,0 +1,11 @@
+package responses
+
+type SimilarSongs2 struct {
+	Song []SubsonicChild `json:"song" xml:"song"`
+}
+
+func NewSimilarSongs2(songs []SubsonicChild) *SimilarSongs2 {
+	return &SimilarSongs2{
+		Song: songs,
+	}
+}
@@ -0,0 +1,11 @@
+package responses
+
+type SongsByGenre struct {
+	Song []SubsonicChild `json:"song" xml:"song"`
+}
+
+func NewSongsByGenre(songs []SubsonicChild) *SongsByGenre {
+	return &SongsByGenre{
+		Song: songs,
+	}
+}
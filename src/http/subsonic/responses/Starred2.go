@@ -1,8 +1,19 @@
 package responses
 
 type Starred2 struct {
+	Artist []ArtistId3     `json:"artist" xml:"artist"`
+	Album  []AlbumId3      `json:"album" xml:"album"`
+	Song   []SubsonicChild `json:"song" xml:"song"`
 }
 
-func NewStarred2() *Starred2 {
-	return &Starred2{}
+func NewStarred2(
+	artists []ArtistId3,
+	albums []AlbumId3,
+	songs []SubsonicChild,
+) *Starred2 {
+	return &Starred2{
+		Artist: artists,
+		Album:  albums,
+		Song:   songs,
+	}
 }
@@ -0,0 +1,22 @@
+package responses
+
+type StructuredLyrics struct {
+	DisplayArtist string       `json:"displayArtist,omitempty" xml:"displayArtist,attr,omitempty"`
+	DisplayTitle  string       `json:"displayTitle,omitempty" xml:"displayTitle,attr,omitempty"`
+	Lang          string       `json:"lang" xml:"lang,attr"`
+	Offset        int64        `json:"offset,omitempty" xml:"offset,attr,omitempty"`
+	Synced        bool         `json:"synced" xml:"synced,attr"`
+	Line          []LyricsLine `json:"line" xml:"line"`
+}
+
+func NewStructuredLyrics(
+	lang string,
+	synced bool,
+	line []LyricsLine,
+) *StructuredLyrics {
+	return &StructuredLyrics{
+		Lang:   lang,
+		Synced: synced,
+		Line:   line,
+	}
+}
@@ -1,7 +1,10 @@
 package responses
 
+import "time"
+
 type SubsonicChild struct {
 	Id        string `json:"id" xml:"id,attr"`
+	Parent    string `json:"parent,omitempty" xml:"parent,attr,omitempty"`
 	IsDir     bool   `json:"isDir" xml:"isDir,attr"`
 	Artist    string `json:"artist" xml:"artist,attr"`
 	ArtistId  string `json:"artistId" xml:"artistId,attr"`
@@ -12,8 +15,26 @@ type SubsonicChild struct {
 	Duration  int    `json:"duration" xml:"duration,attr"`
 	PlayCount int    `json:"playCount" xml:"playCount,attr"`
 	AlbumId   string `json:"albumId" xml:"albumId,attr"`
+	Genre     string `json:"genre,omitempty" xml:"genre,attr,omitempty"`
+
+	Starred *time.Time `json:"starred" xml:"starred,attr,omitempty"`
+
+	UserRating    int     `json:"userRating,omitempty" xml:"userRating,attr,omitempty"`
+	AverageRating float64 `json:"averageRating,omitempty" xml:"averageRating,attr,omitempty"`
+
+	// OpenSubsonic
+	MediaType     string      `json:"mediaType,omitempty" xml:"mediaType,attr,omitempty"`
+	Played        *time.Time  `json:"played,omitempty" xml:"played,attr,omitempty"`
+	Artists       []ArtistId3 `json:"artists,omitempty" xml:"artists,omitempty"`
+	ReplayGain    *ReplayGain `json:"replayGain,omitempty" xml:"replayGain,omitempty"`
+	MusicBrainzId string      `json:"musicBrainzId,omitempty" xml:"musicBrainzId,attr,omitempty"`
 }
 
+const (
+	MEDIA_TYPE_SONG    = "song"
+	MEDIA_TYPE_PODCAST = "podcast"
+)
+
 func NewSubsonicChild(
 	id string,
 	isDir bool,
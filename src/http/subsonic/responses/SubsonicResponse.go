@@ -15,7 +15,13 @@ const (
 	ERROR_CODE_GENERIC           = 0
 	ERROR_CODE_PARAMETER_MISSING = 10
 	ERROR_CODE_NOT_AUTHENTICATED = 40
-	ERROR_CODE_NOT_FOUND         = 70
+
+	ERROR_CODE_AUTH_NOT_SUPPORTED = 42
+	ERROR_CODE_CONFLICTING_AUTH   = 43
+	ERROR_CODE_INVALID_API_KEY    = 44
+
+	ERROR_CODE_NOT_AUTHORIZED = 50
+	ERROR_CODE_NOT_FOUND      = 70
 )
 
 type SubsonicResponseWrapper struct {
@@ -32,23 +38,42 @@ type SubsonicResponse struct {
 
 	Error *subsonicError `json:"error,omitempty" xml:"error"`
 
-	AlbumList2            *AlbumList2            `json:"albumList2,omitempty" xml:"albumList2"`
-	Album                 *AlbumId3              `json:"album,omitempty" xml:"album"`
-	Artists               *Artists               `json:"artists,omitempty" xml:"artists"`
-	Artist                *Artist                `json:"artist,omitempty" xml:"artist"`
-	Genres                *Genres                `json:"genres,omitempty" xml:"genres"`
-	InternetRadioStations *InternetRadioStations `json:"internetRadioStations,omitempty" xml:"internetRadioStations"`
-	License               *License               `json:"license,omitempty" xml:"license"`
-	MusicFolders          *MusicFolders          `json:"musicFolders,omitempty" xml:"musicFolders"`
-	NewestPodcasts        *NewestPodcasts        `json:"newestPodcasts,omitempty" xml:"newestPodcasts"`
-	Playlists             *SubsonicPlaylists     `json:"playlists,omitempty" xml:"playlists"`
-	Playlist              *SubsonicPlaylist      `json:"playlist,omitempty" xml:"playlist"`
-	Podcasts              *Podcasts              `json:"podcasts,omitempty" xml:"podcasts"`
-	RandomSongs           *RandomSongs           `json:"randomSongs,omitempty" xml:"randomSongs"`
-	ScanStatus            *ScanStatus            `json:"scanStatus,omitempty" xml:"scanStatus"`
-	SearchResult3         *SearchResult3         `json:"searchResult3,omitempty" xml:"searchResult3"`
-	Song                  *SubsonicChild         `json:"song,omitempty" xml:"song"`
-	Starred2              *Starred2              `json:"starred2,omitempty" xml:"starred2"`
+	AlbumList              *AlbumList              `json:"albumList,omitempty" xml:"albumList"`
+	AlbumList2             *AlbumList2             `json:"albumList2,omitempty" xml:"albumList2"`
+	Album                  *AlbumId3               `json:"album,omitempty" xml:"album"`
+	Artists                *Artists                `json:"artists,omitempty" xml:"artists"`
+	Artist                 *Artist                 `json:"artist,omitempty" xml:"artist"`
+	ArtistInfo             *ArtistInfo             `json:"artistInfo,omitempty" xml:"artistInfo"`
+	ArtistInfo2            *ArtistInfo2            `json:"artistInfo2,omitempty" xml:"artistInfo2"`
+	Bookmarks              *Bookmarks              `json:"bookmarks,omitempty" xml:"bookmarks"`
+	Directory              *Directory              `json:"directory,omitempty" xml:"directory"`
+	Genres                 *Genres                 `json:"genres,omitempty" xml:"genres"`
+	Indexes                *Indexes                `json:"indexes,omitempty" xml:"indexes"`
+	InternetRadioStations  *InternetRadioStations  `json:"internetRadioStations,omitempty" xml:"internetRadioStations"`
+	License                *License                `json:"license,omitempty" xml:"license"`
+	Lyrics                 *Lyrics                 `json:"lyrics,omitempty" xml:"lyrics"`
+	LyricsList             *LyricsList             `json:"lyricsList,omitempty" xml:"lyricsList"`
+	MusicFolders           *MusicFolders           `json:"musicFolders,omitempty" xml:"musicFolders"`
+	NewestPodcasts         *NewestPodcasts         `json:"newestPodcasts,omitempty" xml:"newestPodcasts"`
+	OpenSubsonicExtensions []OpenSubsonicExtension `json:"openSubsonicExtensions,omitempty" xml:"openSubsonicExtensions"`
+	PlayQueue              *PlayQueue              `json:"playQueue,omitempty" xml:"playQueue"`
+	NowPlaying             *NowPlaying             `json:"nowPlaying,omitempty" xml:"nowPlaying"`
+	Playlists              *SubsonicPlaylists      `json:"playlists,omitempty" xml:"playlists"`
+	Playlist               *SubsonicPlaylist       `json:"playlist,omitempty" xml:"playlist"`
+	Podcasts               *Podcasts               `json:"podcasts,omitempty" xml:"podcasts"`
+	RandomSongs            *RandomSongs            `json:"randomSongs,omitempty" xml:"randomSongs"`
+	ScanStatus             *ScanStatus             `json:"scanStatus,omitempty" xml:"scanStatus"`
+	SearchResult2          *SearchResult2          `json:"searchResult2,omitempty" xml:"searchResult2"`
+	SearchResult3          *SearchResult3          `json:"searchResult3,omitempty" xml:"searchResult3"`
+	Shares                 *Shares                 `json:"shares,omitempty" xml:"shares"`
+	SimilarSongs           *SimilarSongs           `json:"similarSongs,omitempty" xml:"similarSongs"`
+	SimilarSongs2          *SimilarSongs2          `json:"similarSongs2,omitempty" xml:"similarSongs2"`
+	Song                   *SubsonicChild          `json:"song,omitempty" xml:"song"`
+	SongsByGenre           *SongsByGenre           `json:"songsByGenre,omitempty" xml:"songsByGenre"`
+	Starred2               *Starred2               `json:"starred2,omitempty" xml:"starred2"`
+	TopSongs               *TopSongs               `json:"topSongs,omitempty" xml:"topSongs"`
+	User                   *User                   `json:"user,omitempty" xml:"user"`
+	Users                  *Users                  `json:"users,omitempty" xml:"users"`
 }
 
 type subsonicError struct {
@@ -89,6 +114,22 @@ func NewNotAuthenticatedResponse() *SubsonicResponse {
 	return NewFailedResponse(ERROR_CODE_NOT_AUTHENTICATED, "Wrong username/password or username/token")
 }
 
+func NewAuthNotSupportedResponse(message string) *SubsonicResponse {
+	return NewFailedResponse(ERROR_CODE_AUTH_NOT_SUPPORTED, message)
+}
+
+func NewConflictingAuthResponse() *SubsonicResponse {
+	return NewFailedResponse(ERROR_CODE_CONFLICTING_AUTH, "Multiple conflicting authentication mechanisms provided")
+}
+
+func NewInvalidApiKeyResponse() *SubsonicResponse {
+	return NewFailedResponse(ERROR_CODE_INVALID_API_KEY, "Invalid API key")
+}
+
+func NewNotAuthorizedResponse(message string) *SubsonicResponse {
+	return NewFailedResponse(ERROR_CODE_NOT_AUTHORIZED, message)
+}
+
 func NewNotFoundResponse(what string) *SubsonicResponse {
 	return NewFailedResponse(ERROR_CODE_NOT_FOUND, fmt.Sprintf("Not found: %s", what))
 }
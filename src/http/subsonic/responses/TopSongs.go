@@ -0,0 +1,11 @@
+package responses
+
+type TopSongs struct {
+	Song []SubsonicChild `json:"song" xml:"song"`
+}
+
+func NewTopSongs(songs []SubsonicChild) *TopSongs {
+	return &TopSongs{
+		Song: songs,
+	}
+}
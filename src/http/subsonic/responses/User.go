@@ -0,0 +1,40 @@
+package responses
+
+type User struct {
+	Username            string `json:"username" xml:"username,attr"`
+	Email               string `json:"email,omitempty" xml:"email,attr,omitempty"`
+	ScrobblingEnabled   bool   `json:"scrobblingEnabled" xml:"scrobblingEnabled,attr"`
+	AdminRole           bool   `json:"adminRole" xml:"adminRole,attr"`
+	SettingsRole        bool   `json:"settingsRole" xml:"settingsRole,attr"`
+	DownloadRole        bool   `json:"downloadRole" xml:"downloadRole,attr"`
+	UploadRole          bool   `json:"uploadRole" xml:"uploadRole,attr"`
+	PlaylistRole        bool   `json:"playlistRole" xml:"playlistRole,attr"`
+	CoverArtRole        bool   `json:"coverArtRole" xml:"coverArtRole,attr"`
+	CommentRole         bool   `json:"commentRole" xml:"commentRole,attr"`
+	PodcastRole         bool   `json:"podcastRole" xml:"podcastRole,attr"`
+	StreamRole          bool   `json:"streamRole" xml:"streamRole,attr"`
+	JukeboxRole         bool   `json:"jukeboxRole" xml:"jukeboxRole,attr"`
+	ShareRole           bool   `json:"shareRole" xml:"shareRole,attr"`
+	VideoConversionRole bool   `json:"videoConversionRole" xml:"videoConversionRole,attr"`
+}
+
+// tapesonic only has admins and stream-only users, the subsonic roles are derived from that
+func NewUser(
+	username string,
+	email string,
+	isAdmin bool,
+) *User {
+	return &User{
+		Username:          username,
+		Email:             email,
+		ScrobblingEnabled: true,
+		AdminRole:         isAdmin,
+		SettingsRole:      isAdmin,
+		DownloadRole:      isAdmin,
+		PlaylistRole:      true,
+		CoverArtRole:      true,
+		PodcastRole:       isAdmin,
+		ShareRole:         isAdmin,
+		StreamRole:        true,
+	}
+}
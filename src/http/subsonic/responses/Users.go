@@ -0,0 +1,11 @@
+package responses
+
+type Users struct {
+	User []User `json:"user" xml:"user"`
+}
+
+func NewUsers(users []User) *Users {
+	return &Users{
+		User: users,
+	}
+}
@@ -1,66 +1,150 @@
 package util
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
 	"net/http"
 	"strings"
 
-	"tapesonic/config"
 	"tapesonic/http/subsonic/responses"
 )
 
-func Authenticated(handler http.HandlerFunc, config *config.TapesonicConfig) http.HandlerFunc {
+type User struct {
+	Username string
+	Password string
+	IsAdmin  bool
+}
+
+// both return nil if there's no such user
+type UserProvider interface {
+	FindUser(username string) (*User, error)
+	FindUserByApiKey(apiKey string) (*User, error)
+}
+
+const (
+	AUTH_METHOD_NONE     = "none"
+	AUTH_METHOD_API_KEY  = "apiKey"
+	AUTH_METHOD_TOKEN    = "token"
+	AUTH_METHOD_PASSWORD = "password"
+)
+
+type userContextKey struct{}
+
+func Authenticated(handler http.HandlerFunc, users UserProvider, allowPasswordAuth bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
+		user, failedResponse, err := authenticate(r, users, allowPasswordAuth)
+		if err != nil {
+			LogError(r, fmt.Sprintf("Failed to authenticate request: %s", err.Error()))
+			writeResponse(w, r, responses.NewServerErrorResponse("Server failed to process the request"))
+			return
+		}
+		if failedResponse != nil {
+			writeResponse(w, r, failedResponse)
+			return
+		}
+
+		handler(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, *user)))
+	}
+}
 
-		username := query.Get(SUBSONIC_QUERY_USERNAME)
+func authenticate(r *http.Request, users UserProvider, allowPasswordAuth bool) (*User, *responses.SubsonicResponse, error) {
+	query := r.URL.Query()
+
+	username := query.Get(SUBSONIC_QUERY_USERNAME)
+	password := query.Get(SUBSONIC_QUERY_PASSWORD)
+	token := query.Get(SUBSONIC_QUERY_TOKEN)
+	salt := query.Get(SUBSONIC_QUERY_SALT)
+
+	switch getAuthMethod(r) {
+	case AUTH_METHOD_API_KEY:
+		if username != "" || password != "" || token != "" {
+			LogDebug(r, "Request has both an api key and user credentials")
+			return nil, responses.NewConflictingAuthResponse(), nil
+		}
+
+		user, err := users.FindUserByApiKey(query.Get(SUBSONIC_QUERY_API_KEY))
+		if err != nil {
+			return nil, nil, err
+		}
+		if user == nil {
+			LogWarning(r, "Request failed authentication with an invalid api key")
+			return nil, responses.NewInvalidApiKeyResponse(), nil
+		}
+
+		return user, nil, nil
+	case AUTH_METHOD_TOKEN:
 		if username == "" {
-			response := responses.NewParameterMissingResponse(SUBSONIC_QUERY_USERNAME)
-			LogDebug(r, response.Error.Message)
-			writeResponse(w, r, response)
-			return
+			return nil, missingParameter(r, SUBSONIC_QUERY_USERNAME), nil
+		}
+		if salt == "" {
+			return nil, missingParameter(r, SUBSONIC_QUERY_SALT), nil
 		}
 
-		password := query.Get(SUBSONIC_QUERY_PASSWORD)
-
-		token := query.Get(SUBSONIC_QUERY_TOKEN)
-		salt := query.Get(SUBSONIC_QUERY_SALT)
-
-		var isAuthenticated bool
-		if token != "" {
-			if salt == "" {
-				response := responses.NewParameterMissingResponse(SUBSONIC_QUERY_SALT)
-				LogDebug(r, response.Error.Message)
-				writeResponse(w, r, response)
-				return
-			}
-
-			isAuthenticated = authenticateByToken(
-				username,
-				token,
-				salt,
-				config.Username,
-				config.Password,
-			)
-		} else if password != "" {
-			isAuthenticated = authenticateByPassword(
-				username,
-				password,
-				config.Username,
-				config.Password,
-			)
-		} else {
-			response := responses.NewParameterMissingResponse(SUBSONIC_QUERY_PASSWORD)
-			LogDebug(r, response.Error.Message)
-			writeResponse(w, r, response)
-			return
+		user, err := users.FindUser(username)
+		if err != nil {
+			return nil, nil, err
+		}
+		if user == nil || !authenticateByToken(token, salt, user.Password) {
+			LogWarning(r, "Request failed authentication")
+			return nil, responses.NewNotAuthenticatedResponse(), nil
+		}
+
+		return user, nil, nil
+	case AUTH_METHOD_PASSWORD:
+		if !allowPasswordAuth {
+			LogWarning(r, "Request tried to authenticate with a password while password authentication is disabled")
+			return nil, responses.NewAuthNotSupportedResponse("Password authentication is disabled, use token or api key authentication"), nil
+		}
+		if username == "" {
+			return nil, missingParameter(r, SUBSONIC_QUERY_USERNAME), nil
 		}
 
-		if !isAuthenticated {
+		user, err := users.FindUser(username)
+		if err != nil {
+			return nil, nil, err
+		}
+		if user == nil || !authenticateByPassword(password, user.Password) {
 			LogWarning(r, "Request failed authentication")
-			writeResponse(w, r, responses.NewNotAuthenticatedResponse())
+			return nil, responses.NewNotAuthenticatedResponse(), nil
+		}
+
+		return user, nil, nil
+	default:
+		if username == "" {
+			return nil, missingParameter(r, SUBSONIC_QUERY_USERNAME), nil
+		}
+		return nil, missingParameter(r, SUBSONIC_QUERY_PASSWORD), nil
+	}
+}
+
+func getAuthMethod(r *http.Request) string {
+	query := r.URL.Query()
+
+	if query.Get(SUBSONIC_QUERY_API_KEY) != "" {
+		return AUTH_METHOD_API_KEY
+	} else if query.Get(SUBSONIC_QUERY_TOKEN) != "" {
+		return AUTH_METHOD_TOKEN
+	} else if query.Get(SUBSONIC_QUERY_PASSWORD) != "" {
+		return AUTH_METHOD_PASSWORD
+	} else {
+		return AUTH_METHOD_NONE
+	}
+}
+
+func missingParameter(r *http.Request, name string) *responses.SubsonicResponse {
+	response := responses.NewParameterMissingResponse(name)
+	LogDebug(r, response.Error.Message)
+	return response
+}
+
+// handlers for managing the server are off limits for stream-only users
+func AdminOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !IsAdmin(r) {
+			LogWarning(r, "Non-admin user tried to access an admin-only endpoint")
+			writeResponse(w, r, responses.NewNotAuthorizedResponse("User is not authorized for the given operation"))
 			return
 		}
 
@@ -68,18 +152,29 @@ func Authenticated(handler http.HandlerFunc, config *config.TapesonicConfig) htt
 	}
 }
 
-func authenticateByPassword(username string, password string, usernameConfig string, passwordConfig string) bool {
-	if username != usernameConfig {
-		return false
-	}
+func IsAdmin(r *http.Request) bool {
+	user, ok := r.Context().Value(userContextKey{}).(User)
+	return ok && user.IsAdmin
+}
 
+func authenticateByPassword(password string, expectedPassword string) bool {
 	if strings.HasPrefix(password, "enc:") {
-		return password == encodePassword(passwordConfig)
+		return password == encodePassword(expectedPassword)
 	} else {
-		return password == passwordConfig
+		return password == expectedPassword
 	}
 }
 
+// DecodePassword handles passwords in the `enc:` hex form, which clients may also use when setting passwords
+func DecodePassword(password string) (string, error) {
+	if !strings.HasPrefix(password, "enc:") {
+		return password, nil
+	}
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(password, "enc:"))
+	return string(decoded), err
+}
+
 func encodePassword(password string) string {
 	parts := []string{}
 
@@ -90,12 +185,8 @@ func encodePassword(password string) string {
 	return "enc:" + strings.Join(parts, "")
 }
 
-func authenticateByToken(username string, token string, salt string, usernameConfig string, passwordConfig string) bool {
-	if username != usernameConfig {
-		return false
-	}
-
-	return GenerateToken(passwordConfig, salt) == token
+func authenticateByToken(token string, salt string, expectedPassword string) bool {
+	return GenerateToken(expectedPassword, salt) == token
 }
 
 func GenerateToken(password string, salt string) string {
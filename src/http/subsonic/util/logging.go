@@ -17,6 +17,8 @@ var sensitiveFields = []string{
 	SUBSONIC_QUERY_PASSWORD,
 	SUBSONIC_QUERY_TOKEN,
 	SUBSONIC_QUERY_SALT,
+	SUBSONIC_QUERY_API_KEY,
+	"password", // createUser/updateUser
 }
 
 func Logged(handler http.HandlerFunc) http.HandlerFunc {
@@ -24,7 +26,7 @@ func Logged(handler http.HandlerFunc) http.HandlerFunc {
 		requestId := uuid.New().String()
 		r.Header.Add(requestTraceHeader, requestId)
 
-		LogInfo(r, fmt.Sprintf("Serving request %s %s", r.Method, maskUrl(*r.URL)))
+		LogInfo(r, fmt.Sprintf("Serving request %s %s", r.Method, maskUrl(*r.URL)), "client", GetClientName(r), "auth", getAuthMethod(r))
 		handler(w, r)
 	}
 }
@@ -3,7 +3,10 @@ package util
 import (
 	"encoding/json"
 	"encoding/xml"
+	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
 
 	"tapesonic/http/subsonic/responses"
 )
@@ -13,15 +16,38 @@ const (
 	SUBSONIC_QUERY_PASSWORD = "p"
 	SUBSONIC_QUERY_SALT     = "s"
 	SUBSONIC_QUERY_TOKEN    = "t"
+	SUBSONIC_QUERY_API_KEY  = "apiKey"
 	SUBSONIC_QUERY_CLIENT   = "c"
+	SUBSONIC_QUERY_VERSION  = "v"
 	SUBSONIC_QUERY_FORMAT   = "f"
+	SUBSONIC_QUERY_CALLBACK = "callback"
 )
 
 const (
-	SUBSONIC_FORMAT_XML  = "xml"
-	SUBSONIC_FORMAT_JSON = "json"
+	SUBSONIC_FORMAT_XML   = "xml"
+	SUBSONIC_FORMAT_JSON  = "json"
+	SUBSONIC_FORMAT_JSONP = "jsonp"
 )
 
+var jsonpCallbackRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$.]*$`)
+
+// POST requests with form-encoded bodies are merged into the query so handlers don't have to care where the params came from
+func WithFormParams(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if err := r.ParseForm(); err != nil {
+				LogWarning(r, fmt.Sprintf("Failed to parse form: %s", err.Error()))
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			r.URL.RawQuery = r.Form.Encode()
+		}
+
+		handler(w, r)
+	}
+}
+
 func writeResponse(w http.ResponseWriter, r *http.Request, response *responses.SubsonicResponse) {
 	wrappedResponse := responses.SubsonicResponseWrapper{
 		SubsonicResponse: *response,
@@ -31,6 +57,23 @@ func writeResponse(w http.ResponseWriter, r *http.Request, response *responses.S
 	case SUBSONIC_FORMAT_JSON:
 		w.Header().Add("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(wrappedResponse)
+	case SUBSONIC_FORMAT_JSONP:
+		callback := r.URL.Query().Get(SUBSONIC_QUERY_CALLBACK)
+		if !jsonpCallbackRegex.MatchString(callback) {
+			LogError(r, "Invalid JSONP callback", "callback", callback)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body, err := json.Marshal(wrappedResponse)
+		if err != nil {
+			LogError(r, fmt.Sprintf("Failed to serialize response: %s", err.Error()))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/javascript")
+		fmt.Fprintf(w, "%s(%s);", callback, body)
 	case SUBSONIC_FORMAT_XML, "":
 		w.Header().Add("Content-Type", "application/xml")
 		xml.NewEncoder(w).Encode(wrappedResponse)
@@ -40,10 +83,39 @@ func writeResponse(w http.ResponseWriter, r *http.Request, response *responses.S
 	}
 }
 
+func GetUsername(r *http.Request) string {
+	if user, ok := r.Context().Value(userContextKey{}).(User); ok {
+		return user.Username
+	}
+	return r.URL.Query().Get(SUBSONIC_QUERY_USERNAME)
+}
+
 func GetClientName(r *http.Request) string {
 	return r.URL.Query().Get(SUBSONIC_QUERY_CLIENT)
 }
 
+// GetAuthParams returns the params which let urls generated by the server, like HLS segments,
+// be requested by the client with the same credentials as the original request
+func GetAuthParams(r *http.Request) url.Values {
+	query := r.URL.Query()
+
+	result := url.Values{}
+	for _, name := range []string{
+		SUBSONIC_QUERY_USERNAME,
+		SUBSONIC_QUERY_PASSWORD,
+		SUBSONIC_QUERY_SALT,
+		SUBSONIC_QUERY_TOKEN,
+		SUBSONIC_QUERY_API_KEY,
+		SUBSONIC_QUERY_CLIENT,
+		SUBSONIC_QUERY_VERSION,
+	} {
+		if value := query.Get(name); value != "" {
+			result.Set(name, value)
+		}
+	}
+	return result
+}
+
 func getFormat(r *http.Request) string {
 	return r.URL.Query().Get(SUBSONIC_QUERY_FORMAT)
 }
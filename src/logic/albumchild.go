@@ -0,0 +1,19 @@
+package logic
+
+import "tapesonic/http/subsonic/responses"
+
+// adapts an ID3 album for non-ID3 endpoints where albums are represented as directories
+func NewAlbumChild(album responses.AlbumId3) responses.SubsonicChild {
+	child := responses.NewSubsonicChild(album.Id, true, album.Artist, album.Name, 0, album.Duration)
+	child.Parent = album.ArtistId
+	child.ArtistId = album.ArtistId
+	child.Album = album.Name
+	child.AlbumId = album.Id
+	child.CoverArt = album.CoverArt
+	child.PlayCount = album.PlayCount
+	child.Genre = album.Genre
+	child.Starred = album.Starred
+	child.UserRating = album.UserRating
+	child.AverageRating = album.AverageRating
+	return *child
+}
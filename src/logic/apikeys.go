@@ -0,0 +1,128 @@
+package logic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"tapesonic/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// the usage time is only informational, so it isn't written on every request
+const apiKeyLastUsedPrecision = time.Minute
+
+type ApiKey struct {
+	Id         uuid.UUID
+	Username   string
+	Name       string
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type IssuedApiKey struct {
+	ApiKey
+	Key string
+}
+
+type ApiKeyService struct {
+	storage *storage.ApiKeyStorage
+	users   *storage.UserStorage
+}
+
+func NewApiKeyService(
+	storage *storage.ApiKeyStorage,
+	users *storage.UserStorage,
+) *ApiKeyService {
+	return &ApiKeyService{
+		storage: storage,
+		users:   users,
+	}
+}
+
+func (s *ApiKeyService) Issue(username string, name string) (IssuedApiKey, error) {
+	user, err := s.users.Find(username)
+	if err != nil {
+		return IssuedApiKey{}, err
+	} else if user == nil {
+		return IssuedApiKey{}, ErrUserNotFound
+	}
+
+	rawKey := make([]byte, 32)
+	if _, err := rand.Read(rawKey); err != nil {
+		return IssuedApiKey{}, err
+	}
+	key := hex.EncodeToString(rawKey)
+
+	apiKey, err := s.storage.Create(storage.ApiKey{
+		Username: username,
+		Name:     name,
+		KeyHash:  hashApiKey(key),
+	})
+	if err != nil {
+		return IssuedApiKey{}, err
+	}
+
+	return IssuedApiKey{
+		ApiKey: toApiKey(apiKey),
+		Key:    key,
+	}, nil
+}
+
+func (s *ApiKeyService) GetAll() ([]ApiKey, error) {
+	apiKeys, err := s.storage.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []ApiKey{}
+	for _, apiKey := range apiKeys {
+		result = append(result, toApiKey(apiKey))
+	}
+	return result, nil
+}
+
+func (s *ApiKeyService) Revoke(id uuid.UUID) error {
+	return s.storage.DeleteById(id)
+}
+
+// FindUsername returns an empty username if the key is unknown
+func (s *ApiKeyService) FindUsername(key string) (string, error) {
+	if key == "" {
+		return "", errors.New("api key must be provided")
+	}
+
+	apiKey, err := s.storage.FindByHash(hashApiKey(key))
+	if err != nil || apiKey == nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedPrecision {
+		if err := s.storage.MarkUsed(apiKey.Id, now); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to update last usage time of api key id=%s: %s", apiKey.Id, err.Error()))
+		}
+	}
+
+	return apiKey.Username, nil
+}
+
+// keys are random enough for a plain hash to be safe, unlike passwords
+func hashApiKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+func toApiKey(apiKey storage.ApiKey) ApiKey {
+	return ApiKey{
+		Id:         apiKey.Id,
+		Username:   apiKey.Username,
+		Name:       apiKey.Name,
+		LastUsedAt: apiKey.LastUsedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,94 @@
+package logic
+
+import (
+	"slices"
+	"strings"
+	"tapesonic/http/subsonic/responses"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DEFAULT_IGNORED_ARTICLES = "The El La Los Las Le Les"
+
+	otherArtistsIndexName = "#"
+)
+
+func NewArtistIndex(artists []responses.ArtistId3, ignoredArticles string) []responses.IndexId3 {
+	articles := strings.Fields(ignoredArticles)
+
+	type sortableArtist struct {
+		artist   responses.ArtistId3
+		sortName string
+	}
+
+	sortableArtists := []sortableArtist{}
+	for _, artist := range artists {
+		sortableArtists = append(sortableArtists, sortableArtist{
+			artist:   artist,
+			sortName: strings.ToLower(removeArticle(strings.TrimSpace(artist.Name), articles)),
+		})
+	}
+
+	slices.SortStableFunc(sortableArtists, func(a sortableArtist, b sortableArtist) int {
+		return strings.Compare(a.sortName, b.sortName)
+	})
+
+	index := []responses.IndexId3{}
+	for _, artist := range sortableArtists {
+		indexName := getArtistIndexName(artist.sortName)
+
+		if len(index) == 0 || index[len(index)-1].Name != indexName {
+			index = append(index, *responses.NewIndexId3(indexName, []responses.ArtistId3{}))
+		}
+
+		index[len(index)-1].Artist = append(index[len(index)-1].Artist, artist.artist)
+	}
+
+	// letters are sorted before non-letters by convention
+	slices.SortStableFunc(index, func(a responses.IndexId3, b responses.IndexId3) int {
+		if a.Name == otherArtistsIndexName && b.Name != otherArtistsIndexName {
+			return 1
+		}
+		if a.Name != otherArtistsIndexName && b.Name == otherArtistsIndexName {
+			return -1
+		}
+		return 0
+	})
+
+	return mergeArtistIndexes(index)
+}
+
+func removeArticle(name string, articles []string) string {
+	for _, article := range articles {
+		prefix := article + " "
+		if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+			return strings.TrimSpace(name[len(prefix):])
+		}
+	}
+
+	return name
+}
+
+func getArtistIndexName(sortName string) string {
+	firstLetter, _ := utf8.DecodeRuneInString(sortName)
+	if firstLetter == utf8.RuneError || !unicode.IsLetter(firstLetter) {
+		return otherArtistsIndexName
+	}
+
+	return string(unicode.ToUpper(firstLetter))
+}
+
+// non-letter artists can be split into multiple buckets by sorting
+func mergeArtistIndexes(index []responses.IndexId3) []responses.IndexId3 {
+	result := []responses.IndexId3{}
+	for _, item := range index {
+		if len(result) > 0 && result[len(result)-1].Name == item.Name {
+			result[len(result)-1].Artist = append(result[len(result)-1].Artist, item.Artist...)
+		} else {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+package logic_test
+
+import (
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/logic"
+	"testing"
+)
+
+func TestNewArtistIndex(t *testing.T) {
+	artists := []responses.ArtistId3{
+		{Id: "1", Name: "The Beatles"},
+		{Id: "2", Name: "ABBA"},
+		{Id: "3", Name: "2Pac"},
+		{Id: "4", Name: "björk"},
+		{Id: "5", Name: "Theatre of Tragedy"},
+		{Id: "6", Name: "Los Lobos"},
+		{Id: "7", Name: "...And You Will Know Us"},
+	}
+
+	index := logic.NewArtistIndex(artists, logic.DEFAULT_IGNORED_ARTICLES)
+
+	expected := map[string][]string{
+		"A": {"2"},
+		"B": {"1", "4"},
+		"L": {"6"},
+		"T": {"5"},
+		"#": {"7", "3"},
+	}
+	expectedOrder := []string{"A", "B", "L", "T", "#"}
+
+	if len(index) != len(expectedOrder) {
+		t.Fatalf("Expected %d index entries, got %d: %v", len(expectedOrder), len(index), index)
+	}
+
+	for i, item := range index {
+		if item.Name != expectedOrder[i] {
+			t.Errorf("Expected index entry %d to be %s, got %s", i, expectedOrder[i], item.Name)
+			continue
+		}
+
+		ids := []string{}
+		for _, artist := range item.Artist {
+			ids = append(ids, artist.Id)
+		}
+
+		if len(ids) != len(expected[item.Name]) {
+			t.Errorf("Expected artists %v in index entry %s, got %v", expected[item.Name], item.Name, ids)
+			continue
+		}
+		for j := range ids {
+			if ids[j] != expected[item.Name][j] {
+				t.Errorf("Expected artists %v in index entry %s, got %v", expected[item.Name], item.Name, ids)
+				break
+			}
+		}
+	}
+}
@@ -49,12 +49,13 @@ func (s *LastFmService) CreateAuthLink() (LastFmAuthLink, error) {
 }
 
 type LastFmSession struct {
+	Owner      string
 	Username   string
 	SessionKey string
 	UpdatedAt  time.Time
 }
 
-func (s *LastFmService) CreateSession(token string) (LastFmSession, error) {
+func (s *LastFmService) CreateSession(owner string, token string) (LastFmSession, error) {
 	if s.client == nil {
 		return LastFmSession{}, ErrLastFmNotConfigured
 	}
@@ -65,6 +66,7 @@ func (s *LastFmService) CreateSession(token string) (LastFmSession, error) {
 	}
 
 	savedSession, err := s.sessions.Save(storage.LastFmSession{
+		Owner:      owner,
 		SessionKey: session.Session.Key,
 		Username:   session.Session.Name,
 	})
@@ -72,34 +74,40 @@ func (s *LastFmService) CreateSession(token string) (LastFmSession, error) {
 		return LastFmSession{}, err
 	}
 
-	return LastFmSession{
-		Username:   savedSession.Username,
-		SessionKey: savedSession.SessionKey,
-		UpdatedAt:  savedSession.UpdatedAt,
-	}, nil
+	return toLastFmSession(savedSession), nil
 }
 
-func (s *LastFmService) GetCurrentSession() (*LastFmSession, error) {
-	session, err := s.sessions.Find()
+func (s *LastFmService) GetSession(owner string) (*LastFmSession, error) {
+	session, err := s.sessions.Find(owner)
 	if err != nil {
 		return &LastFmSession{}, err
 	} else if session == nil {
 		return nil, nil
 	}
 
-	return &LastFmSession{
-		Username:   session.Username,
-		SessionKey: session.SessionKey,
-		UpdatedAt:  session.UpdatedAt,
-	}, err
+	result := toLastFmSession(*session)
+	return &result, err
+}
+
+func (s *LastFmService) GetSessions() ([]LastFmSession, error) {
+	sessions, err := s.sessions.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []LastFmSession{}
+	for _, session := range sessions {
+		result = append(result, toLastFmSession(session))
+	}
+	return result, nil
 }
 
-func (s *LastFmService) UpdateNowPlaying(artist string, title string, album string) error {
+func (s *LastFmService) UpdateNowPlaying(owner string, artist string, title string, album string) error {
 	if s.client == nil {
 		return ErrLastFmNotConfigured
 	}
 
-	session, err := s.sessions.Find()
+	session, err := s.sessions.Find(owner)
 	if err != nil {
 		return err
 	} else if session == nil {
@@ -113,12 +121,12 @@ func (s *LastFmService) UpdateNowPlaying(artist string, title string, album stri
 	return s.client.UpdateNowPlaying(session.SessionKey, lastfm.UpdateNowPlayingRq{Artist: artist, Track: title, Album: album})
 }
 
-func (s *LastFmService) Scrobble(timestamp time.Time, artist string, title string, album string) error {
+func (s *LastFmService) Scrobble(owner string, timestamp time.Time, artist string, title string, album string) error {
 	if s.client == nil {
 		return ErrLastFmNotConfigured
 	}
 
-	session, err := s.sessions.Find()
+	session, err := s.sessions.Find(owner)
 	if err != nil {
 		return err
 	} else if session == nil {
@@ -139,3 +147,12 @@ func (s *LastFmService) Scrobble(timestamp time.Time, artist string, title strin
 		},
 	)
 }
+
+func toLastFmSession(session storage.LastFmSession) LastFmSession {
+	return LastFmSession{
+		Owner:      session.Owner,
+		Username:   session.Username,
+		SessionKey: session.SessionKey,
+		UpdatedAt:  session.UpdatedAt,
+	}
+}
@@ -0,0 +1,323 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"tapesonic/http/lastfm"
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/storage"
+	"tapesonic/util"
+)
+
+const (
+	lastFmSimilarArtistCount   = 10
+	lastFmTopTracksPerArtist   = 10
+	lastFmMaxImportsPerRequest = 10
+	lastFmFetchMultiplier      = 3
+)
+
+// resolves last.fm recommendations against the library of all subsonic services
+type LastFmDiscoveryService struct {
+	client *lastfm.LastFmClient
+
+	subsonic    map[string]*SubsonicNamedService
+	mux         SubsonicService
+	songCache   *SongCacheService
+	artistCache *storage.CachedMuxArtistStorage
+
+	ytdlp      *YtdlpService
+	importer   *AutoImportService
+	autoImport bool
+	importLock sync.Mutex
+}
+
+func NewLastFmDiscoveryService(
+	client *lastfm.LastFmClient,
+	subsonicServices []*SubsonicNamedService,
+	mux SubsonicService,
+	songCache *SongCacheService,
+	artistCache *storage.CachedMuxArtistStorage,
+	ytdlp *YtdlpService,
+	importer *AutoImportService,
+	autoImport bool,
+) *LastFmDiscoveryService {
+	subsonic := make(map[string]*SubsonicNamedService)
+	for _, svc := range subsonicServices {
+		subsonic[svc.Name()] = svc
+	}
+
+	return &LastFmDiscoveryService{
+		client:      client,
+		subsonic:    subsonic,
+		mux:         mux,
+		songCache:   songCache,
+		artistCache: artistCache,
+		ytdlp:       ytdlp,
+		importer:    importer,
+		autoImport:  autoImport,
+	}
+}
+
+// songs of the artist itself and of similar artists, shuffled
+func (s *LastFmDiscoveryService) GetSimilarSongsByArtist(username string, artistId string, count int) ([]responses.SubsonicChild, error) {
+	if s.client == nil {
+		return []responses.SubsonicChild{}, nil
+	}
+
+	artist, err := s.mux.GetArtist(username, artistId)
+	if err != nil {
+		return nil, err
+	}
+
+	similarArtists, err := s.client.ArtistGetSimilar(artist.Name, lastFmSimilarArtistCount)
+	if err != nil {
+		return nil, err
+	}
+
+	artistNames := []string{artist.Name}
+	for _, similarArtist := range similarArtists.SimilarArtists.Artist {
+		artistNames = append(artistNames, similarArtist.Name)
+	}
+
+	topTracks, err := util.ParallelMap(artistNames, func(name string) ([]lastfm.TrackInfo, error) {
+		tracks, err := s.client.ArtistGetTopTracks(name, lastFmTopTracksPerArtist)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to get top tracks of %s from last.fm, skipping: %s", name, err))
+			return []lastfm.TrackInfo{}, nil
+		}
+		return tracks.TopTracks.Track, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := []lastfm.TrackInfo{}
+	for _, artistTracks := range topTracks {
+		tracks = append(tracks, artistTracks...)
+	}
+
+	songs, err := s.resolveTracks(username, tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	rand.Shuffle(len(songs), func(i int, j int) { songs[i], songs[j] = songs[j], songs[i] })
+
+	return songs[:min(count, len(songs))], nil
+}
+
+// the id can point to a song or an artist
+func (s *LastFmDiscoveryService) GetSimilarSongs(username string, id string, count int) ([]responses.SubsonicChild, error) {
+	if s.client == nil {
+		return []responses.SubsonicChild{}, nil
+	}
+
+	song, err := s.mux.GetSong(username, id)
+	if err != nil {
+		return s.GetSimilarSongsByArtist(username, id, count)
+	}
+
+	similarTracks, err := s.client.TrackGetSimilar(song.Artist, song.Title, count*lastFmFetchMultiplier)
+	if err != nil {
+		return nil, err
+	}
+
+	songs, err := s.resolveTracks(username, similarTracks.SimilarTracks.Track)
+	if err != nil {
+		return nil, err
+	}
+
+	return songs[:min(count, len(songs))], nil
+}
+
+func (s *LastFmDiscoveryService) GetTopSongs(username string, artistName string, count int) ([]responses.SubsonicChild, error) {
+	if s.client == nil {
+		return []responses.SubsonicChild{}, nil
+	}
+
+	// a lot of the recommended tracks are usually missing from the library, so more of them are fetched
+	topTracks, err := s.client.ArtistGetTopTracks(artistName, count*lastFmFetchMultiplier)
+	if err != nil {
+		return nil, err
+	}
+
+	songs, err := s.resolveTracks(username, topTracks.TopTracks.Track)
+	if err != nil {
+		return nil, err
+	}
+
+	return songs[:min(count, len(songs))], nil
+}
+
+func (s *LastFmDiscoveryService) GetArtistInfo(username string, artistId string, count int, includeNotPresent bool) (*responses.ArtistInfo2, error) {
+	if s.client == nil {
+		return responses.NewArtistInfo2([]responses.ArtistId3{}), nil
+	}
+
+	artist, err := s.mux.GetArtist(username, artistId)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.client.ArtistGetInfo(artist.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	similarArtists, err := s.client.ArtistGetSimilar(artist.Name, count)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedArtists, err := util.ParallelMap(similarArtists.SimilarArtists.Artist, func(similarArtist lastfm.ArtistInfo) (*responses.ArtistId3, error) {
+		return s.resolveArtist(username, similarArtist.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	similarArtistsResponse := []responses.ArtistId3{}
+	for i, resolvedArtist := range resolvedArtists {
+		if resolvedArtist != nil {
+			similarArtistsResponse = append(similarArtistsResponse, *resolvedArtist)
+		} else if includeNotPresent {
+			similarArtistsResponse = append(similarArtistsResponse, *responses.NewArtistId3("", similarArtists.SimilarArtists.Artist[i].Name))
+		}
+	}
+
+	response := responses.NewArtistInfo2(similarArtistsResponse)
+	response.Biography = info.Artist.Bio.Summary
+	response.MusicBrainzId = info.Artist.Mbid
+	response.LastFmUrl = info.Artist.Url
+	for _, image := range info.Artist.Image {
+		switch image.Size {
+		case "small":
+			response.SmallImageUrl = image.Url
+		case "medium":
+			response.MediumImageUrl = image.Url
+		case "large":
+			response.LargeImageUrl = image.Url
+		}
+	}
+
+	return response, nil
+}
+
+func (s *LastFmDiscoveryService) resolveArtist(username string, name string) (*responses.ArtistId3, error) {
+	cachedArtists, err := s.artistCache.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(cachedArtists) == 0 {
+		return nil, nil
+	}
+
+	subsonic, ok := s.subsonic[cachedArtists[0].ServiceName]
+	if !ok {
+		return nil, fmt.Errorf("unknown service: %s", cachedArtists[0].ServiceName)
+	}
+
+	artist, err := subsonic.GetArtistByRawId(username, cachedArtists[0].ArtistId)
+	if err != nil {
+		return nil, err
+	}
+
+	artistId3 := responses.NewArtistId3(artist.Id, artist.Name)
+	artistId3.CoverArt = artist.CoverArt
+	artistId3.AlbumCount = len(artist.Album)
+	artistId3.ArtistImageUrl = artist.ArtistImageUrl
+	artistId3.Starred = artist.Starred
+
+	return artistId3, nil
+}
+
+// keeps the order of tracks, skipping the ones missing from the library
+func (s *LastFmDiscoveryService) resolveTracks(username string, tracks []lastfm.TrackInfo) ([]responses.SubsonicChild, error) {
+	resolvedSongs, err := util.ParallelMap(tracks, func(track lastfm.TrackInfo) (*responses.SubsonicChild, error) {
+		cachedSong, err := s.songCache.FindCachedSongByFields(track.Artist.Name, track.Name, "")
+		if err != nil || cachedSong == nil {
+			return nil, err
+		}
+
+		subsonic, ok := s.subsonic[cachedSong.ServiceName]
+		if !ok {
+			return nil, fmt.Errorf("unknown service: %s", cachedSong.ServiceName)
+		}
+
+		song, err := subsonic.GetSongByRawId(username, cachedSong.SongId)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to get cached song service=%s, id=%s, skipping: %s", cachedSong.ServiceName, cachedSong.SongId, err))
+			return nil, nil
+		}
+
+		return song, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	songIds := map[string]bool{}
+	songs := []responses.SubsonicChild{}
+	missingTracks := []lastfm.TrackInfo{}
+	for i, song := range resolvedSongs {
+		if song == nil {
+			missingTracks = append(missingTracks, tracks[i])
+			continue
+		}
+
+		if songIds[song.Id] {
+			continue
+		}
+		songIds[song.Id] = true
+
+		songs = append(songs, *song)
+	}
+
+	if s.autoImport && len(missingTracks) > 0 {
+		go s.importMissingTracks(missingTracks)
+	}
+
+	return songs, nil
+}
+
+// imported tracks show up the next time the same recommendations are requested
+func (s *LastFmDiscoveryService) importMissingTracks(tracks []lastfm.TrackInfo) {
+	if !s.importLock.TryLock() {
+		slog.Debug("Another import of tracks recommended by last.fm is already running, skipping")
+		return
+	}
+	defer s.importLock.Unlock()
+
+	tracks = tracks[:min(lastFmMaxImportsPerRequest, len(tracks))]
+	for _, track := range tracks {
+		targetTrackText := fmt.Sprintf("artist=%s, title=%s", track.Artist.Name, track.Name)
+
+		// last.fm doesn't provide any playable links in its api, so the track has to be searched for
+		searchResult, err := s.ytdlp.GetMetadata(context.Background(), fmt.Sprintf("ytsearch1:%s - %s", track.Artist.Name, track.Name))
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to search for track [%s], skipping: %s", targetTrackText, err))
+			continue
+		}
+		if len(searchResult.Entries) == 0 {
+			slog.Debug(fmt.Sprintf("Didn't find anything for track [%s], skipping", targetTrackText))
+			continue
+		}
+
+		url := util.Coalesce(searchResult.Entries[0].WebpageUrl, searchResult.Entries[0].Url)
+		importedTrack, err := s.importer.ImportTrackFrom(context.Background(), url, track.Artist.Name, track.Name)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to import track [%s] from %s, skipping: %s", targetTrackText, url, err))
+			continue
+		}
+
+		if _, err := s.songCache.Refresh("tapesonic", importedTrack.Id.String()); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to update song cache for track id=%s: %s", importedTrack.Id, err))
+			continue
+		}
+
+		slog.Info(fmt.Sprintf("Imported track [%s] recommended by last.fm from %s", targetTrackText, url))
+	}
+}
@@ -0,0 +1,222 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"tapesonic/storage"
+	"tapesonic/ytdlp"
+
+	"github.com/google/uuid"
+)
+
+// lines which consist only of sound annotations like [Music] or ♪
+var lyricsAnnotationRegex = regexp.MustCompile(`^(\[[^\]]*\]|♪|\s)*$`)
+
+type LyricsService struct {
+	storage *storage.LyricsStorage
+	tracks  *storage.TrackStorage
+	ytdlp   *YtdlpService
+}
+
+func NewLyricsService(
+	storage *storage.LyricsStorage,
+	tracks *storage.TrackStorage,
+	ytdlp *YtdlpService,
+) *LyricsService {
+	return &LyricsService{
+		storage: storage,
+		tracks:  tracks,
+		ytdlp:   ytdlp,
+	}
+}
+
+// returns nil if the source of the track doesn't have any subtitles
+func (s *LyricsService) GetForTrack(ctx context.Context, trackId uuid.UUID) (*storage.TrackLyrics, error) {
+	tracks, err := s.tracks.GetTracksWithSourcesByIds([]uuid.UUID{trackId})
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("track with id %s doesn't exist", trackId)
+	}
+	track := tracks[0]
+
+	lyrics, err := s.storage.Find(track.Id)
+	if err != nil {
+		return nil, err
+	}
+	if lyrics != nil && lyrics.StartOffsetMs == track.StartOffsetMs && lyrics.EndOffsetMs == track.EndOffsetMs {
+		return lyrics, nil
+	}
+
+	language, lines, err := s.fetchLines(ctx, track.Source.Url)
+	if err != nil {
+		return nil, err
+	}
+	if lines == nil {
+		slog.Debug(fmt.Sprintf("No subtitles found for source id=%s (%s)", track.Source.Id, track.Source.Url))
+		return nil, nil
+	}
+
+	// all tracks of the source share the same subtitles, so there's no point in fetching them again for each one
+	sourceTracks, err := s.tracks.GetDirectTracksBySource(track.SourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	allLyrics := []storage.TrackLyrics{}
+	for _, sourceTrack := range sourceTracks {
+		allLyrics = append(allLyrics, sliceLyrics(sourceTrack, language, lines))
+	}
+
+	if err := s.storage.Upsert(allLyrics); err != nil {
+		return nil, err
+	}
+
+	result := sliceLyrics(track, language, lines)
+	return &result, nil
+}
+
+func (s *LyricsService) fetchLines(ctx context.Context, url string) (string, []storage.LyricsLine, error) {
+	metadata, err := s.ytdlp.GetMetadata(ctx, url)
+	if err != nil {
+		return "", nil, err
+	}
+
+	language, subtitle, ok := selectSubtitle(metadata)
+	if !ok {
+		return "", nil, nil
+	}
+
+	content, err := downloadSubtitle(ctx, subtitle.Url)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to download %s subtitles for %s: %w", language, url, err)
+	}
+
+	cues, err := ytdlp.ParseVtt(content)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s subtitles for %s: %w", language, url, err)
+	}
+
+	// auto-generated captions repeat the previous line in every cue to make it look like it's scrolling
+	lines := []storage.LyricsLine{}
+	previousCueLines := []string{}
+	for _, cue := range cues {
+		for _, line := range cue.Lines {
+			if slices.Contains(previousCueLines, line) || lyricsAnnotationRegex.MatchString(line) {
+				continue
+			}
+
+			lines = append(lines, storage.LyricsLine{
+				StartMs: cue.StartMs,
+				Text:    strings.TrimSpace(strings.Trim(line, "♪")),
+			})
+		}
+		previousCueLines = cue.Lines
+	}
+
+	return language, lines, nil
+}
+
+// manual subtitles are preferred, auto-generated ones are only used in the original language
+// because all other languages are machine translations
+func selectSubtitle(metadata ytdlp.YtdlpFile) (string, ytdlp.YtdlpSubtitle, bool) {
+	languages := []string{}
+	for language := range metadata.Subtitles {
+		if language != "live_chat" {
+			languages = append(languages, language)
+		}
+	}
+	slices.Sort(languages)
+
+	if metadata.Language != "" {
+		slices.SortStableFunc(languages, func(a string, b string) int {
+			aMatches := isSameLanguage(a, metadata.Language)
+			bMatches := isSameLanguage(b, metadata.Language)
+			if aMatches == bMatches {
+				return 0
+			} else if aMatches {
+				return -1
+			} else {
+				return 1
+			}
+		})
+	}
+
+	for _, language := range languages {
+		if subtitle, ok := findVttSubtitle(metadata.Subtitles[language]); ok {
+			return language, subtitle, true
+		}
+	}
+
+	if metadata.Language != "" {
+		for _, language := range []string{metadata.Language + "-orig", metadata.Language} {
+			if subtitle, ok := findVttSubtitle(metadata.AutomaticCaptions[language]); ok {
+				return metadata.Language, subtitle, true
+			}
+		}
+	}
+
+	return "", ytdlp.YtdlpSubtitle{}, false
+}
+
+func findVttSubtitle(subtitles []ytdlp.YtdlpSubtitle) (ytdlp.YtdlpSubtitle, bool) {
+	for _, subtitle := range subtitles {
+		if subtitle.Ext == "vtt" {
+			return subtitle, true
+		}
+	}
+	return ytdlp.YtdlpSubtitle{}, false
+}
+
+func isSameLanguage(a string, b string) bool {
+	a, _, _ = strings.Cut(a, "-")
+	b, _, _ = strings.Cut(b, "-")
+	return strings.EqualFold(a, b)
+}
+
+func downloadSubtitle(ctx context.Context, url string) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	content, err := io.ReadAll(response.Body)
+	return string(content), err
+}
+
+func sliceLyrics(track storage.Track, language string, lines []storage.LyricsLine) storage.TrackLyrics {
+	trackLines := []storage.LyricsLine{}
+	for _, line := range lines {
+		if line.StartMs >= track.StartOffsetMs && line.StartMs < track.EndOffsetMs {
+			trackLines = append(trackLines, storage.LyricsLine{
+				StartMs: line.StartMs - track.StartOffsetMs,
+				Text:    line.Text,
+			})
+		}
+	}
+
+	return storage.TrackLyrics{
+		TrackId:       track.Id,
+		StartOffsetMs: track.StartOffsetMs,
+		EndOffsetMs:   track.EndOffsetMs,
+		Language:      language,
+		Lines:         trackLines,
+	}
+}
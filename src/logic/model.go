@@ -9,6 +9,19 @@ import (
 type AudioStream struct {
 	Reader   io.ReadCloser
 	MimeType string
+	BitRate  int // kbps, 0 if unknown
+	FileName string
+}
+
+type StreamOptions struct {
+	Format                string // empty means the original format is preferred
+	MaxBitRate            int    // kbps, 0 means no limit
+	TimeOffsetMs          int64
+	EstimateContentLength bool
+}
+
+func (o StreamOptions) IsTranscoded() bool {
+	return o.Format != "" || o.MaxBitRate > 0 || o.TimeOffsetMs > 0
 }
 
 type TrackProperties struct {
@@ -0,0 +1,130 @@
+package logic
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/util"
+	"time"
+)
+
+// clients don't report when playback stops, so entries are dropped once the song should've ended
+const nowPlayingGracePeriod = 5 * time.Minute
+
+type NowPlayingService struct {
+	mux SubsonicService
+
+	lock         sync.Mutex
+	entries      map[nowPlayingKey]NowPlayingEntry
+	lastPlayerId int
+}
+
+type nowPlayingKey struct {
+	username   string
+	clientName string
+}
+
+type NowPlayingEntry struct {
+	Username   string
+	ClientName string
+	PlayerId   int
+	SongId     string
+	StartedAt  time.Time
+}
+
+func (e NowPlayingEntry) MinutesAgo() int {
+	return int(time.Since(e.StartedAt).Minutes())
+}
+
+type NowPlayingSong struct {
+	NowPlayingEntry
+	Song responses.SubsonicChild
+}
+
+func NewNowPlayingService(mux SubsonicService) *NowPlayingService {
+	return &NowPlayingService{
+		mux:     mux,
+		entries: map[nowPlayingKey]NowPlayingEntry{},
+	}
+}
+
+func (s *NowPlayingService) SetNowPlaying(username string, clientName string, songId string, startedAt time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := nowPlayingKey{username: username, clientName: clientName}
+
+	entry, ok := s.entries[key]
+	if !ok {
+		s.lastPlayerId += 1
+		entry.PlayerId = s.lastPlayerId
+	}
+
+	entry.Username = username
+	entry.ClientName = clientName
+	entry.SongId = songId
+	entry.StartedAt = startedAt
+
+	s.entries[key] = entry
+}
+
+func (s *NowPlayingService) GetNowPlaying() ([]NowPlayingSong, error) {
+	entries := s.getEntries()
+
+	songs, err := util.ParallelMap(entries, func(entry NowPlayingEntry) (*NowPlayingSong, error) {
+		song, err := s.mux.GetSong(entry.Username, entry.SongId)
+		if err != nil {
+			// the song was most likely deleted, its duration is unknown so the entry would never expire otherwise
+			slog.Warn(fmt.Sprintf("Failed to get now playing song id=%s of %s (%s), dropping: %s", entry.SongId, entry.Username, entry.ClientName, err))
+			s.removeIfUnchanged(entry)
+			return nil, nil
+		}
+
+		if time.Since(entry.StartedAt) > time.Duration(song.Duration)*time.Second+nowPlayingGracePeriod {
+			s.removeIfUnchanged(entry)
+			return nil, nil
+		}
+
+		return &NowPlayingSong{NowPlayingEntry: entry, Song: *song}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []NowPlayingSong{}
+	for _, song := range songs {
+		if song != nil {
+			result = append(result, *song)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *NowPlayingService) getEntries() []NowPlayingEntry {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entries := []NowPlayingEntry{}
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].StartedAt.After(entries[j].StartedAt)
+	})
+
+	return entries
+}
+
+func (s *NowPlayingService) removeIfUnchanged(entry NowPlayingEntry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := nowPlayingKey{username: entry.Username, clientName: entry.ClientName}
+	if current, ok := s.entries[key]; ok && current.SongId == entry.SongId && current.StartedAt.Equal(entry.StartedAt) {
+		delete(s.entries, key)
+	}
+}
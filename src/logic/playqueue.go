@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"fmt"
+	"log/slog"
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/storage"
+	"tapesonic/util"
+)
+
+type PlayQueueService struct {
+	storage *storage.PlayQueueStorage
+	mux     SubsonicService
+}
+
+func NewPlayQueueService(
+	storage *storage.PlayQueueStorage,
+	mux SubsonicService,
+) *PlayQueueService {
+	return &PlayQueueService{
+		storage: storage,
+		mux:     mux,
+	}
+}
+
+func (s *PlayQueueService) Save(username string, songIds []string, currentId string, positionMs int64, changedBy string) error {
+	// saving an empty queue is how clients clear it
+	if len(songIds) == 0 {
+		return s.storage.Delete(username)
+	}
+
+	return s.storage.Upsert(storage.PlayQueue{
+		Username:   username,
+		SongIds:    songIds,
+		CurrentId:  currentId,
+		PositionMs: positionMs,
+		ChangedBy:  changedBy,
+	})
+}
+
+func (s *PlayQueueService) Get(username string) (*responses.PlayQueue, error) {
+	queue, err := s.storage.Find(username)
+	if err != nil {
+		return nil, err
+	}
+	if queue == nil {
+		return nil, nil
+	}
+
+	songs, err := util.ParallelMap(queue.SongIds, func(id string) (*responses.SubsonicChild, error) {
+		song, err := s.mux.GetSong(username, id)
+		if err != nil {
+			// songs get deleted and proxied servers go down, that shouldn't break the whole queue
+			slog.Warn(fmt.Sprintf("Failed to get song id=%s for the play queue of %s, skipping: %s", id, username, err))
+			return nil, nil
+		}
+		return song, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []responses.SubsonicChild{}
+	for _, song := range songs {
+		if song != nil {
+			entries = append(entries, *song)
+		}
+	}
+
+	response := responses.NewPlayQueue(queue.Username, queue.UpdatedAt, queue.ChangedBy, entries)
+	response.Current = queue.CurrentId
+	response.Position = queue.PositionMs
+
+	return response, nil
+}
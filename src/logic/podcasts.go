@@ -0,0 +1,322 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"tapesonic/model"
+	"tapesonic/storage"
+	"tapesonic/util"
+	"tapesonic/ytdlp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PodcastService struct {
+	storage *storage.PodcastStorage
+	sources *storage.SourceStorage
+	tracks  *storage.TrackStorage
+
+	ytdlp      *YtdlpService
+	files      *SourceFileService
+	thumbnails *ThumbnailService
+
+	maxEpisodes int
+}
+
+func NewPodcastService(
+	storage *storage.PodcastStorage,
+	sources *storage.SourceStorage,
+	tracks *storage.TrackStorage,
+	ytdlp *YtdlpService,
+	files *SourceFileService,
+	thumbnails *ThumbnailService,
+	maxEpisodes int,
+) *PodcastService {
+	return &PodcastService{
+		storage:     storage,
+		sources:     sources,
+		tracks:      tracks,
+		ytdlp:       ytdlp,
+		files:       files,
+		thumbnails:  thumbnails,
+		maxEpisodes: maxEpisodes,
+	}
+}
+
+func (s *PodcastService) CreateChannel(ctx context.Context, url string) (storage.PodcastChannel, error) {
+	metadata, err := s.ytdlp.GetMetadata(ctx, url)
+	if err != nil {
+		return storage.PodcastChannel{}, err
+	}
+
+	channelUrl := util.Coalesce(metadata.WebpageUrl, url)
+	existingChannel, err := s.storage.FindChannelByUrl(channelUrl)
+	if err != nil {
+		return storage.PodcastChannel{}, err
+	}
+	if existingChannel != nil {
+		return *existingChannel, nil
+	}
+
+	channel := storage.PodcastChannel{
+		Url:    channelUrl,
+		Status: storage.PODCAST_STATUS_NEW,
+	}
+	s.updateChannelMetadata(&channel, metadata)
+
+	return s.storage.CreateChannel(channel)
+}
+
+func (s *PodcastService) GetChannel(id uuid.UUID) (storage.PodcastChannel, error) {
+	return s.storage.GetChannel(id)
+}
+
+func (s *PodcastService) GetChannels() ([]storage.PodcastChannel, error) {
+	return s.storage.GetChannels()
+}
+
+func (s *PodcastService) GetSubsonicEpisodesByChannel(channelId uuid.UUID) ([]storage.SubsonicPodcastEpisodeItem, error) {
+	return s.storage.GetSubsonicEpisodesByChannel(channelId)
+}
+
+func (s *PodcastService) GetSubsonicNewestEpisodes(count int) ([]storage.SubsonicPodcastEpisodeItem, error) {
+	return s.storage.GetSubsonicNewestEpisodes(count)
+}
+
+func (s *PodcastService) DeleteChannel(id uuid.UUID) error {
+	episodes, err := s.storage.GetEpisodesByChannel(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storage.DeleteChannel(id); err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, episode := range episodes {
+		// the same video might've been added to the library as well, keep the media for it
+		tracks, err := s.tracks.GetDirectTracksBySource(episode.SourceId)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(tracks) > 0 {
+			continue
+		}
+
+		if err := s.files.DeleteFor(episode.SourceId); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := s.thumbnails.DeleteUnused(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *PodcastService) RefreshAll(ctx context.Context) error {
+	channels, err := s.storage.GetChannels()
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, channel := range channels {
+		if err := s.RefreshChannel(ctx, channel.Id); err != nil {
+			errs = append(errs, fmt.Errorf("failed to refresh podcast channel id=%s (%s): %w", channel.Id, channel.Url, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *PodcastService) RefreshChannel(ctx context.Context, id uuid.UUID) error {
+	channel, err := s.storage.GetChannel(id)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Refreshing podcast channel id=%s (%s)", channel.Id, channel.Url))
+
+	channel.Status = storage.PODCAST_STATUS_DOWNLOADING
+	if err := s.storage.UpdateChannel(channel); err != nil {
+		return err
+	}
+
+	refreshErr := s.refreshEpisodes(ctx, &channel)
+
+	refreshedAt := time.Now()
+	channel.RefreshedAt = &refreshedAt
+	if refreshErr != nil {
+		channel.Status = storage.PODCAST_STATUS_ERROR
+		channel.ErrorMessage = refreshErr.Error()
+	} else {
+		channel.Status = storage.PODCAST_STATUS_COMPLETED
+		channel.ErrorMessage = ""
+	}
+
+	return errors.Join(refreshErr, s.storage.UpdateChannel(channel))
+}
+
+func (s *PodcastService) refreshEpisodes(ctx context.Context, channel *storage.PodcastChannel) error {
+	metadata, err := s.ytdlp.GetMetadata(ctx, channel.Url)
+	if err != nil {
+		return err
+	}
+
+	s.updateChannelMetadata(channel, metadata)
+
+	existingEpisodes, err := s.storage.GetEpisodesByChannel(channel.Id)
+	if err != nil {
+		return err
+	}
+
+	existingUrls := map[string]bool{}
+	for _, episode := range existingEpisodes {
+		existingUrls[episode.Source.Url] = true
+	}
+
+	// channels list their newest videos first, there's no point in going through the whole back catalogue every time
+	entries := metadata.Entries
+	if len(entries) > s.maxEpisodes {
+		entries = entries[:s.maxEpisodes]
+	}
+
+	newEpisodeCount := 0
+	for _, entry := range entries {
+		entryUrl := util.Coalesce(entry.WebpageUrl, entry.Url)
+		if existingUrls[entryUrl] {
+			continue
+		}
+
+		// flat playlist entries lack most of the metadata, including the publish date
+		entryMetadata, err := s.ytdlp.GetMetadata(ctx, entryUrl)
+		if err != nil {
+			return fmt.Errorf("failed to get metadata for %s: %w", entryUrl, err)
+		}
+
+		// upcoming streams, nested playlists and channel tabs can't be played
+		if entryMetadata.Duration == 0 {
+			slog.Debug(fmt.Sprintf("Skipping podcast entry %s without any media in channel id=%s", entryUrl, channel.Id))
+			continue
+		}
+
+		if existingUrls[entryMetadata.WebpageUrl] {
+			continue
+		}
+
+		source, err := s.getOrCreateSource(entryMetadata)
+		if err != nil {
+			return fmt.Errorf("failed to save source for %s: %w", entryUrl, err)
+		}
+
+		_, err = s.storage.CreateEpisode(storage.PodcastEpisode{
+			ChannelId:   channel.Id,
+			SourceId:    source.Id,
+			Title:       entryMetadata.Title,
+			Description: entryMetadata.Description,
+			DurationMs:  source.DurationMs,
+			PublishedAt: getPublishDate(entryMetadata),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save podcast episode for %s: %w", entryUrl, err)
+		}
+
+		existingUrls[source.Url] = true
+		newEpisodeCount += 1
+	}
+
+	if newEpisodeCount > 0 {
+		slog.Info(fmt.Sprintf("Found %d new episodes for podcast channel id=%s (%s)", newEpisodeCount, channel.Id, channel.Url))
+	}
+
+	return nil
+}
+
+func (s *PodcastService) updateChannelMetadata(channel *storage.PodcastChannel, metadata ytdlp.YtdlpFile) {
+	channel.Title = util.Coalesce(metadata.Title, metadata.Channel, metadata.Uploader, channel.Title)
+	channel.Description = metadata.Description
+
+	if metadata.Thumbnail != "" && metadata.Thumbnail != channel.ThumbnailUrl {
+		thumbnail, err := s.thumbnails.CreateFromUrl(metadata.Thumbnail)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to save thumbnail for podcast channel %s: %s", channel.Url, err))
+			return
+		}
+
+		channel.ThumbnailUrl = metadata.Thumbnail
+		channel.ThumbnailId = &thumbnail.Id
+	}
+}
+
+func (s *PodcastService) getOrCreateSource(metadata ytdlp.YtdlpFile) (storage.Source, error) {
+	existingSource, err := s.sources.FindByUrl(metadata.WebpageUrl)
+	if err != nil {
+		return storage.Source{}, err
+	}
+	if existingSource != nil {
+		return *existingSource, nil
+	}
+
+	source := storage.Source{
+		ExtractorKey: metadata.ExtractorKey,
+		ExtractedId:  metadata.Id,
+		Url:          metadata.WebpageUrl,
+
+		Title:      metadata.Title,
+		Uploader:   metadata.Uploader,
+		UploaderId: metadata.UploaderId,
+
+		DurationMs: int64(metadata.Duration * 1000),
+
+		UploadedAt:  getPublishDate(metadata),
+		ReleaseDate: parseDateOrNull(metadata.ReleaseDate),
+
+		Genres: normalizeGenres(metadata.Tags),
+
+		ManagementPolicy: model.SOURCE_MANAGEMENT_POLICY_AUTO,
+	}
+
+	if metadata.Thumbnail != "" {
+		thumbnail, err := s.thumbnails.CreateFromUrl(metadata.Thumbnail)
+		if err != nil {
+			return storage.Source{}, err
+		}
+		source.ThumbnailId = &thumbnail.Id
+	}
+
+	return s.sources.Upsert(source)
+}
+
+func getPublishDate(metadata ytdlp.YtdlpFile) time.Time {
+	if metadata.Timestamp > 0 {
+		return time.Unix(int64(metadata.Timestamp), 0)
+	}
+	if uploadDate := parseDateOrNull(metadata.UploadDate); uploadDate != nil {
+		return *uploadDate
+	}
+	return time.Now()
+}
+
+func (s *PodcastService) DownloadEpisode(id uuid.UUID) error {
+	episode, err := s.storage.GetEpisode(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storage.SetEpisodeDownloadStatus(episode.Id, storage.PODCAST_STATUS_DOWNLOADING, ""); err != nil {
+		return err
+	}
+
+	if _, err := s.files.DownloadIfMissingFor(episode.SourceId); err != nil {
+		return errors.Join(err, s.storage.SetEpisodeDownloadStatus(episode.Id, storage.PODCAST_STATUS_ERROR, err.Error()))
+	}
+
+	return s.storage.SetEpisodeDownloadStatus(episode.Id, storage.PODCAST_STATUS_COMPLETED, "")
+}
@@ -0,0 +1,350 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"tapesonic/ffmpeg"
+	"tapesonic/storage"
+	"tapesonic/util"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotLiveStream = errors.New("url doesn't point to a live stream")
+)
+
+const (
+	radioFormat         = "mp3"
+	radioChunkSize      = 16 * 1024
+	radioListenerBuffer = 64
+	radioMaxFailures    = 3
+	radioRetryDelay     = 5 * time.Second
+)
+
+// every station is broadcasted by a single ffmpeg process no matter how many listeners it has
+type RadioService struct {
+	storage *storage.RadioStationStorage
+	ytdlp   *YtdlpService
+	ffmpeg  *ffmpeg.Ffmpeg
+
+	idleTimeout time.Duration
+
+	lock       sync.Mutex
+	broadcasts map[uuid.UUID]*radioBroadcast
+}
+
+type radioBroadcast struct {
+	station storage.RadioStation
+	cancel  context.CancelFunc
+
+	listeners map[*radioListener]struct{}
+	idleTimer *time.Timer
+}
+
+type radioListener struct {
+	service   *RadioService
+	broadcast *radioBroadcast
+
+	chunks  chan []byte
+	pending []byte
+	closed  bool
+}
+
+func NewRadioService(
+	storage *storage.RadioStationStorage,
+	ytdlp *YtdlpService,
+	ffmpeg *ffmpeg.Ffmpeg,
+	idleTimeout time.Duration,
+) *RadioService {
+	return &RadioService{
+		storage:     storage,
+		ytdlp:       ytdlp,
+		ffmpeg:      ffmpeg,
+		idleTimeout: idleTimeout,
+		broadcasts:  map[uuid.UUID]*radioBroadcast{},
+	}
+}
+
+func (s *RadioService) GetStations() ([]storage.RadioStation, error) {
+	return s.storage.GetAll()
+}
+
+func (s *RadioService) CreateStation(ctx context.Context, url string, name string, homepageUrl string) (storage.RadioStation, error) {
+	station := storage.RadioStation{
+		Url:         url,
+		Name:        name,
+		HomepageUrl: homepageUrl,
+	}
+
+	if err := s.fillFromMetadata(ctx, &station); err != nil {
+		return storage.RadioStation{}, err
+	}
+
+	return s.storage.Create(station)
+}
+
+func (s *RadioService) UpdateStation(ctx context.Context, id uuid.UUID, url string, name string, homepageUrl string) error {
+	station, err := s.storage.GetById(id)
+	if err != nil {
+		return err
+	}
+
+	station.Url = url
+	station.Name = name
+	station.HomepageUrl = homepageUrl
+
+	if err := s.fillFromMetadata(ctx, &station); err != nil {
+		return err
+	}
+
+	if err := s.storage.Update(station); err != nil {
+		return err
+	}
+
+	// listeners will have to reconnect to pick up the new url
+	s.stopBroadcast(id)
+	return nil
+}
+
+func (s *RadioService) DeleteStation(id uuid.UUID) error {
+	if err := s.storage.DeleteById(id); err != nil {
+		return err
+	}
+
+	s.stopBroadcast(id)
+	return nil
+}
+
+func (s *RadioService) fillFromMetadata(ctx context.Context, station *storage.RadioStation) error {
+	metadata, err := s.ytdlp.GetMetadata(ctx, station.Url)
+	if err != nil {
+		return err
+	}
+
+	if !metadata.IsLive {
+		return fmt.Errorf("%w: %s", ErrNotLiveStream, station.Url)
+	}
+
+	station.Name = util.Coalesce(station.Name, metadata.Title)
+	station.HomepageUrl = util.Coalesce(station.HomepageUrl, metadata.WebpageUrl)
+	return nil
+}
+
+func (s *RadioService) Listen(ctx context.Context, id uuid.UUID) (AudioStream, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	broadcast, ok := s.broadcasts[id]
+	if !ok {
+		station, err := s.storage.GetById(id)
+		if err != nil {
+			return AudioStream{}, err
+		}
+
+		broadcast = s.startBroadcastLocked(station)
+	}
+
+	if broadcast.idleTimer != nil {
+		broadcast.idleTimer.Stop()
+		broadcast.idleTimer = nil
+	}
+
+	listener := &radioListener{
+		service:   s,
+		broadcast: broadcast,
+		chunks:    make(chan []byte, radioListenerBuffer),
+	}
+	broadcast.listeners[listener] = struct{}{}
+
+	slog.Debug(fmt.Sprintf("New listener for radio station id=%s, %d listeners total", id, len(broadcast.listeners)))
+
+	context.AfterFunc(ctx, func() { listener.Close() })
+
+	return AudioStream{
+		Reader:   listener,
+		MimeType: util.FormatToMediaType(radioFormat),
+	}, nil
+}
+
+func (s *RadioService) startBroadcastLocked(station storage.RadioStation) *radioBroadcast {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	broadcast := &radioBroadcast{
+		station:   station,
+		cancel:    cancel,
+		listeners: map[*radioListener]struct{}{},
+	}
+	s.broadcasts[station.Id] = broadcast
+
+	slog.Info(fmt.Sprintf("Starting broadcast for radio station id=%s (%s)", station.Id, station.Url))
+
+	go s.runBroadcast(ctx, broadcast)
+	return broadcast
+}
+
+func (s *RadioService) runBroadcast(ctx context.Context, broadcast *radioBroadcast) {
+	defer func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		// the station might've been restarted already by someone else
+		if s.broadcasts[broadcast.station.Id] == broadcast {
+			s.stopBroadcastLocked(broadcast)
+		}
+	}()
+
+	failures := 0
+	for ctx.Err() == nil {
+		produced, err := s.broadcastOnce(ctx, broadcast)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if produced {
+			failures = 0
+		}
+		failures += 1
+
+		if failures >= radioMaxFailures {
+			slog.Warn(fmt.Sprintf("Stopping broadcast for radio station id=%s after %d failed attempts: %s", broadcast.station.Id, failures, err))
+			return
+		}
+
+		slog.Debug(fmt.Sprintf("Restarting broadcast for radio station id=%s: %s", broadcast.station.Id, err))
+
+		// stream urls for live streams expire after a while, so the url has to be re-resolved
+		if err := s.ytdlp.InvalidateMetadata(broadcast.station.Url); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to invalidate metadata for radio station id=%s: %s", broadcast.station.Id, err))
+		}
+
+		if !produced {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(radioRetryDelay):
+			}
+		}
+	}
+}
+
+func (s *RadioService) broadcastOnce(ctx context.Context, broadcast *radioBroadcast) (produced bool, err error) {
+	streamInfo, err := s.ytdlp.GetStreamInfo(ctx, broadcast.station.Url, "ba/b")
+	if err != nil {
+		return false, err
+	}
+
+	reader, err := s.ffmpeg.StreamLive(ctx, radioFormat, streamInfo.Url)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+
+	for {
+		chunk := make([]byte, radioChunkSize)
+		n, err := reader.Read(chunk)
+		if n > 0 {
+			produced = true
+			s.publish(broadcast, chunk[:n])
+		}
+
+		if errors.Is(err, io.EOF) {
+			return produced, fmt.Errorf("stream ended")
+		} else if err != nil {
+			return produced, err
+		}
+	}
+}
+
+func (s *RadioService) publish(broadcast *radioBroadcast, chunk []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for listener := range broadcast.listeners {
+		select {
+		case listener.chunks <- chunk:
+		default:
+			// slow listeners are dropped instead of stalling everyone else
+			slog.Debug(fmt.Sprintf("Dropping a listener of radio station id=%s which can't keep up", broadcast.station.Id))
+			s.removeListenerLocked(listener)
+		}
+	}
+}
+
+func (s *RadioService) stopBroadcast(id uuid.UUID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	broadcast, ok := s.broadcasts[id]
+	if !ok {
+		return
+	}
+
+	s.stopBroadcastLocked(broadcast)
+}
+
+func (s *RadioService) stopBroadcastLocked(broadcast *radioBroadcast) {
+	slog.Info(fmt.Sprintf("Stopping broadcast for radio station id=%s (%s)", broadcast.station.Id, broadcast.station.Url))
+
+	delete(s.broadcasts, broadcast.station.Id)
+	broadcast.cancel()
+
+	if broadcast.idleTimer != nil {
+		broadcast.idleTimer.Stop()
+		broadcast.idleTimer = nil
+	}
+
+	for listener := range broadcast.listeners {
+		s.removeListenerLocked(listener)
+	}
+}
+
+func (s *RadioService) removeListenerLocked(listener *radioListener) {
+	if listener.closed {
+		return
+	}
+
+	listener.closed = true
+	close(listener.chunks)
+
+	broadcast := listener.broadcast
+	delete(broadcast.listeners, listener)
+
+	if len(broadcast.listeners) == 0 && s.broadcasts[broadcast.station.Id] == broadcast {
+		broadcast.idleTimer = time.AfterFunc(s.idleTimeout, func() {
+			s.lock.Lock()
+			defer s.lock.Unlock()
+
+			if len(broadcast.listeners) == 0 && s.broadcasts[broadcast.station.Id] == broadcast {
+				s.stopBroadcastLocked(broadcast)
+			}
+		})
+	}
+}
+
+func (l *radioListener) Read(p []byte) (int, error) {
+	if len(l.pending) == 0 {
+		chunk, ok := <-l.chunks
+		if !ok {
+			return 0, io.EOF
+		}
+		l.pending = chunk
+	}
+
+	n := copy(p, l.pending)
+	l.pending = l.pending[n:]
+	return n, nil
+}
+
+func (l *radioListener) Close() error {
+	l.service.lock.Lock()
+	defer l.service.lock.Unlock()
+
+	l.service.removeListenerLocked(l)
+	return nil
+}
@@ -10,20 +10,25 @@ import (
 
 type ScrobbleService struct {
 	listenbrainz *listenbrainz.ListenBrainzClient
-	lastfm       *LastFmService
+	// listenbrainz token is global, so only listens of this user are submitted there
+	listenbrainzUsername string
+	lastfm               *LastFmService
 }
 
 func NewScrobbleService(
 	listenbrainz *listenbrainz.ListenBrainzClient,
+	listenbrainzUsername string,
 	lastfm *LastFmService,
 ) *ScrobbleService {
 	return &ScrobbleService{
-		listenbrainz: listenbrainz,
-		lastfm:       lastfm,
+		listenbrainz:         listenbrainz,
+		listenbrainzUsername: listenbrainzUsername,
+		lastfm:               lastfm,
 	}
 }
 
 func (svc *ScrobbleService) ScrobblePlaying(
+	username string,
 	artist string,
 	album string,
 	track string,
@@ -33,13 +38,13 @@ func (svc *ScrobbleService) ScrobblePlaying(
 		return nil
 	}
 
-	lastFmErr := svc.lastfm.UpdateNowPlaying(artist, track, album)
+	lastFmErr := svc.lastfm.UpdateNowPlaying(username, artist, track, album)
 	if errors.Is(lastFmErr, ErrLastFmNotConfigured) {
 		lastFmErr = nil
 	}
 
 	var listenbrainzErr error = nil
-	if svc.listenbrainz != nil {
+	if svc.listenbrainz != nil && username == svc.listenbrainzUsername {
 		request := listenbrainz.SubmitListensRequest{
 			ListenType: listenbrainz.ListenTypePlayingNow,
 			Payload: []listenbrainz.SubmitListensRequestPayloadItem{
@@ -59,6 +64,7 @@ func (svc *ScrobbleService) ScrobblePlaying(
 }
 
 func (svc *ScrobbleService) ScrobbleCompleted(
+	username string,
 	listenedAt time.Time,
 	artist string,
 	album string,
@@ -69,13 +75,13 @@ func (svc *ScrobbleService) ScrobbleCompleted(
 		return nil
 	}
 
-	lastFmErr := svc.lastfm.Scrobble(listenedAt, artist, track, album)
+	lastFmErr := svc.lastfm.Scrobble(username, listenedAt, artist, track, album)
 	if errors.Is(lastFmErr, ErrLastFmNotConfigured) {
 		lastFmErr = nil
 	}
 
 	var listenbrainzErr error = nil
-	if svc.listenbrainz != nil {
+	if svc.listenbrainz != nil && username == svc.listenbrainzUsername {
 		request := listenbrainz.SubmitListensRequest{
 			ListenType: listenbrainz.ListenTypeSingle,
 			Payload: []listenbrainz.SubmitListensRequestPayloadItem{
@@ -0,0 +1,233 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/storage"
+	"tapesonic/util"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrShareNotFound        = errors.New("share not found")
+	ErrShareItemNotFound    = errors.New("shared item not found")
+	ErrShareItemUnsupported = errors.New("only songs, albums and playlists from Tapesonic's own library can be shared")
+)
+
+type Share struct {
+	Id          uuid.UUID
+	Username    string
+	Description string
+
+	ExpiresAt     *time.Time
+	LastVisitedAt *time.Time
+	VisitCount    int
+
+	CreatedAt time.Time
+
+	Entries []responses.SubsonicChild
+}
+
+// shares are public, so only the internal library is shared: proxied songs would be streamed with
+// the credentials of the proxied server and could disappear from it at any time
+type ShareService struct {
+	storage  *storage.ShareStorage
+	subsonic *SubsonicNamedService
+}
+
+func NewShareService(
+	storage *storage.ShareStorage,
+	subsonic *SubsonicNamedService,
+) *ShareService {
+	return &ShareService{
+		storage:  storage,
+		subsonic: subsonic,
+	}
+}
+
+func (s *ShareService) Create(username string, ids []string, description string, expiresAt *time.Time) (Share, error) {
+	items := []storage.ShareItem{}
+	for _, id := range ids {
+		item, err := s.resolveItem(username, id)
+		if err != nil {
+			return Share{}, err
+		}
+
+		items = append(items, item)
+	}
+
+	share, err := s.storage.Create(storage.Share{
+		Username:    username,
+		Description: description,
+		Items:       items,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return Share{}, err
+	}
+
+	return s.toShare(share)
+}
+
+func (s *ShareService) GetAll(username string) ([]Share, error) {
+	shares, err := s.storage.GetAllByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Share{}
+	for _, share := range shares {
+		resultShare, err := s.toShare(share)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, resultShare)
+	}
+	return result, nil
+}
+
+// Update can only remove the expiration time by passing nil, same as in the Subsonic API
+func (s *ShareService) Update(username string, id uuid.UUID, description string, expiresAt *time.Time) error {
+	share, err := s.findOwned(username, id)
+	if err != nil {
+		return err
+	}
+
+	share.Description = description
+	share.ExpiresAt = expiresAt
+
+	return s.storage.Update(share)
+}
+
+func (s *ShareService) Delete(username string, id uuid.UUID) error {
+	if _, err := s.findOwned(username, id); err != nil {
+		return err
+	}
+
+	return s.storage.DeleteById(id)
+}
+
+// Visit is used by the public share page, expired shares are treated as non-existent
+func (s *ShareService) Visit(id uuid.UUID) (Share, error) {
+	share, err := s.findActive(id)
+	if err != nil {
+		return Share{}, err
+	}
+
+	now := time.Now()
+	if err := s.storage.RecordVisit(id, now); err != nil {
+		return Share{}, err
+	}
+	share.VisitCount++
+	share.LastVisitedAt = &now
+
+	return s.toShare(share)
+}
+
+func (s *ShareService) Stream(ctx context.Context, id uuid.UUID, songId string) (AudioStream, error) {
+	share, err := s.findActive(id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	trackId, err := s.decodeId(songId)
+	if err != nil {
+		return AudioStream{}, ErrShareItemNotFound
+	}
+
+	trackIds, err := s.storage.GetTrackIds(share.Id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+	if !slices.Contains(trackIds, trackId) {
+		return AudioStream{}, ErrShareItemNotFound
+	}
+
+	return s.subsonic.StreamByRawId(ctx, encodeId(trackId.String()), StreamOptions{})
+}
+
+func (s *ShareService) resolveItem(username string, id string) (storage.ShareItem, error) {
+	if !s.subsonic.Matches(id) {
+		return storage.ShareItem{}, ErrShareItemUnsupported
+	}
+
+	itemId, err := s.decodeId(id)
+	if err != nil {
+		return storage.ShareItem{}, ErrShareItemNotFound
+	}
+
+	rawId := s.subsonic.RemovePrefix(id)
+	if _, err := s.subsonic.GetSongByRawId(username, rawId); err == nil {
+		return storage.ShareItem{TrackId: &itemId}, nil
+	}
+	if _, err := s.subsonic.GetAlbumByRawId(username, rawId); err == nil {
+		return storage.ShareItem{TapeId: &itemId}, nil
+	}
+	if _, err := s.subsonic.GetPlaylistByRawId(username, rawId); err == nil {
+		return storage.ShareItem{TapeId: &itemId}, nil
+	}
+
+	return storage.ShareItem{}, fmt.Errorf("%w: %s", ErrShareItemNotFound, id)
+}
+
+func (s *ShareService) findOwned(username string, id uuid.UUID) (storage.Share, error) {
+	share, err := s.storage.Find(id)
+	if err != nil {
+		return storage.Share{}, err
+	} else if share == nil || share.Username != username {
+		return storage.Share{}, ErrShareNotFound
+	}
+
+	return *share, nil
+}
+
+func (s *ShareService) findActive(id uuid.UUID) (storage.Share, error) {
+	share, err := s.storage.Find(id)
+	if err != nil {
+		return storage.Share{}, err
+	} else if share == nil || (share.ExpiresAt != nil && share.ExpiresAt.Before(time.Now())) {
+		return storage.Share{}, ErrShareNotFound
+	}
+
+	return *share, nil
+}
+
+func (s *ShareService) decodeId(id string) (uuid.UUID, error) {
+	return decodeId(s.subsonic.RemovePrefix(id))
+}
+
+// entries are resolved on every request, so shared tapes follow later changes to their tracks
+func (s *ShareService) toShare(share storage.Share) (Share, error) {
+	trackIds, err := s.storage.GetTrackIds(share.Id)
+	if err != nil {
+		return Share{}, err
+	}
+
+	entries, err := util.ParallelMap(trackIds, func(trackId uuid.UUID) (responses.SubsonicChild, error) {
+		song, err := s.subsonic.GetSongByRawId(share.Username, encodeId(trackId.String()))
+		if err != nil {
+			return responses.SubsonicChild{}, err
+		}
+		return *song, nil
+	})
+	if err != nil {
+		return Share{}, err
+	}
+
+	return Share{
+		Id:            share.Id,
+		Username:      share.Username,
+		Description:   share.Description,
+		ExpiresAt:     share.ExpiresAt,
+		LastVisitedAt: share.LastVisitedAt,
+		VisitCount:    share.VisitCount,
+		CreatedAt:     share.CreatedAt,
+		Entries:       entries,
+	}, nil
+}
@@ -64,7 +64,7 @@ func (s *SongCacheService) Refresh(serviceName string, id string) (storage.Cache
 		return storage.CachedMuxSong{}, fmt.Errorf("unknown service: %s", serviceName)
 	}
 
-	song, err := subsonic.GetSongByRawId(id)
+	song, err := subsonic.GetSongByRawId("", id)
 	if err != nil {
 		return storage.CachedMuxSong{}, err
 	}
@@ -3,6 +3,7 @@ package logic
 import (
 	"context"
 	"fmt"
+	"strings"
 	"tapesonic/model"
 	"tapesonic/storage"
 	"tapesonic/util"
@@ -103,6 +104,14 @@ func (s *SourceService) addSourceRecursive(ctx context.Context, url string, mana
 		source.ManagementPolicy = existingSource.ManagementPolicy
 	}
 
+	// genres edited by hand take precedence over whatever the extractor says
+	if existingSource != nil && existingSource.GenresEdited {
+		source.Genres = existingSource.Genres
+		source.GenresEdited = true
+	} else {
+		source.Genres = normalizeGenres(metadata.Tags)
+	}
+
 	source, err = s.storage.Upsert(source)
 	if err != nil {
 		return SourceAndMetadata{}, err
@@ -212,6 +221,22 @@ func parseDateOrNull(str string) *time.Time {
 	}
 }
 
+func normalizeGenres(genres []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, genre := range genres {
+		genre = strings.Join(strings.Fields(genre), " ")
+		key := strings.ToLower(genre)
+		if genre == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, genre)
+	}
+	return result
+}
+
 func extractTrackProperties(source storage.Source) TrackProperties {
 	return TrackProperties{
 		SourceId:      source.Id,
@@ -309,6 +334,10 @@ func (s *SourceService) GetById(id uuid.UUID) (storage.Source, error) {
 	return s.storage.GetById(id)
 }
 
+func (s *SourceService) SetGenres(id uuid.UUID, genres []string) error {
+	return s.storage.SetGenresById(id, normalizeGenres(genres))
+}
+
 func (s *SourceService) FindByUrl(url string) (*storage.Source, error) {
 	return s.storage.FindByUrl(url)
 }
@@ -2,6 +2,7 @@ package logic
 
 import (
 	"context"
+	"errors"
 	"io"
 	"tapesonic/http/subsonic/responses"
 	"time"
@@ -9,6 +10,7 @@ import (
 
 type SubsonicService interface {
 	Search3(
+		username string,
 		query string,
 		artistCount int,
 		artistOffset int,
@@ -18,43 +20,111 @@ type SubsonicService interface {
 		songOffset int,
 	) (*responses.SearchResult3, error)
 
-	GetSong(id string) (*responses.SubsonicChild, error)
+	GetSong(username string, id string) (*responses.SubsonicChild, error)
 
-	GetRandomSongs(size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error)
+	GetRandomSongs(username string, size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error)
 
-	GetAlbum(id string) (*responses.AlbumId3, error)
+	GetSongsByGenre(username string, genre string, count int, offset int) (*responses.SongsByGenre, error)
+
+	GetAlbum(username string, id string) (*responses.AlbumId3, error)
 
 	GetAlbumList2(
+		username string,
 		type_ string,
 		size int,
 		offset int,
 		fromYear *int,
 		toYear *int,
+		genre string,
 	) (*responses.AlbumList2, error)
 
-	GetPlaylist(id string) (*responses.SubsonicPlaylist, error)
+	GetPlaylist(username string, id string) (*responses.SubsonicPlaylist, error)
+
+	GetPlaylists(username string) (*responses.SubsonicPlaylists, error)
+
+	CreatePlaylist(username string, playlistId string, name string, songIds []string) (*responses.SubsonicPlaylist, error)
+
+	UpdatePlaylist(username string, playlistId string, name string, songIdsToAdd []string, songIndexesToRemove []int) error
+
+	DeletePlaylist(username string, playlistId string) error
+
+	GetGenres() (*responses.Genres, error)
+
+	GetArtists(username string) (*responses.Artists, error)
+
+	GetArtist(username string, id string) (*responses.Artist, error)
+
+	Scrobble(username string, id string, time_ time.Time, submission bool) error
+
+	Star(username string, ids []string, albumIds []string, artistIds []string) error
+
+	Unstar(username string, ids []string, albumIds []string, artistIds []string) error
+
+	GetStarred2(username string) (*responses.Starred2, error)
+
+	SetRating(username string, id string, rating int) error
+
+	CreateBookmark(username string, id string, positionMs int64, comment string) error
+
+	GetBookmarks(username string) (*responses.Bookmarks, error)
+
+	DeleteBookmark(username string, id string) error
 
-	GetPlaylists() (*responses.SubsonicPlaylists, error)
+	GetPodcasts(id string, includeEpisodes bool) (*responses.Podcasts, error)
 
-	GetArtist(id string) (*responses.Artist, error)
+	GetNewestPodcasts(count int) (*responses.NewestPodcasts, error)
 
-	Scrobble(id string, time_ time.Time, submission bool) error
+	CreatePodcastChannel(url string) error
 
-	GetCoverArt(id string) (mime string, reader io.ReadCloser, err error)
+	DeletePodcastChannel(id string) error
 
-	Stream(ctx context.Context, id string) (AudioStream, error)
+	RefreshPodcasts() error
+
+	DownloadPodcastEpisode(id string) error
+
+	GetLyrics(artist string, title string) (*responses.Lyrics, error)
+
+	GetLyricsBySongId(id string) (*responses.LyricsList, error)
+
+	GetMusicFolders() (*responses.MusicFolders, error)
+
+	GetIndexes(username string, musicFolderId string) (*responses.Indexes, error)
+
+	GetMusicDirectory(username string, id string) (*responses.Directory, error)
+
+	GetCoverArt(id string, size int) (mime string, reader io.ReadCloser, err error)
+
+	Stream(ctx context.Context, id string, options StreamOptions) (AudioStream, error)
+
+	Download(ctx context.Context, id string) (AudioStream, error)
+
+	// GetStreamSegments returns durations of the HLS segments of the song in milliseconds
+	GetStreamSegments(id string) ([]int64, error)
+
+	StreamSegment(ctx context.Context, id string, index int, maxBitRate int) (AudioStream, error)
 
 	GetLicense() (*responses.License, error)
 }
 
+var (
+	ErrReadOnlyPlaylist = errors.New("playlist is read-only")
+	ErrHlsUnsupported   = errors.New("HLS streaming is only supported for Tapesonic's own library")
+)
+
 const (
-	LIST_RANDOM = "random"
-	LIST_NEWEST = "newest"
-	// LIST_HIGHEST   = "highest" // todo
+	LIST_RANDOM    = "random"
+	LIST_NEWEST    = "newest"
+	LIST_HIGHEST   = "highest"
 	LIST_FREQUENT  = "frequent"
 	LIST_RECENT    = "recent"
 	LIST_BY_NAME   = "alphabeticalByName"
 	LIST_BY_ARTIST = "alphabeticalByArtist"
 	LIST_STARRED   = "starred"
 	LIST_BY_YEAR   = "byYear"
+	LIST_BY_GENRE  = "byGenre"
+)
+
+const (
+	HLS_SEGMENT_DURATION_MS = 10000
+	HLS_SEGMENT_MEDIA_TYPE  = "video/mp2t"
 )
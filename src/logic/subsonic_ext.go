@@ -19,6 +19,7 @@ func NewSubsonicExternalService(client *client.SubsonicClient) SubsonicService {
 }
 
 func (svc *subsonicExternalService) Search3(
+	username string,
 	query string,
 	artistCount int,
 	artistOffset int,
@@ -38,54 +39,173 @@ func (svc *subsonicExternalService) Search3(
 	)
 }
 
-func (svc *subsonicExternalService) GetSong(id string) (*responses.SubsonicChild, error) {
+func (svc *subsonicExternalService) GetSong(username string, id string) (*responses.SubsonicChild, error) {
 	return svc.client.GetSong(id)
 }
 
-func (svc *subsonicExternalService) GetRandomSongs(size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
+func (svc *subsonicExternalService) GetRandomSongs(username string, size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
 	return svc.client.GetRandomSongs(size, genre, fromYear, toYear)
 }
 
-func (svc *subsonicExternalService) GetAlbum(id string) (*responses.AlbumId3, error) {
+func (svc *subsonicExternalService) GetSongsByGenre(username string, genre string, count int, offset int) (*responses.SongsByGenre, error) {
+	return svc.client.GetSongsByGenre(genre, count, offset)
+}
+
+func (svc *subsonicExternalService) GetAlbum(username string, id string) (*responses.AlbumId3, error) {
 	return svc.client.GetAlbum(id)
 }
 
-func (svc *subsonicExternalService) GetAlbumList2(type_ string, size int, offset int, fromYear *int, toYear *int) (*responses.AlbumList2, error) {
-	return svc.client.GetAlbumList2(type_, size, offset, fromYear, toYear)
+func (svc *subsonicExternalService) GetAlbumList2(username string, type_ string, size int, offset int, fromYear *int, toYear *int, genre string) (*responses.AlbumList2, error) {
+	return svc.client.GetAlbumList2(type_, size, offset, fromYear, toYear, genre)
 }
 
-func (svc *subsonicExternalService) GetPlaylist(id string) (*responses.SubsonicPlaylist, error) {
+func (svc *subsonicExternalService) GetPlaylist(username string, id string) (*responses.SubsonicPlaylist, error) {
 	return svc.client.GetPlaylist(id)
 }
 
-func (svc *subsonicExternalService) GetPlaylists() (*responses.SubsonicPlaylists, error) {
+func (svc *subsonicExternalService) GetPlaylists(username string) (*responses.SubsonicPlaylists, error) {
 	return svc.client.GetPlaylists()
 }
 
-func (svc *subsonicExternalService) GetArtist(id string) (*responses.Artist, error) {
+func (svc *subsonicExternalService) CreatePlaylist(username string, playlistId string, name string, songIds []string) (*responses.SubsonicPlaylist, error) {
+	return svc.client.CreatePlaylist(playlistId, name, songIds)
+}
+
+func (svc *subsonicExternalService) UpdatePlaylist(username string, playlistId string, name string, songIdsToAdd []string, songIndexesToRemove []int) error {
+	return svc.client.UpdatePlaylist(playlistId, name, songIdsToAdd, songIndexesToRemove)
+}
+
+func (svc *subsonicExternalService) DeletePlaylist(username string, playlistId string) error {
+	return svc.client.DeletePlaylist(playlistId)
+}
+
+func (svc *subsonicExternalService) GetGenres() (*responses.Genres, error) {
+	return svc.client.GetGenres()
+}
+
+func (svc *subsonicExternalService) GetArtists(username string) (*responses.Artists, error) {
+	return svc.client.GetArtists()
+}
+
+func (svc *subsonicExternalService) GetArtist(username string, id string) (*responses.Artist, error) {
 	return svc.client.GetArtist(id)
 }
 
-func (svc *subsonicExternalService) Scrobble(id string, time_ time.Time, submission bool) error {
+func (svc *subsonicExternalService) Scrobble(username string, id string, time_ time.Time, submission bool) error {
 	return svc.client.Scrobble(id, time_, submission)
 }
 
-func (svc *subsonicExternalService) GetCoverArt(id string) (mime string, reader io.ReadCloser, err error) {
-	return svc.client.GetCoverArt(id)
+func (svc *subsonicExternalService) Star(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.client.Star(ids, albumIds, artistIds)
+}
+
+func (svc *subsonicExternalService) Unstar(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.client.Unstar(ids, albumIds, artistIds)
+}
+
+func (svc *subsonicExternalService) SetRating(username string, id string, rating int) error {
+	return svc.client.SetRating(id, rating)
+}
+
+func (svc *subsonicExternalService) CreateBookmark(username string, id string, positionMs int64, comment string) error {
+	return svc.client.CreateBookmark(id, positionMs, comment)
+}
+
+func (svc *subsonicExternalService) GetBookmarks(username string) (*responses.Bookmarks, error) {
+	return svc.client.GetBookmarks()
+}
+
+func (svc *subsonicExternalService) DeleteBookmark(username string, id string) error {
+	return svc.client.DeleteBookmark(id)
+}
+
+func (svc *subsonicExternalService) GetStarred2(username string) (*responses.Starred2, error) {
+	return svc.client.GetStarred2()
+}
+
+func (svc *subsonicExternalService) GetPodcasts(id string, includeEpisodes bool) (*responses.Podcasts, error) {
+	return svc.client.GetPodcasts(id, includeEpisodes)
+}
+
+func (svc *subsonicExternalService) GetNewestPodcasts(count int) (*responses.NewestPodcasts, error) {
+	return svc.client.GetNewestPodcasts(count)
+}
+
+func (svc *subsonicExternalService) CreatePodcastChannel(url string) error {
+	return svc.client.CreatePodcastChannel(url)
 }
 
-func (svc *subsonicExternalService) Stream(ctx context.Context, id string) (AudioStream, error) {
-	mime, reader, err := svc.client.Stream(id)
+func (svc *subsonicExternalService) DeletePodcastChannel(id string) error {
+	return svc.client.DeletePodcastChannel(id)
+}
+
+func (svc *subsonicExternalService) RefreshPodcasts() error {
+	return svc.client.RefreshPodcasts()
+}
+
+func (svc *subsonicExternalService) DownloadPodcastEpisode(id string) error {
+	return svc.client.DownloadPodcastEpisode(id)
+}
+
+func (svc *subsonicExternalService) GetLyrics(artist string, title string) (*responses.Lyrics, error) {
+	return svc.client.GetLyrics(artist, title)
+}
+
+func (svc *subsonicExternalService) GetLyricsBySongId(id string) (*responses.LyricsList, error) {
+	return svc.client.GetLyricsBySongId(id)
+}
+
+func (svc *subsonicExternalService) GetMusicFolders() (*responses.MusicFolders, error) {
+	return svc.client.GetMusicFolders()
+}
+
+func (svc *subsonicExternalService) GetIndexes(username string, musicFolderId string) (*responses.Indexes, error) {
+	return svc.client.GetIndexes(musicFolderId)
+}
+
+func (svc *subsonicExternalService) GetMusicDirectory(username string, id string) (*responses.Directory, error) {
+	return svc.client.GetMusicDirectory(id)
+}
+
+func (svc *subsonicExternalService) GetCoverArt(id string, size int) (mime string, reader io.ReadCloser, err error) {
+	return svc.client.GetCoverArt(id, size)
+}
+
+func (svc *subsonicExternalService) Stream(ctx context.Context, id string, options StreamOptions) (AudioStream, error) {
+	mime, reader, err := svc.client.Stream(id, options.Format, options.MaxBitRate, options.TimeOffsetMs/1000, options.EstimateContentLength)
 	if err != nil {
 		return AudioStream{}, err
 	}
 
 	return AudioStream{
-		Reader:        reader,
-		MimeType:      mime,
+		Reader:   reader,
+		MimeType: mime,
+		BitRate:  options.MaxBitRate,
 	}, nil
 }
 
+func (svc *subsonicExternalService) Download(ctx context.Context, id string) (AudioStream, error) {
+	mime, fileName, reader, err := svc.client.Download(id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	return AudioStream{
+		Reader:   reader,
+		MimeType: mime,
+		FileName: fileName,
+	}, nil
+}
+
+// proxied servers don't expose segments in any standard way, so their songs are only available via Stream
+func (svc *subsonicExternalService) GetStreamSegments(id string) ([]int64, error) {
+	return nil, ErrHlsUnsupported
+}
+
+func (svc *subsonicExternalService) StreamSegment(ctx context.Context, id string, index int, maxBitRate int) (AudioStream, error) {
+	return AudioStream{}, ErrHlsUnsupported
+}
+
 func (svc *subsonicExternalService) GetLicense() (*responses.License, error) {
 	return svc.client.GetLicense()
 }
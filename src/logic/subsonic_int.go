@@ -2,12 +2,15 @@ package logic
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math"
 	"os"
+	"path"
+	"regexp"
 	"slices"
 	"strings"
 	"tapesonic/ffmpeg"
@@ -21,9 +24,17 @@ import (
 
 type subsonicInternalService struct {
 	tracks      *storage.TrackStorage
+	sources     *storage.SourceStorage
 	albums      *storage.AlbumStorage
+	artists     *storage.ArtistStorage
+	genres      *storage.GenreStorage
 	playlists   *storage.PlaylistStorage
+	tapes       *storage.TapeStorage
 	listens     *storage.TrackListensStorage
+	starred     *storage.StarredItemStorage
+	ratings     *storage.RatingStorage
+	bookmarks   *storage.BookmarkStorage
+	users       *storage.UserStorage
 	media       *storage.MediaStorage
 	streamCache *storage.StreamCacheStorage
 
@@ -32,35 +43,58 @@ type subsonicInternalService struct {
 	thumbnails *ThumbnailService
 	scrobbler  *ScrobbleService
 	ytdlp      *YtdlpService
+	podcasts   *PodcastService
+	lyrics     *LyricsService
 }
 
 func NewSubsonicInternalService(
 	tracks *storage.TrackStorage,
+	sources *storage.SourceStorage,
 	albums *storage.AlbumStorage,
+	artists *storage.ArtistStorage,
+	genres *storage.GenreStorage,
 	playlists *storage.PlaylistStorage,
+	tapes *storage.TapeStorage,
 	listens *storage.TrackListensStorage,
+	starred *storage.StarredItemStorage,
+	ratings *storage.RatingStorage,
+	bookmarks *storage.BookmarkStorage,
+	users *storage.UserStorage,
 	media *storage.MediaStorage,
 	streamCache *storage.StreamCacheStorage,
 	ffmpeg *ffmpeg.Ffmpeg,
 	thumbnails *ThumbnailService,
 	scrobbler *ScrobbleService,
 	ytdlp *YtdlpService,
+	podcasts *PodcastService,
+	lyrics *LyricsService,
 ) SubsonicService {
 	return &subsonicInternalService{
 		tracks:      tracks,
+		sources:     sources,
 		albums:      albums,
+		artists:     artists,
+		genres:      genres,
 		playlists:   playlists,
+		tapes:       tapes,
 		listens:     listens,
+		starred:     starred,
+		ratings:     ratings,
+		bookmarks:   bookmarks,
+		users:       users,
 		media:       media,
 		streamCache: streamCache,
 		ffmpeg:      ffmpeg,
 		thumbnails:  thumbnails,
 		scrobbler:   scrobbler,
 		ytdlp:       ytdlp,
+		podcasts:    podcasts,
+		lyrics:      lyrics,
 	}
 }
 
 func (svc *subsonicInternalService) Search3(
+	username string,
 	query string,
 	artistCount int,
 	artistOffset int,
@@ -71,26 +105,38 @@ func (svc *subsonicInternalService) Search3(
 ) (*responses.SearchResult3, error) {
 	var err error = nil
 
+	var artists []storage.SubsonicArtistItem
 	var albums []storage.SubsonicAlbumItem
 	var songs []storage.SubsonicTrackItem
 
 	query = strings.TrimSpace(query)
 	if query == "" {
-		if albums, err = svc.albums.GetSubsonicAlbumsSortId(albumCount, albumOffset); err != nil {
+		if artists, err = svc.artists.GetSubsonicArtistsSortId(username, artistCount, artistOffset); err != nil {
 			return nil, err
 		}
-		if songs, err = svc.tracks.GetSubsonicTracksSortId(songCount, songOffset); err != nil {
+		if albums, err = svc.albums.GetSubsonicAlbumsSortId(username, albumCount, albumOffset); err != nil {
+			return nil, err
+		}
+		if songs, err = svc.tracks.GetSubsonicTracksSortId(username, songCount, songOffset); err != nil {
 			return nil, err
 		}
 	} else {
-		if albums, err = svc.albums.SearchSubsonicAlbums(albumCount, albumOffset, query); err != nil {
+		if artists, err = svc.artists.SearchSubsonicArtists(username, artistCount, artistOffset, query); err != nil {
+			return nil, err
+		}
+		if albums, err = svc.albums.SearchSubsonicAlbums(username, albumCount, albumOffset, query); err != nil {
 			return nil, err
 		}
-		if songs, err = svc.tracks.SearchSubsonicTracks(songCount, songOffset, query); err != nil {
+		if songs, err = svc.tracks.SearchSubsonicTracks(username, songCount, songOffset, query); err != nil {
 			return nil, err
 		}
 	}
 
+	artistsResponse := []responses.ArtistId3{}
+	for _, artist := range artists {
+		artistsResponse = append(artistsResponse, toArtistId3(artist))
+	}
+
 	albumsResponse := []responses.AlbumId3{}
 	for _, album := range albums {
 		albumsResponse = append(albumsResponse, toAlbumId3(album))
@@ -102,19 +148,19 @@ func (svc *subsonicInternalService) Search3(
 	}
 
 	return &responses.SearchResult3{
-		Artist: []responses.ArtistId3{}, // todo
+		Artist: artistsResponse,
 		Album:  albumsResponse,
 		Song:   songsResponse,
 	}, nil
 }
 
-func (svc *subsonicInternalService) GetSong(rawId string) (*responses.SubsonicChild, error) {
+func (svc *subsonicInternalService) GetSong(username string, rawId string) (*responses.SubsonicChild, error) {
 	id, err := decodeId(rawId)
 	if err != nil {
 		return nil, err
 	}
 
-	track, err := svc.tracks.GetSubsonicTrack(id)
+	track, err := svc.tracks.GetSubsonicTrack(username, id)
 	if err != nil {
 		return nil, err
 	}
@@ -125,13 +171,8 @@ func (svc *subsonicInternalService) GetSong(rawId string) (*responses.SubsonicCh
 	return &songResponse, nil
 }
 
-func (svc *subsonicInternalService) GetRandomSongs(size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
-	if genre != "" {
-		// todo
-		return responses.NewRandomSongs([]responses.SubsonicChild{}), nil
-	}
-
-	songs, err := svc.tracks.GetSubsonicTracksSortRandom(size, fromYear, toYear)
+func (svc *subsonicInternalService) GetRandomSongs(username string, size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
+	songs, err := svc.tracks.GetSubsonicTracksSortRandom(username, size, genre, fromYear, toYear)
 	if err != nil {
 		return nil, err
 	}
@@ -147,18 +188,32 @@ func (svc *subsonicInternalService) GetRandomSongs(size int, genre string, fromY
 	return responses.NewRandomSongs(songsResponse), nil
 }
 
-func (svc *subsonicInternalService) GetAlbum(rawId string) (*responses.AlbumId3, error) {
+func (svc *subsonicInternalService) GetSongsByGenre(username string, genre string, count int, offset int) (*responses.SongsByGenre, error) {
+	songs, err := svc.tracks.GetSubsonicTracksByGenre(username, count, offset, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	songsResponse := []responses.SubsonicChild{}
+	for _, song := range songs {
+		songsResponse = append(songsResponse, toChild(song))
+	}
+
+	return responses.NewSongsByGenre(songsResponse), nil
+}
+
+func (svc *subsonicInternalService) GetAlbum(username string, rawId string) (*responses.AlbumId3, error) {
 	id, err := decodeId(rawId)
 	if err != nil {
 		return nil, err
 	}
 
-	album, err := svc.albums.GetSubsonicAlbum(id)
+	album, err := svc.albums.GetSubsonicAlbum(username, id)
 	if err != nil {
 		return nil, err
 	}
 
-	tracks, err := svc.tracks.GetSubsonicTracksByAlbum(id)
+	tracks, err := svc.tracks.GetSubsonicTracksByAlbum(username, id)
 	if err != nil {
 		return nil, err
 	}
@@ -173,34 +228,44 @@ func (svc *subsonicInternalService) GetAlbum(rawId string) (*responses.AlbumId3,
 }
 
 func (svc *subsonicInternalService) GetAlbumList2(
+	username string,
 	type_ string,
 	size int,
 	offset int,
 	fromYear *int,
 	toYear *int,
+	genre string,
 ) (*responses.AlbumList2, error) {
 	var albums []storage.SubsonicAlbumItem
 	var err error
 	if type_ == LIST_RANDOM {
-		albums, err = svc.albums.GetSubsonicAlbumsSortRandom(size, offset)
+		albums, err = svc.albums.GetSubsonicAlbumsSortRandom(username, size, offset)
 	} else if type_ == LIST_NEWEST {
-		albums, err = svc.albums.GetSubsonicAlbumsSortNewest(size, offset)
+		albums, err = svc.albums.GetSubsonicAlbumsSortNewest(username, size, offset)
 	} else if type_ == LIST_BY_NAME {
-		albums, err = svc.albums.GetSubsonicAlbumsSortName(size, offset)
+		albums, err = svc.albums.GetSubsonicAlbumsSortName(username, size, offset)
 	} else if type_ == LIST_BY_ARTIST {
-		albums, err = svc.albums.GetSubsonicAlbumsSortArtist(size, offset)
+		albums, err = svc.albums.GetSubsonicAlbumsSortArtist(username, size, offset)
 	} else if type_ == LIST_RECENT {
-		albums, err = svc.albums.GetSubsonicAlbumsSortRecent(size, offset)
+		albums, err = svc.albums.GetSubsonicAlbumsSortRecent(username, size, offset)
 	} else if type_ == LIST_FREQUENT {
-		albums, err = svc.albums.GetSubsonicAlbumsSortFrequent(size, offset)
+		albums, err = svc.albums.GetSubsonicAlbumsSortFrequent(username, size, offset)
 	} else if type_ == LIST_STARRED {
-		albums, err = []storage.SubsonicAlbumItem{}, nil
+		albums, err = svc.albums.GetSubsonicAlbumsSortStarred(username, size, offset)
 	} else if type_ == LIST_BY_YEAR {
 		if fromYear == nil || toYear == nil {
 			return nil, fmt.Errorf("fromYear or toYear parameter missing")
 		}
 
-		albums, err = svc.albums.GetSubsonicAlbumsSortReleaseDate(size, offset, *fromYear, *toYear)
+		albums, err = svc.albums.GetSubsonicAlbumsSortReleaseDate(username, size, offset, *fromYear, *toYear)
+	} else if type_ == LIST_HIGHEST {
+		albums, err = svc.albums.GetSubsonicAlbumsSortRating(username, size, offset)
+	} else if type_ == LIST_BY_GENRE {
+		if genre == "" {
+			return nil, fmt.Errorf("genre parameter missing")
+		}
+
+		albums, err = svc.albums.GetSubsonicAlbumsByGenre(username, size, offset, genre)
 	} else {
 		return nil, fmt.Errorf("unsupported album sort order %s", type_)
 	}
@@ -217,18 +282,18 @@ func (svc *subsonicInternalService) GetAlbumList2(
 	return responses.NewAlbumList2(albumsResponse), nil
 }
 
-func (svc *subsonicInternalService) GetPlaylist(rawId string) (*responses.SubsonicPlaylist, error) {
+func (svc *subsonicInternalService) GetPlaylist(username string, rawId string) (*responses.SubsonicPlaylist, error) {
 	id, err := decodeId(rawId)
 	if err != nil {
 		return nil, err
 	}
 
-	playlist, err := svc.playlists.GetSubsonicPlaylist(id)
+	playlist, err := svc.playlists.GetSubsonicPlaylist(username, id)
 	if err != nil {
 		return nil, err
 	}
 
-	tracks, err := svc.tracks.GetSubsonicTracksByPlaylist(id)
+	tracks, err := svc.tracks.GetSubsonicTracksByPlaylist(username, id)
 	if err != nil {
 		return nil, err
 	}
@@ -245,8 +310,8 @@ func (svc *subsonicInternalService) GetPlaylist(rawId string) (*responses.Subson
 	return &playlistResponse, nil
 }
 
-func (svc *subsonicInternalService) GetPlaylists() (*responses.SubsonicPlaylists, error) {
-	playlists, err := svc.playlists.GetSubsonicPlaylists(math.MaxInt32, 0)
+func (svc *subsonicInternalService) GetPlaylists(username string) (*responses.SubsonicPlaylists, error) {
+	playlists, err := svc.playlists.GetSubsonicPlaylists(username, math.MaxInt32, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -259,9 +324,193 @@ func (svc *subsonicInternalService) GetPlaylists() (*responses.SubsonicPlaylists
 	return responses.NewSubsonicPlaylists(playlistsResponse), nil
 }
 
-func (svc *subsonicInternalService) GetArtist(id string) (*responses.Artist, error) {
-	// todo
-	return nil, fmt.Errorf("not supported yet")
+func (svc *subsonicInternalService) CreatePlaylist(username string, rawPlaylistId string, name string, rawSongIds []string) (*responses.SubsonicPlaylist, error) {
+	songIds, err := decodeTrackIds(rawSongIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var tape storage.Tape
+	if rawPlaylistId != "" {
+		tape, err = svc.getPlaylistTape(username, rawPlaylistId)
+		if err != nil {
+			return nil, err
+		}
+
+		if name != "" {
+			tape.Name = name
+		}
+		tape.Tracks = toTapeTracks(songIds)
+
+		tape, err = svc.tapes.Update(tape)
+	} else {
+		tape, err = svc.tapes.Create(storage.Tape{
+			Name:   name,
+			Type:   storage.TAPE_TYPE_PLAYLIST,
+			Tracks: toTapeTracks(songIds),
+			Owner:  username,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.GetPlaylist(username, encodeId(tape.Id.String()))
+}
+
+func (svc *subsonicInternalService) UpdatePlaylist(username string, rawPlaylistId string, name string, rawSongIdsToAdd []string, songIndexesToRemove []int) error {
+	songIdsToAdd, err := decodeTrackIds(rawSongIdsToAdd)
+	if err != nil {
+		return err
+	}
+
+	tape, err := svc.getPlaylistTape(username, rawPlaylistId)
+	if err != nil {
+		return err
+	}
+
+	tracks, err := svc.tracks.GetTracksByTape(tape.Id)
+	if err != nil {
+		return err
+	}
+
+	songIds := []uuid.UUID{}
+	for i, track := range tracks {
+		if !slices.Contains(songIndexesToRemove, i) {
+			songIds = append(songIds, track.Id)
+		}
+	}
+	songIds = append(songIds, songIdsToAdd...)
+
+	if name != "" {
+		tape.Name = name
+	}
+	tape.Tracks = toTapeTracks(songIds)
+
+	_, err = svc.tapes.Update(tape)
+	return err
+}
+
+func (svc *subsonicInternalService) DeletePlaylist(username string, rawPlaylistId string) error {
+	tape, err := svc.getPlaylistTape(username, rawPlaylistId)
+	if err != nil {
+		return err
+	}
+
+	return svc.tapes.DeleteById(tape.Id)
+}
+
+// playlists of other users are treated as non-existent; playlists without an owner
+// are visible to everyone but can only be changed by admins
+func (svc *subsonicInternalService) getPlaylistTape(username string, rawId string) (storage.Tape, error) {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return storage.Tape{}, err
+	}
+
+	tape, err := svc.tapes.GetTape(id)
+	if err != nil {
+		return storage.Tape{}, err
+	}
+	if tape.Type != storage.TAPE_TYPE_PLAYLIST || (tape.Owner != "" && tape.Owner != username) {
+		return storage.Tape{}, fmt.Errorf("playlist with id %s doesn't exist", id.String())
+	}
+
+	if tape.Owner == "" {
+		user, err := svc.users.Find(username)
+		if err != nil {
+			return storage.Tape{}, err
+		}
+		if user == nil || user.Role != storage.USER_ROLE_ADMIN {
+			return storage.Tape{}, ErrReadOnlyPlaylist
+		}
+	}
+
+	return tape, nil
+}
+
+// a track can be present in a tape only once, so the duplicates are dropped
+func toTapeTracks(trackIds []uuid.UUID) []storage.TapeToTrack {
+	result := []storage.TapeToTrack{}
+	for i, trackId := range trackIds {
+		if slices.Index(trackIds, trackId) != i {
+			continue
+		}
+
+		result = append(result, storage.TapeToTrack{TrackId: trackId})
+	}
+	return result
+}
+
+func (svc *subsonicInternalService) GetGenres() (*responses.Genres, error) {
+	genres, err := svc.genres.GetSubsonicGenres()
+	if err != nil {
+		return nil, err
+	}
+
+	genresResponse := []responses.Genre{}
+	for _, genre := range genres {
+		genresResponse = append(genresResponse, *responses.NewGenre(genre.Value, genre.SongCount, genre.AlbumCount))
+	}
+
+	return responses.NewGenres(genresResponse), nil
+}
+
+func (svc *subsonicInternalService) GetArtists(username string) (*responses.Artists, error) {
+	artists, err := svc.artists.GetSubsonicArtistsSortName(username, math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	artistsResponse := []responses.ArtistId3{}
+	for _, artist := range artists {
+		artistsResponse = append(artistsResponse, toArtistId3(artist))
+	}
+
+	return responses.NewArtists(
+		DEFAULT_IGNORED_ARTICLES,
+		NewArtistIndex(artistsResponse, DEFAULT_IGNORED_ARTICLES),
+	), nil
+}
+
+func (svc *subsonicInternalService) GetArtist(username string, rawId string) (*responses.Artist, error) {
+	id := decodeArtistId(rawId)
+	artist, err := svc.artists.GetSubsonicArtist(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	albums, err := svc.albums.GetSubsonicAlbumsByArtist(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	artistResponse := responses.NewArtist(encodeArtistId(artist.Id), artist.Name)
+	if artist.ThumbnailId != nil {
+		artistResponse.CoverArt = encodeId(artist.ThumbnailId.String())
+	}
+	artistResponse.AlbumCount = artist.AlbumCount
+	artistResponse.Starred = artist.StarredAt
+
+	artistResponse.Album = []responses.AlbumId3{}
+	for _, album := range albums {
+		artistResponse.Album = append(artistResponse.Album, toAlbumId3(album))
+	}
+
+	return artistResponse, nil
+}
+
+func toArtistId3(artist storage.SubsonicArtistItem) responses.ArtistId3 {
+	artistResponse := responses.NewArtistId3(encodeArtistId(artist.Id), artist.Name)
+
+	if artist.ThumbnailId != nil {
+		artistResponse.CoverArt = encodeId(artist.ThumbnailId.String())
+	}
+
+	artistResponse.AlbumCount = artist.AlbumCount
+	artistResponse.Starred = artist.StarredAt
+
+	return *artistResponse
 }
 
 func toAlbumId3(album storage.SubsonicAlbumItem) responses.AlbumId3 {
@@ -289,7 +538,14 @@ func toAlbumId3(album storage.SubsonicAlbumItem) responses.AlbumId3 {
 		)
 	}
 
+	albumResponse.ArtistId = encodeArtistId(album.ArtistId)
+	if len(album.Genres) > 0 {
+		albumResponse.Genre = album.Genres[0]
+	}
 	albumResponse.PlayCount = album.PlayCount
+	albumResponse.Starred = album.StarredAt
+	albumResponse.UserRating = album.UserRating
+	albumResponse.AverageRating = album.AverageRating
 
 	return *albumResponse
 }
@@ -307,6 +563,7 @@ func toPlaylist(playlist storage.SubsonicPlaylistItem) responses.SubsonicPlaylis
 	if playlist.ThumbnailId != nil {
 		responsePlaylist.CoverArt = encodeId(playlist.ThumbnailId.String())
 	}
+	responsePlaylist.Owner = playlist.Owner
 
 	return *responsePlaylist
 }
@@ -335,161 +592,987 @@ func toChild(track storage.SubsonicTrackItem) responses.SubsonicChild {
 		trackResponse.Album = track.Album
 	}
 
+	trackResponse.ArtistId = encodeArtistId(track.ArtistId)
+	if len(track.Genres) > 0 {
+		trackResponse.Genre = track.Genres[0]
+	}
+
 	trackResponse.PlayCount = track.PlayCount
+	trackResponse.Starred = track.StarredAt
+	trackResponse.UserRating = track.UserRating
+	trackResponse.AverageRating = track.AverageRating
+
+	trackResponse.MediaType = responses.MEDIA_TYPE_SONG
+	trackResponse.Played = track.LastPlayedAt
+	trackResponse.Artists = []responses.ArtistId3{*responses.NewArtistId3(encodeArtistId(track.ArtistId), track.Artist)}
 
 	return *trackResponse
 }
 
-func (svc *subsonicInternalService) Scrobble(rawId string, time_ time.Time, submission bool) error {
+func (svc *subsonicInternalService) Scrobble(username string, rawId string, time_ time.Time, submission bool) error {
 	id, err := decodeId(rawId)
 	if err != nil {
 		return err
 	}
 
-	selfErr := svc.listens.Record(id, time_, submission)
-	scrobblerError := svc.scrobbleWithScrobbler(rawId, time_, submission)
+	selfErr := svc.listens.Record(username, id, time_, submission)
+	scrobblerError := svc.scrobbleWithScrobbler(username, rawId, time_, submission)
 
 	return errors.Join(selfErr, scrobblerError)
 }
 
-func (svc *subsonicInternalService) scrobbleWithScrobbler(rawId string, time_ time.Time, submission bool) error {
+func (svc *subsonicInternalService) scrobbleWithScrobbler(username string, rawId string, time_ time.Time, submission bool) error {
 	if svc.scrobbler == nil {
 		return nil
 	}
 
-	song, err := svc.GetSong(rawId)
+	song, err := svc.GetSong(username, rawId)
 	if err != nil {
 		return err
 	}
 
 	if submission {
-		return svc.scrobbler.ScrobbleCompleted(time_, song.Artist, song.Album, song.Title)
+		return svc.scrobbler.ScrobbleCompleted(username, time_, song.Artist, song.Album, song.Title)
 	} else {
-		return svc.scrobbler.ScrobblePlaying(song.Artist, song.Album, song.Title)
+		return svc.scrobbler.ScrobblePlaying(username, song.Artist, song.Album, song.Title)
 	}
 }
 
-func (svc *subsonicInternalService) GetCoverArt(rawId string) (mediaType string, reader io.ReadCloser, err error) {
-	id, err := decodeId(rawId)
+func (svc *subsonicInternalService) Star(username string, rawIds []string, rawAlbumIds []string, rawArtistIds []string) error {
+	ids, err := decodeIds(rawIds)
 	if err != nil {
-		return "", nil, err
+		return err
+	}
+	albumIds, err := decodeIds(rawAlbumIds)
+	if err != nil {
+		return err
 	}
 
-	return svc.thumbnails.GetThumbnailContent(id)
+	starredAt := time.Now()
+	return errors.Join(
+		svc.starred.Star(username, storage.STARRED_ITEM_TYPE_SONG, ids, starredAt),
+		svc.starred.Star(username, storage.STARRED_ITEM_TYPE_ALBUM, albumIds, starredAt),
+		svc.starred.Star(username, storage.STARRED_ITEM_TYPE_ARTIST, decodeArtistIds(rawArtistIds), starredAt),
+	)
 }
 
-// some codecs like mp4/alac are not supported by Chromium-based clients
-var ALLOWED_STREAMING_CODECS = []string{"mp3", "flac", "opus"}
+func (svc *subsonicInternalService) Unstar(username string, rawIds []string, rawAlbumIds []string, rawArtistIds []string) error {
+	ids, err := decodeIds(rawIds)
+	if err != nil {
+		return err
+	}
+	albumIds, err := decodeIds(rawAlbumIds)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(
+		svc.starred.Unstar(username, storage.STARRED_ITEM_TYPE_SONG, ids),
+		svc.starred.Unstar(username, storage.STARRED_ITEM_TYPE_ALBUM, albumIds),
+		svc.starred.Unstar(username, storage.STARRED_ITEM_TYPE_ARTIST, decodeArtistIds(rawArtistIds)),
+	)
+}
 
-func (svc *subsonicInternalService) Stream(ctx context.Context, rawId string) (AudioStream, error) {
+func (svc *subsonicInternalService) SetRating(username string, rawId string, rating int) error {
 	id, err := decodeId(rawId)
 	if err != nil {
-		return AudioStream{}, err
+		return err
 	}
 
-	track, err := svc.media.GetTrackSources(id)
+	// songs and albums share the same id format, so we have to look it up
+	tape, err := svc.tapes.GetTape(id)
 	if err != nil {
-		return AudioStream{}, err
+		return err
+	}
+	if tape.Type == storage.TAPE_TYPE_ALBUM {
+		return svc.ratings.SetRating(username, storage.RATING_ITEM_TYPE_ALBUM, id.String(), rating, time.Now())
 	}
 
-	if track.LocalPath != "" {
-		allowDirectStreaming := true
-		switch {
-		case track.StartOffsetMs > 0:
-			slog.Debug(fmt.Sprintf("Direct streaming for track id=`%s` (%s) is forbidden because StartOffsetMs > 0 (%d)", id, track.LocalPath, track.StartOffsetMs))
-			allowDirectStreaming = false
-		case track.EndOffsetMs != track.SourceDurationMs:
-			slog.Debug(fmt.Sprintf("Direct streaming for track id=`%s` (%s) is forbidden because EndOffsetMs != SourceDurationMs (%d != %d)", id, track.LocalPath, track.EndOffsetMs, track.SourceDurationMs))
-			allowDirectStreaming = false
-		case !slices.Contains(ALLOWED_STREAMING_CODECS, track.LocalCodec):
-			slog.Debug(fmt.Sprintf("Direct streaming for track id=`%s` (%s) is forbidden because codec `%s` is not allowed", id, track.LocalPath, track.LocalCodec))
-			allowDirectStreaming = false
-		}
-
-		if allowDirectStreaming {
-			slog.Debug(fmt.Sprintf("Streaming downloaded track id=`%s` (%s) directly from file", id, track.LocalPath))
-
-			reader, err := os.Open(track.LocalPath)
-			if err != nil {
-				return AudioStream{}, err
-			}
+	if _, err := svc.tracks.GetSubsonicTrack(username, id); err != nil {
+		return err
+	}
 
-			slog.Debug(fmt.Sprintf("Got streaming data for track id=`%s`", id))
+	return svc.ratings.SetRating(username, storage.RATING_ITEM_TYPE_SONG, id.String(), rating, time.Now())
+}
 
-			return AudioStream{
-				Reader:   reader,
-				MimeType: util.FormatToMediaType(track.LocalFormat),
-			}, nil
-		} else {
-			slog.Debug(fmt.Sprintf("Streaming downloaded track id=`%s` (%s) via ffmpeg, start=%d, end=%d", id, track.LocalPath, track.StartOffsetMs, track.EndOffsetMs))
+func (svc *subsonicInternalService) CreateBookmark(username string, rawId string, positionMs int64, comment string) error {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return err
+	}
 
-			item, reader, err := svc.streamCache.GetOrSave(fmt.Sprintf("tapesonic-%s", id), func() (string, io.ReadCloser, error) {
-				slog.Debug(fmt.Sprintf("Populating stream cache for track id=`%s`", id))
+	track, err := svc.media.GetTrackSources(id)
+	if err != nil {
+		return err
+	}
+	if track.RemoteUrl == "" {
+		return fmt.Errorf("track with id %s doesn't exist", id)
+	}
 
-				format, reader, err := svc.ffmpeg.StreamFrom(
-					ctx,
-					track.LocalCodec,
-					ffmpeg.ANY_FORMAT,
-					track.StartOffsetMs,
-					track.EndOffsetMs-track.StartOffsetMs,
-					track.LocalPath,
-				)
-				if err != nil {
-					return "", nil, err
-				}
+	// the client only knows about the track, which may be just a chapter of a longer source
+	positionMs = min(max(positionMs, 0), track.EndOffsetMs-track.StartOffsetMs)
 
-				return util.FormatToMediaType(format), reader, nil
-			})
-			if err != nil {
-				return AudioStream{}, err
-			}
+	return svc.bookmarks.Upsert(storage.Bookmark{
+		Username:         username,
+		TrackId:          id,
+		SourcePositionMs: track.StartOffsetMs + positionMs,
+		Comment:          comment,
+	})
+}
 
-			slog.Debug(fmt.Sprintf("Got streaming data for track id=`%s`", id))
+func (svc *subsonicInternalService) GetBookmarks(username string) (*responses.Bookmarks, error) {
+	bookmarks, err := svc.bookmarks.GetAllByUsername(username)
+	if err != nil {
+		return nil, err
+	}
 
-			return AudioStream{
-				Reader:   reader,
-				MimeType: item.ContentType,
-			}, nil
-		}
-	} else if track.RemoteUrl != "" {
-		streamInfo, err := svc.ytdlp.GetStreamInfo(ctx, track.RemoteUrl, "ba")
+	bookmarksResponse := []responses.Bookmark{}
+	for _, bookmark := range bookmarks {
+		song, err := svc.GetSong(username, encodeId(bookmark.TrackId.String()))
 		if err != nil {
-			return AudioStream{}, err
+			return nil, err
 		}
 
-		slog.Debug(fmt.Sprintf("Streaming remote track id=`%s` (%s) via ffmpeg, start=%d, end=%d", id, track.RemoteUrl, track.StartOffsetMs, track.EndOffsetMs))
+		positionMs := min(max(bookmark.SourcePositionMs-bookmark.StartOffsetMs, 0), bookmark.EndOffsetMs-bookmark.StartOffsetMs)
 
-		format, reader, err := svc.ffmpeg.StreamFrom(
-			ctx,
-			streamInfo.ACodec,
-			ffmpeg.SEEKABLE_FORMAT,
-			track.StartOffsetMs,
-			track.EndOffsetMs-track.StartOffsetMs,
-			streamInfo.Url,
-		)
-		if err != nil {
-			return AudioStream{}, err
-		}
+		bookmarksResponse = append(bookmarksResponse, *responses.NewBookmark(
+			positionMs,
+			username,
+			bookmark.Comment,
+			bookmark.CreatedAt,
+			bookmark.UpdatedAt,
+			*song,
+		))
+	}
 
-		slog.Debug(fmt.Sprintf("Got streaming data for track id=`%s`", id))
+	return responses.NewBookmarks(bookmarksResponse), nil
+}
 
-		return AudioStream{
-			Reader:   reader,
-			MimeType: util.FormatToMediaType(format),
-		}, nil
-	} else {
-		return AudioStream{}, fmt.Errorf("no local path or remote url for track id=`%s`", id)
+func (svc *subsonicInternalService) DeleteBookmark(username string, rawId string) error {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return err
 	}
-}
 
-func (svc *subsonicInternalService) GetLicense() (*responses.License, error) {
-	return responses.NewLicense(true), nil
+	return svc.bookmarks.Delete(username, id)
 }
 
-func encodeId(id string) string {
-	return strings.ReplaceAll(fmt.Sprint(id), "-", "_")
-}
+func (svc *subsonicInternalService) GetStarred2(username string) (*responses.Starred2, error) {
+	artists, err := svc.artists.GetSubsonicArtistsSortStarred(username, math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
 
-func decodeId(rawId string) (uuid.UUID, error) {
-	return uuid.Parse(strings.ReplaceAll(rawId, "_", "-"))
+	albums, err := svc.albums.GetSubsonicAlbumsSortStarred(username, math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	songs, err := svc.tracks.GetSubsonicTracksSortStarred(username, math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	artistsResponse := []responses.ArtistId3{}
+	for _, artist := range artists {
+		artistsResponse = append(artistsResponse, toArtistId3(artist))
+	}
+
+	albumsResponse := []responses.AlbumId3{}
+	for _, album := range albums {
+		albumsResponse = append(albumsResponse, toAlbumId3(album))
+	}
+
+	songsResponse := []responses.SubsonicChild{}
+	for _, song := range songs {
+		songResponse := toChild(song)
+		songResponse.Track = 0
+
+		songsResponse = append(songsResponse, songResponse)
+	}
+
+	return responses.NewStarred2(
+		artistsResponse,
+		albumsResponse,
+		songsResponse,
+	), nil
+}
+
+func (svc *subsonicInternalService) GetPodcasts(rawId string, includeEpisodes bool) (*responses.Podcasts, error) {
+	channels := []storage.PodcastChannel{}
+	if rawId != "" {
+		id, err := decodeId(rawId)
+		if err != nil {
+			return nil, err
+		}
+
+		channel, err := svc.podcasts.GetChannel(id)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	} else {
+		var err error
+		if channels, err = svc.podcasts.GetChannels(); err != nil {
+			return nil, err
+		}
+	}
+
+	channelsResponse := []responses.PodcastChannel{}
+	for _, channel := range channels {
+		channelResponse := toPodcastChannel(channel)
+
+		if includeEpisodes {
+			episodes, err := svc.podcasts.GetSubsonicEpisodesByChannel(channel.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			channelResponse.Episode = []responses.PodcastEpisode{}
+			for _, episode := range episodes {
+				channelResponse.Episode = append(channelResponse.Episode, toPodcastEpisode(episode))
+			}
+		}
+
+		channelsResponse = append(channelsResponse, channelResponse)
+	}
+
+	return responses.NewPodcasts(channelsResponse), nil
+}
+
+func (svc *subsonicInternalService) GetNewestPodcasts(count int) (*responses.NewestPodcasts, error) {
+	episodes, err := svc.podcasts.GetSubsonicNewestEpisodes(count)
+	if err != nil {
+		return nil, err
+	}
+
+	episodesResponse := []responses.PodcastEpisode{}
+	for _, episode := range episodes {
+		episodesResponse = append(episodesResponse, toPodcastEpisode(episode))
+	}
+
+	return responses.NewNewestPodcasts(episodesResponse), nil
+}
+
+func (svc *subsonicInternalService) CreatePodcastChannel(url string) error {
+	channel, err := svc.podcasts.CreateChannel(context.Background(), url)
+	if err != nil {
+		return err
+	}
+
+	// fetching metadata for every new episode takes a while, so it's done in background
+	go svc.refreshPodcastChannel(channel.Id)
+	return nil
+}
+
+func (svc *subsonicInternalService) refreshPodcastChannel(id uuid.UUID) {
+	if err := svc.podcasts.RefreshChannel(context.Background(), id); err != nil {
+		slog.Error(fmt.Sprintf("Failed to refresh podcast channel id=%s: %s", id, err))
+	}
+}
+
+func (svc *subsonicInternalService) DeletePodcastChannel(rawId string) error {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return err
+	}
+
+	return svc.podcasts.DeleteChannel(id)
+}
+
+func (svc *subsonicInternalService) RefreshPodcasts() error {
+	go func() {
+		if err := svc.podcasts.RefreshAll(context.Background()); err != nil {
+			slog.Error(fmt.Sprintf("Failed to refresh podcasts: %s", err))
+		}
+	}()
+	return nil
+}
+
+func (svc *subsonicInternalService) DownloadPodcastEpisode(rawId string) error {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := svc.podcasts.DownloadEpisode(id); err != nil {
+			slog.Error(fmt.Sprintf("Failed to download podcast episode id=%s: %s", id, err))
+		}
+	}()
+	return nil
+}
+
+func toPodcastChannel(channel storage.PodcastChannel) responses.PodcastChannel {
+	channelResponse := responses.NewPodcastChannel(
+		encodeId(channel.Id.String()),
+		channel.Url,
+		channel.Title,
+		channel.Status,
+	)
+
+	channelResponse.Description = channel.Description
+	if channel.ThumbnailId != nil {
+		channelResponse.CoverArt = encodeId(channel.ThumbnailId.String())
+	}
+	channelResponse.OriginalImageUrl = channel.ThumbnailUrl
+	channelResponse.ErrorMessage = channel.ErrorMessage
+
+	return *channelResponse
+}
+
+func toPodcastEpisode(episode storage.SubsonicPodcastEpisodeItem) responses.PodcastEpisode {
+	child := responses.NewSubsonicChild(
+		encodeId(episode.Id),
+		false,
+		episode.Uploader,
+		episode.Title,
+		0,
+		episode.DurationSec,
+	)
+	child.Album = episode.ChannelTitle
+	child.MediaType = responses.MEDIA_TYPE_PODCAST
+	if episode.ThumbnailId != nil {
+		child.CoverArt = encodeId(episode.ThumbnailId.String())
+	}
+
+	status := storage.PODCAST_STATUS_NEW
+	if episode.Downloaded {
+		status = storage.PODCAST_STATUS_COMPLETED
+	} else if episode.DownloadStatus == storage.PODCAST_STATUS_DOWNLOADING || episode.DownloadStatus == storage.PODCAST_STATUS_ERROR {
+		status = episode.DownloadStatus
+	}
+
+	episodeResponse := responses.NewPodcastEpisode(*child, encodeId(episode.ChannelId), status)
+	// episodes can be streamed without downloading them first
+	episodeResponse.StreamId = child.Id
+	episodeResponse.Description = episode.Description
+	episodeResponse.PublishDate = &episode.PublishedAt
+
+	return *episodeResponse
+}
+
+func (svc *subsonicInternalService) GetLyrics(artist string, title string) (*responses.Lyrics, error) {
+	track, err := svc.tracks.FindSubsonicTrackByArtistAndTitle("", artist, title)
+	if err != nil {
+		return nil, err
+	}
+	if track == nil {
+		return responses.NewLyrics("", "", ""), nil
+	}
+
+	id, err := uuid.Parse(track.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	lyrics, err := svc.lyrics.GetForTrack(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+	if lyrics == nil {
+		return responses.NewLyrics("", "", ""), nil
+	}
+
+	lines := []string{}
+	for _, line := range lyrics.Lines {
+		lines = append(lines, line.Text)
+	}
+
+	return responses.NewLyrics(track.Artist, track.Title, strings.Join(lines, "\n")), nil
+}
+
+func (svc *subsonicInternalService) GetLyricsBySongId(rawId string) (*responses.LyricsList, error) {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return nil, err
+	}
+
+	lyrics, err := svc.lyrics.GetForTrack(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+	if lyrics == nil || len(lyrics.Lines) == 0 {
+		return responses.NewLyricsList([]responses.StructuredLyrics{}), nil
+	}
+
+	track, err := svc.tracks.GetSubsonicTrack("", id)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []responses.LyricsLine{}
+	for _, line := range lyrics.Lines {
+		start := line.StartMs
+		lines = append(lines, *responses.NewLyricsLine(&start, line.Text))
+	}
+
+	structuredLyrics := responses.NewStructuredLyrics(lyrics.Language, true, lines)
+	structuredLyrics.DisplayArtist = track.Artist
+	structuredLyrics.DisplayTitle = track.Title
+
+	return responses.NewLyricsList([]responses.StructuredLyrics{*structuredLyrics}), nil
+}
+
+const (
+	internalMusicFolderId   = "library"
+	uploaderDirectoryPrefix = "uploader_"
+	artistIdPrefix          = "artist_"
+)
+
+func (svc *subsonicInternalService) GetMusicFolders() (*responses.MusicFolders, error) {
+	return responses.NewMusicFolders([]responses.MusicFolder{*responses.NewMusicFolder(internalMusicFolderId, "Tapesonic")}), nil
+}
+
+// uploaders are the top-level directories, there's only a single music folder so it's ignored
+func (svc *subsonicInternalService) GetIndexes(username string, musicFolderId string) (*responses.Indexes, error) {
+	uploaders, err := svc.sources.GetUploadersForDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	directories := []responses.ArtistId3{}
+	for _, uploader := range uploaders {
+		directories = append(directories, *responses.NewArtistId3(encodeUploaderDirectoryId(uploader), getUploaderDirectoryName(uploader)))
+	}
+
+	lastModified := int64(0)
+	lastUpdatedAt, err := svc.sources.GetLastUpdatedAtForDirectory()
+	if err != nil {
+		return nil, err
+	}
+	if lastUpdatedAt != nil {
+		lastModified = lastUpdatedAt.UnixMilli()
+	}
+
+	return responses.NewIndexes(
+		lastModified,
+		DEFAULT_IGNORED_ARTICLES,
+		NewArtistIndex(directories, DEFAULT_IGNORED_ARTICLES),
+	), nil
+}
+
+// uploader directories contain playlists as subdirectories and tracks of all other sources,
+// playlist directories contain tracks of their entries
+func (svc *subsonicInternalService) GetMusicDirectory(username string, rawId string) (*responses.Directory, error) {
+	if strings.HasPrefix(rawId, uploaderDirectoryPrefix) {
+		uploader, err := decodeUploaderDirectoryId(rawId)
+		if err != nil {
+			return nil, err
+		}
+
+		sources, err := svc.sources.GetForDirectoryByUploader(uploader)
+		if err != nil {
+			return nil, err
+		}
+		if len(sources) == 0 {
+			return nil, fmt.Errorf("uploader directory `%s` doesn't exist", rawId)
+		}
+
+		children := []responses.SubsonicChild{}
+		trackSourceIds := []uuid.UUID{}
+		for _, source := range sources {
+			if source.IsPlaylist {
+				child := responses.NewSubsonicChild(encodeId(source.Id.String()), true, source.Uploader, source.Title, 0, 0)
+				child.Parent = rawId
+				if source.ThumbnailId != nil {
+					child.CoverArt = encodeId(source.ThumbnailId.String())
+				}
+				children = append(children, *child)
+			} else {
+				trackSourceIds = append(trackSourceIds, source.Id)
+			}
+		}
+
+		tracks, err := svc.getDirectoryTracks(username, rawId, trackSourceIds)
+		if err != nil {
+			return nil, err
+		}
+
+		return responses.NewDirectory(rawId, getUploaderDirectoryName(uploader), append(children, tracks...)), nil
+	}
+
+	if strings.HasPrefix(rawId, artistIdPrefix) {
+		return svc.getArtistDirectory(username, rawId)
+	}
+
+	id, err := decodeId(rawId)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := svc.sources.FindForDirectoryById(id)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return svc.getAlbumDirectory(username, rawId)
+	}
+
+	trackSourceIds := []uuid.UUID{source.Id}
+	if source.IsPlaylist {
+		if trackSourceIds, err = svc.sources.GetChildIds(source.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	tracks, err := svc.getDirectoryTracks(username, rawId, trackSourceIds)
+	if err != nil {
+		return nil, err
+	}
+
+	directory := responses.NewDirectory(rawId, source.Title, tracks)
+	directory.Parent = encodeUploaderDirectoryId(source.Uploader)
+	return directory, nil
+}
+
+// artists and albums returned from non-ID3 endpoints are browsed as directories too
+func (svc *subsonicInternalService) getArtistDirectory(username string, rawId string) (*responses.Directory, error) {
+	artist, err := svc.GetArtist(username, rawId)
+	if err != nil {
+		return nil, fmt.Errorf("directory `%s` doesn't exist: %w", rawId, err)
+	}
+
+	children := []responses.SubsonicChild{}
+	for _, album := range artist.Album {
+		children = append(children, NewAlbumChild(album))
+	}
+
+	return responses.NewDirectory(rawId, artist.Name, children), nil
+}
+
+func (svc *subsonicInternalService) getAlbumDirectory(username string, rawId string) (*responses.Directory, error) {
+	album, err := svc.GetAlbum(username, rawId)
+	if err != nil {
+		return nil, fmt.Errorf("directory `%s` doesn't exist: %w", rawId, err)
+	}
+
+	children := []responses.SubsonicChild{}
+	for _, song := range album.Song {
+		song.Parent = rawId
+		children = append(children, song)
+	}
+
+	directory := responses.NewDirectory(rawId, album.Name, children)
+	directory.Parent = album.ArtistId
+	return directory, nil
+}
+
+func (svc *subsonicInternalService) getDirectoryTracks(username string, parentId string, sourceIds []uuid.UUID) ([]responses.SubsonicChild, error) {
+	tracks, err := svc.tracks.GetSubsonicTracksBySources(username, sourceIds)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []responses.SubsonicChild{}
+	for _, track := range tracks {
+		child := toChild(track)
+		child.Parent = parentId
+		child.Track = 0
+		children = append(children, child)
+	}
+
+	return children, nil
+}
+
+// uploader names can contain anything, so they're hex-encoded to be safe to use as an id
+func encodeUploaderDirectoryId(uploader string) string {
+	return uploaderDirectoryPrefix + hex.EncodeToString([]byte(uploader))
+}
+
+func decodeUploaderDirectoryId(rawId string) (string, error) {
+	uploader, err := hex.DecodeString(strings.TrimPrefix(rawId, uploaderDirectoryPrefix))
+	return string(uploader), err
+}
+
+// artist ids are hex-encoded names which can look like any other id, so they're prefixed
+// to tell artist directories apart; unprefixed ids are still accepted by ID3 endpoints
+func encodeArtistId(id string) string {
+	return artistIdPrefix + id
+}
+
+func decodeArtistId(rawId string) string {
+	return strings.TrimPrefix(rawId, artistIdPrefix)
+}
+
+func decodeArtistIds(rawIds []string) []string {
+	ids := []string{}
+	for _, rawId := range rawIds {
+		ids = append(ids, decodeArtistId(rawId))
+	}
+
+	return ids
+}
+
+func getUploaderDirectoryName(uploader string) string {
+	return util.Coalesce(uploader, "Unknown uploader")
+}
+
+func (svc *subsonicInternalService) GetCoverArt(rawId string, size int) (mediaType string, reader io.ReadCloser, err error) {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return svc.thumbnails.GetThumbnailVariant(id, size)
+}
+
+// some codecs like mp4/alac are not supported by Chromium-based clients
+var ALLOWED_STREAMING_CODECS = []string{"mp3", "flac", "opus"}
+
+func (svc *subsonicInternalService) Stream(ctx context.Context, rawId string, options StreamOptions) (AudioStream, error) {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	track, err := svc.getStreamSources(id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	startOffsetMs := track.StartOffsetMs + options.TimeOffsetMs
+	if startOffsetMs >= track.EndOffsetMs {
+		return AudioStream{}, fmt.Errorf("time offset %dms is beyond the end of track id=`%s`", options.TimeOffsetMs, id)
+	}
+
+	if track.LocalPath != "" {
+		allowDirectStreaming := true
+		switch {
+		case track.StartOffsetMs > 0:
+			slog.Debug(fmt.Sprintf("Direct streaming for track id=`%s` (%s) is forbidden because StartOffsetMs > 0 (%d)", id, track.LocalPath, track.StartOffsetMs))
+			allowDirectStreaming = false
+		case track.EndOffsetMs != track.SourceDurationMs:
+			slog.Debug(fmt.Sprintf("Direct streaming for track id=`%s` (%s) is forbidden because EndOffsetMs != SourceDurationMs (%d != %d)", id, track.LocalPath, track.EndOffsetMs, track.SourceDurationMs))
+			allowDirectStreaming = false
+		case !slices.Contains(ALLOWED_STREAMING_CODECS, track.LocalCodec):
+			slog.Debug(fmt.Sprintf("Direct streaming for track id=`%s` (%s) is forbidden because codec `%s` is not allowed", id, track.LocalPath, track.LocalCodec))
+			allowDirectStreaming = false
+		case options.IsTranscoded() && !(options.Format == track.LocalFormat && options.MaxBitRate == 0 && options.TimeOffsetMs == 0):
+			slog.Debug(fmt.Sprintf("Direct streaming for track id=`%s` (%s) is forbidden because transcoding is requested (%+v)", id, track.LocalPath, options))
+			allowDirectStreaming = false
+		}
+
+		if allowDirectStreaming {
+			slog.Debug(fmt.Sprintf("Streaming downloaded track id=`%s` (%s) directly from file", id, track.LocalPath))
+
+			reader, err := os.Open(track.LocalPath)
+			if err != nil {
+				return AudioStream{}, err
+			}
+
+			slog.Debug(fmt.Sprintf("Got streaming data for track id=`%s`", id))
+
+			return AudioStream{
+				Reader:   reader,
+				MimeType: util.FormatToMediaType(track.LocalFormat),
+			}, nil
+		} else if options.TimeOffsetMs > 0 {
+			// every offset would end up as a separate item in the stream cache, so such streams aren't cached
+			slog.Debug(fmt.Sprintf("Streaming downloaded track id=`%s` (%s) via ffmpeg, start=%d, end=%d, options=%+v", id, track.LocalPath, startOffsetMs, track.EndOffsetMs, options))
+
+			format, reader, err := svc.ffmpeg.StreamFrom(
+				ctx,
+				track.LocalCodec,
+				options.Format,
+				options.MaxBitRate,
+				startOffsetMs,
+				track.EndOffsetMs-startOffsetMs,
+				track.LocalPath,
+			)
+			if err != nil {
+				return AudioStream{}, err
+			}
+
+			slog.Debug(fmt.Sprintf("Got streaming data for track id=`%s`", id))
+
+			return AudioStream{
+				Reader:   reader,
+				MimeType: util.FormatToMediaType(format),
+				BitRate:  ffmpeg.GetBitRate(format, options.MaxBitRate),
+			}, nil
+		} else {
+			slog.Debug(fmt.Sprintf("Streaming downloaded track id=`%s` (%s) via ffmpeg, start=%d, end=%d, options=%+v", id, track.LocalPath, track.StartOffsetMs, track.EndOffsetMs, options))
+
+			item, reader, err := svc.streamCache.GetOrSave(getStreamCacheKey(id, options), func() (string, io.ReadCloser, error) {
+				slog.Debug(fmt.Sprintf("Populating stream cache for track id=`%s`", id))
+
+				format, reader, err := svc.ffmpeg.StreamFrom(
+					ctx,
+					track.LocalCodec,
+					options.Format,
+					options.MaxBitRate,
+					track.StartOffsetMs,
+					track.EndOffsetMs-track.StartOffsetMs,
+					track.LocalPath,
+				)
+				if err != nil {
+					return "", nil, err
+				}
+
+				return util.FormatToMediaType(format), reader, nil
+			})
+			if err != nil {
+				return AudioStream{}, err
+			}
+
+			slog.Debug(fmt.Sprintf("Got streaming data for track id=`%s`", id))
+
+			return AudioStream{
+				Reader:   reader,
+				MimeType: item.ContentType,
+			}, nil
+		}
+	} else if track.RemoteUrl != "" {
+		streamInfo, err := svc.ytdlp.GetStreamInfo(ctx, track.RemoteUrl, "ba")
+		if err != nil {
+			return AudioStream{}, err
+		}
+
+		slog.Debug(fmt.Sprintf("Streaming remote track id=`%s` (%s) via ffmpeg, start=%d, end=%d, options=%+v", id, track.RemoteUrl, startOffsetMs, track.EndOffsetMs, options))
+
+		format, reader, err := svc.ffmpeg.StreamFrom(
+			ctx,
+			streamInfo.ACodec,
+			util.Coalesce(options.Format, ffmpeg.SEEKABLE_FORMAT),
+			options.MaxBitRate,
+			startOffsetMs,
+			track.EndOffsetMs-startOffsetMs,
+			streamInfo.Url,
+		)
+		if err != nil {
+			return AudioStream{}, err
+		}
+
+		slog.Debug(fmt.Sprintf("Got streaming data for track id=`%s`", id))
+
+		return AudioStream{
+			Reader:   reader,
+			MimeType: util.FormatToMediaType(format),
+			BitRate:  ffmpeg.GetBitRate(format, options.MaxBitRate),
+		}, nil
+	} else {
+		return AudioStream{}, fmt.Errorf("no local path or remote url for track id=`%s`", id)
+	}
+}
+
+func (svc *subsonicInternalService) GetStreamSegments(rawId string) ([]int64, error) {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return nil, err
+	}
+
+	track, err := svc.getStreamSources(id)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := []int64{}
+	for offsetMs := track.StartOffsetMs; offsetMs < track.EndOffsetMs; offsetMs += HLS_SEGMENT_DURATION_MS {
+		segments = append(segments, min(HLS_SEGMENT_DURATION_MS, track.EndOffsetMs-offsetMs))
+	}
+	return segments, nil
+}
+
+// segments are encoded on demand and cached one by one, so seeking anywhere in a long track only
+// requires encoding the few seconds around the new position
+func (svc *subsonicInternalService) StreamSegment(ctx context.Context, rawId string, index int, maxBitRate int) (AudioStream, error) {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	track, err := svc.getStreamSources(id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	segmentStartMs := int64(index) * HLS_SEGMENT_DURATION_MS
+	startOffsetMs := track.StartOffsetMs + segmentStartMs
+	if index < 0 || startOffsetMs >= track.EndOffsetMs {
+		return AudioStream{}, fmt.Errorf("segment %d is beyond the end of track id=`%s`", index, id)
+	}
+	durationMs := min(HLS_SEGMENT_DURATION_MS, track.EndOffsetMs-startOffsetMs)
+
+	item, reader, err := svc.streamCache.GetOrSave(getSegmentCacheKey(id, index, maxBitRate), func() (string, io.ReadCloser, error) {
+		input := track.LocalPath
+		if input == "" {
+			if track.RemoteUrl == "" {
+				return "", nil, fmt.Errorf("no local path or remote url for track id=`%s`", id)
+			}
+
+			streamInfo, err := svc.ytdlp.GetStreamInfo(ctx, track.RemoteUrl, "ba")
+			if err != nil {
+				return "", nil, err
+			}
+			input = streamInfo.Url
+		}
+
+		slog.Debug(fmt.Sprintf("Encoding segment %d of track id=`%s` (%s) via ffmpeg, start=%d, duration=%d", index, id, input, startOffsetMs, durationMs))
+
+		reader, err := svc.ffmpeg.SegmentFrom(ctx, maxBitRate, startOffsetMs, durationMs, segmentStartMs, input)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return HLS_SEGMENT_MEDIA_TYPE, reader, nil
+	})
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	return AudioStream{
+		Reader:   reader,
+		MimeType: item.ContentType,
+		BitRate:  ffmpeg.GetBitRate(ffmpeg.SEGMENT_FORMAT, maxBitRate),
+	}, nil
+}
+
+// podcast episodes are streamed the same way as tracks
+func (svc *subsonicInternalService) getStreamSources(id uuid.UUID) (storage.TrackSourceDescriptor, error) {
+	track, err := svc.media.GetTrackSources(id)
+	if err != nil {
+		return storage.TrackSourceDescriptor{}, err
+	}
+
+	if track.RemoteUrl == "" {
+		return svc.media.GetPodcastEpisodeSources(id)
+	}
+	return track, nil
+}
+
+func (svc *subsonicInternalService) Download(ctx context.Context, rawId string) (AudioStream, error) {
+	id, err := decodeId(rawId)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	track, err := svc.tracks.GetSubsonicTrack("", id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	sources, err := svc.media.GetTrackSources(id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	baseFileName := sanitizeFileName(fmt.Sprintf("%s - %s", track.Artist, track.Title))
+
+	if sources.LocalPath != "" && sources.StartOffsetMs == 0 && sources.EndOffsetMs == sources.SourceDurationMs {
+		slog.Debug(fmt.Sprintf("Downloading track id=`%s` (%s) directly from file", id, sources.LocalPath))
+
+		reader, err := os.Open(sources.LocalPath)
+		if err != nil {
+			return AudioStream{}, err
+		}
+
+		return AudioStream{
+			Reader:   reader,
+			MimeType: util.FormatToMediaType(sources.LocalFormat),
+			FileName: baseFileName + path.Ext(sources.LocalPath),
+		}, nil
+	}
+
+	codec, input := sources.LocalCodec, sources.LocalPath
+	if input == "" {
+		if sources.RemoteUrl == "" {
+			return AudioStream{}, fmt.Errorf("no local path or remote url for track id=`%s`", id)
+		}
+
+		streamInfo, err := svc.ytdlp.GetStreamInfo(ctx, sources.RemoteUrl, "ba")
+		if err != nil {
+			return AudioStream{}, err
+		}
+		codec, input = streamInfo.ACodec, streamInfo.Url
+	}
+
+	slog.Debug(fmt.Sprintf("Downloading track id=`%s` (%s) via ffmpeg, start=%d, end=%d", id, input, sources.StartOffsetMs, sources.EndOffsetMs))
+
+	genre := ""
+	if len(track.Genres) > 0 {
+		genre = track.Genres[0]
+	}
+
+	format, reader, err := svc.ffmpeg.CutFrom(
+		ctx,
+		codec,
+		sources.StartOffsetMs,
+		sources.EndOffsetMs-sources.StartOffsetMs,
+		input,
+		map[string]string{
+			"artist": track.Artist,
+			"title":  track.Title,
+			"album":  track.Album,
+			"genre":  genre,
+		},
+	)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	return AudioStream{
+		Reader:   reader,
+		MimeType: util.FormatToMediaType(format),
+		FileName: baseFileName + "." + util.FormatToExtension(format),
+	}, nil
+}
+
+var fileNameForbiddenCharsRegex = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]`)
+
+func sanitizeFileName(name string) string {
+	return strings.TrimSpace(fileNameForbiddenCharsRegex.ReplaceAllString(name, "_"))
+}
+
+// transcoded streams are cached separately from the original ones
+func getStreamCacheKey(id uuid.UUID, options StreamOptions) string {
+	if options.Format == "" && options.MaxBitRate == 0 {
+		return fmt.Sprintf("tapesonic-%s", id)
+	}
+	return fmt.Sprintf("tapesonic-%s-%s-%d", id, util.Coalesce(options.Format, "any"), options.MaxBitRate)
+}
+
+func getSegmentCacheKey(id uuid.UUID, index int, maxBitRate int) string {
+	return fmt.Sprintf("tapesonic-%s-hls-%d-%d", id, maxBitRate, index)
+}
+
+func (svc *subsonicInternalService) GetLicense() (*responses.License, error) {
+	return responses.NewLicense(true), nil
+}
+
+func encodeId(id string) string {
+	return strings.ReplaceAll(fmt.Sprint(id), "-", "_")
+}
+
+func decodeId(rawId string) (uuid.UUID, error) {
+	return uuid.Parse(strings.ReplaceAll(rawId, "_", "-"))
+}
+
+func decodeTrackIds(rawIds []string) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, rawId := range rawIds {
+		id, err := decodeId(rawId)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// decodeIds validates the given raw IDs and returns them in the format they're stored in the database
+func decodeIds(rawIds []string) ([]string, error) {
+	ids := []string{}
+	for _, rawId := range rawIds {
+		id, err := decodeId(rawId)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id.String())
+	}
+
+	return ids, nil
 }
@@ -6,12 +6,15 @@ import (
 	"io"
 	"math"
 	"strings"
+	"tapesonic/config"
 	"tapesonic/http/subsonic/responses"
 	"tapesonic/storage"
 	"tapesonic/util"
 	"time"
 )
 
+var externalPlaylistIdPrefix = config.ExternalPlaylistSubsonicName + "_"
+
 type subsonicMainService struct {
 	delegate          SubsonicService
 	subsonicProviders []*SubsonicNamedService
@@ -41,10 +44,10 @@ func NewSubsonicMainService(
 	}
 }
 
-func (svc *subsonicMainService) Search3(query string, artistCount int, artistOffset int, albumCount int, albumOffset int, songCount int, songOffset int) (*responses.SearchResult3, error) {
+func (svc *subsonicMainService) Search3(username string, query string, artistCount int, artistOffset int, albumCount int, albumOffset int, songCount int, songOffset int) (*responses.SearchResult3, error) {
 	query = strings.TrimSpace(query)
 	if query == "" {
-		return svc.delegate.Search3(query, artistCount, artistOffset, albumCount, albumOffset, songCount, songOffset)
+		return svc.delegate.Search3(username, query, artistCount, artistOffset, albumCount, albumOffset, songCount, songOffset)
 	}
 
 	artistIds, err := svc.artistCache.Search(query, artistCount, artistOffset)
@@ -57,12 +60,13 @@ func (svc *subsonicMainService) Search3(query string, artistCount int, artistOff
 			return responses.ArtistId3{}, err
 		}
 
-		artist, err := subsonicProvider.GetArtist(item.Id)
+		artist, err := subsonicProvider.GetArtistByRawId(username, item.Id)
 		if err != nil {
 			return responses.ArtistId3{}, err
 		}
 
 		artistId3 := responses.NewArtistId3(artist.Id, artist.Name)
+		artistId3.CoverArt = artist.CoverArt
 		artistId3.AlbumCount = len(artist.Album)
 		artistId3.ArtistImageUrl = artist.ArtistImageUrl
 		artistId3.Starred = artist.Starred
@@ -83,7 +87,7 @@ func (svc *subsonicMainService) Search3(query string, artistCount int, artistOff
 			return responses.AlbumId3{}, err
 		}
 
-		album, err := subsonicProvider.GetAlbumByRawId(item.Id)
+		album, err := subsonicProvider.GetAlbumByRawId(username, item.Id)
 		if err != nil {
 			return responses.AlbumId3{}, err
 		}
@@ -104,7 +108,7 @@ func (svc *subsonicMainService) Search3(query string, artistCount int, artistOff
 			return responses.SubsonicChild{}, err
 		}
 
-		song, err := subsonicProvider.GetSongByRawId(item.Id)
+		song, err := subsonicProvider.GetSongByRawId(username, item.Id)
 		if err != nil {
 			return responses.SubsonicChild{}, err
 		}
@@ -118,25 +122,29 @@ func (svc *subsonicMainService) Search3(query string, artistCount int, artistOff
 	return responses.NewSearchResult3(artists, albums, songs), nil
 }
 
-func (svc *subsonicMainService) GetSong(id string) (*responses.SubsonicChild, error) {
-	return svc.delegate.GetSong(id)
+func (svc *subsonicMainService) GetSong(username string, id string) (*responses.SubsonicChild, error) {
+	return svc.delegate.GetSong(username, id)
+}
+
+func (svc *subsonicMainService) GetRandomSongs(username string, size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
+	return svc.delegate.GetRandomSongs(username, size, genre, fromYear, toYear)
 }
 
-func (svc *subsonicMainService) GetRandomSongs(size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
-	return svc.delegate.GetRandomSongs(size, genre, fromYear, toYear)
+func (svc *subsonicMainService) GetSongsByGenre(username string, genre string, count int, offset int) (*responses.SongsByGenre, error) {
+	return svc.delegate.GetSongsByGenre(username, genre, count, offset)
 }
 
-func (svc *subsonicMainService) GetAlbum(id string) (*responses.AlbumId3, error) {
-	return svc.delegate.GetAlbum(id)
+func (svc *subsonicMainService) GetAlbum(username string, id string) (*responses.AlbumId3, error) {
+	return svc.delegate.GetAlbum(username, id)
 }
 
-func (svc *subsonicMainService) GetAlbumList2(type_ string, size int, offset int, fromYear *int, toYear *int) (*responses.AlbumList2, error) {
-	return svc.delegate.GetAlbumList2(type_, size, offset, fromYear, toYear)
+func (svc *subsonicMainService) GetAlbumList2(username string, type_ string, size int, offset int, fromYear *int, toYear *int, genre string) (*responses.AlbumList2, error) {
+	return svc.delegate.GetAlbumList2(username, type_, size, offset, fromYear, toYear, genre)
 }
 
-func (svc *subsonicMainService) GetPlaylist(id string) (*responses.SubsonicPlaylist, error) {
-	if strings.HasPrefix(id, "external_") {
-		id = strings.TrimPrefix(id, "external_")
+func (svc *subsonicMainService) GetPlaylist(username string, id string) (*responses.SubsonicPlaylist, error) {
+	if strings.HasPrefix(id, externalPlaylistIdPrefix) {
+		id = strings.TrimPrefix(id, externalPlaylistIdPrefix)
 
 		playlist, err := svc.externalPlaylists.GetSubsonicPlaylist(id)
 		if err != nil {
@@ -147,7 +155,7 @@ func (svc *subsonicMainService) GetPlaylist(id string) (*responses.SubsonicPlayl
 			return nil, err
 		}
 
-		responsePlaylist := addPlaylistIdPrefix("external", playlist)
+		responsePlaylist := addPlaylistIdPrefix(config.ExternalPlaylistSubsonicName, playlist)
 
 		responsePlaylist.Entry, err = util.ParallelMap(trackIds, func(item storage.CachedSongIdWithIndex) (responses.SubsonicChild, error) {
 			subsonicProvider, err := svc.findServiceByName(item.ServiceName)
@@ -155,7 +163,7 @@ func (svc *subsonicMainService) GetPlaylist(id string) (*responses.SubsonicPlayl
 				return responses.SubsonicChild{}, err
 			}
 
-			song, err := subsonicProvider.GetSongByRawId(item.Id)
+			song, err := subsonicProvider.GetSongByRawId(username, item.Id)
 			if err != nil {
 				return responses.SubsonicChild{}, err
 			}
@@ -171,10 +179,10 @@ func (svc *subsonicMainService) GetPlaylist(id string) (*responses.SubsonicPlayl
 		return responsePlaylist, nil
 	}
 
-	return svc.delegate.GetPlaylist(id)
+	return svc.delegate.GetPlaylist(username, id)
 }
 
-func (svc *subsonicMainService) GetPlaylists() (*responses.SubsonicPlaylists, error) {
+func (svc *subsonicMainService) GetPlaylists(username string) (*responses.SubsonicPlaylists, error) {
 	allPlaylists := []responses.SubsonicPlaylist{}
 
 	externalPlaylists, err := svc.getExternalPlaylists()
@@ -183,7 +191,7 @@ func (svc *subsonicMainService) GetPlaylists() (*responses.SubsonicPlaylists, er
 	}
 	allPlaylists = append(allPlaylists, externalPlaylists.Playlist...)
 
-	libraryPlaylists, err := svc.delegate.GetPlaylists()
+	libraryPlaylists, err := svc.delegate.GetPlaylists(username)
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +200,30 @@ func (svc *subsonicMainService) GetPlaylists() (*responses.SubsonicPlaylists, er
 	return responses.NewSubsonicPlaylists(allPlaylists), nil
 }
 
+func (svc *subsonicMainService) CreatePlaylist(username string, playlistId string, name string, songIds []string) (*responses.SubsonicPlaylist, error) {
+	if strings.HasPrefix(playlistId, externalPlaylistIdPrefix) {
+		return nil, ErrReadOnlyPlaylist
+	}
+
+	return svc.delegate.CreatePlaylist(username, playlistId, name, songIds)
+}
+
+func (svc *subsonicMainService) UpdatePlaylist(username string, playlistId string, name string, songIdsToAdd []string, songIndexesToRemove []int) error {
+	if strings.HasPrefix(playlistId, externalPlaylistIdPrefix) {
+		return ErrReadOnlyPlaylist
+	}
+
+	return svc.delegate.UpdatePlaylist(username, playlistId, name, songIdsToAdd, songIndexesToRemove)
+}
+
+func (svc *subsonicMainService) DeletePlaylist(username string, playlistId string) error {
+	if strings.HasPrefix(playlistId, externalPlaylistIdPrefix) {
+		return ErrReadOnlyPlaylist
+	}
+
+	return svc.delegate.DeletePlaylist(username, playlistId)
+}
+
 func (svc *subsonicMainService) getExternalPlaylists() (*responses.SubsonicPlaylists, error) {
 	playlists, err := svc.externalPlaylists.GetSubsonicPlaylists(math.MaxInt32, 0)
 	if err != nil {
@@ -204,7 +236,7 @@ func (svc *subsonicMainService) getExternalPlaylists() (*responses.SubsonicPlayl
 			continue
 		}
 
-		resultPlaylists = append(resultPlaylists, *addPlaylistIdPrefix("external", playlist))
+		resultPlaylists = append(resultPlaylists, *addPlaylistIdPrefix(config.ExternalPlaylistSubsonicName, playlist))
 	}
 
 	return responses.NewSubsonicPlaylists(resultPlaylists), nil
@@ -225,20 +257,112 @@ func addPlaylistIdPrefix(prefix string, playlist storage.SubsonicPlaylistItem) *
 	return result
 }
 
-func (svc *subsonicMainService) GetArtist(id string) (*responses.Artist, error) {
-	return svc.delegate.GetArtist(id)
+func (svc *subsonicMainService) GetGenres() (*responses.Genres, error) {
+	return svc.delegate.GetGenres()
+}
+
+func (svc *subsonicMainService) GetArtists(username string) (*responses.Artists, error) {
+	return svc.delegate.GetArtists(username)
+}
+
+func (svc *subsonicMainService) GetArtist(username string, id string) (*responses.Artist, error) {
+	return svc.delegate.GetArtist(username, id)
+}
+
+func (svc *subsonicMainService) Scrobble(username string, id string, time_ time.Time, submission bool) error {
+	return svc.delegate.Scrobble(username, id, time_, submission)
+}
+
+func (svc *subsonicMainService) Star(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.delegate.Star(username, ids, albumIds, artistIds)
+}
+
+func (svc *subsonicMainService) Unstar(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.delegate.Unstar(username, ids, albumIds, artistIds)
+}
+
+func (svc *subsonicMainService) SetRating(username string, id string, rating int) error {
+	return svc.delegate.SetRating(username, id, rating)
+}
+
+func (svc *subsonicMainService) CreateBookmark(username string, id string, positionMs int64, comment string) error {
+	return svc.delegate.CreateBookmark(username, id, positionMs, comment)
+}
+
+func (svc *subsonicMainService) GetBookmarks(username string) (*responses.Bookmarks, error) {
+	return svc.delegate.GetBookmarks(username)
+}
+
+func (svc *subsonicMainService) DeleteBookmark(username string, id string) error {
+	return svc.delegate.DeleteBookmark(username, id)
+}
+
+func (svc *subsonicMainService) GetStarred2(username string) (*responses.Starred2, error) {
+	return svc.delegate.GetStarred2(username)
+}
+
+func (svc *subsonicMainService) GetPodcasts(id string, includeEpisodes bool) (*responses.Podcasts, error) {
+	return svc.delegate.GetPodcasts(id, includeEpisodes)
+}
+
+func (svc *subsonicMainService) GetNewestPodcasts(count int) (*responses.NewestPodcasts, error) {
+	return svc.delegate.GetNewestPodcasts(count)
+}
+
+func (svc *subsonicMainService) CreatePodcastChannel(url string) error {
+	return svc.delegate.CreatePodcastChannel(url)
+}
+
+func (svc *subsonicMainService) DeletePodcastChannel(id string) error {
+	return svc.delegate.DeletePodcastChannel(id)
+}
+
+func (svc *subsonicMainService) RefreshPodcasts() error {
+	return svc.delegate.RefreshPodcasts()
+}
+
+func (svc *subsonicMainService) DownloadPodcastEpisode(id string) error {
+	return svc.delegate.DownloadPodcastEpisode(id)
+}
+
+func (svc *subsonicMainService) GetLyrics(artist string, title string) (*responses.Lyrics, error) {
+	return svc.delegate.GetLyrics(artist, title)
+}
+
+func (svc *subsonicMainService) GetLyricsBySongId(id string) (*responses.LyricsList, error) {
+	return svc.delegate.GetLyricsBySongId(id)
+}
+
+func (svc *subsonicMainService) GetMusicFolders() (*responses.MusicFolders, error) {
+	return svc.delegate.GetMusicFolders()
+}
+
+func (svc *subsonicMainService) GetIndexes(username string, musicFolderId string) (*responses.Indexes, error) {
+	return svc.delegate.GetIndexes(username, musicFolderId)
+}
+
+func (svc *subsonicMainService) GetMusicDirectory(username string, id string) (*responses.Directory, error) {
+	return svc.delegate.GetMusicDirectory(username, id)
+}
+
+func (svc *subsonicMainService) GetCoverArt(id string, size int) (mime string, reader io.ReadCloser, err error) {
+	return svc.delegate.GetCoverArt(id, size)
+}
+
+func (svc *subsonicMainService) Stream(ctx context.Context, id string, options StreamOptions) (AudioStream, error) {
+	return svc.delegate.Stream(ctx, id, options)
 }
 
-func (svc *subsonicMainService) Scrobble(id string, time_ time.Time, submission bool) error {
-	return svc.delegate.Scrobble(id, time_, submission)
+func (svc *subsonicMainService) Download(ctx context.Context, id string) (AudioStream, error) {
+	return svc.delegate.Download(ctx, id)
 }
 
-func (svc *subsonicMainService) GetCoverArt(id string) (mime string, reader io.ReadCloser, err error) {
-	return svc.delegate.GetCoverArt(id)
+func (svc *subsonicMainService) GetStreamSegments(id string) ([]int64, error) {
+	return svc.delegate.GetStreamSegments(id)
 }
 
-func (svc *subsonicMainService) Stream(ctx context.Context, id string) (AudioStream, error) {
-	return svc.delegate.Stream(ctx, id)
+func (svc *subsonicMainService) StreamSegment(ctx context.Context, id string, index int, maxBitRate int) (AudioStream, error) {
+	return svc.delegate.StreamSegment(ctx, id, index, maxBitRate)
 }
 
 func (svc *subsonicMainService) GetLicense() (*responses.License, error) {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math/rand"
 	"slices"
 	"sort"
@@ -23,29 +24,37 @@ type SubsonicMuxService struct {
 	services []*SubsonicNamedService
 
 	muxedSongListens *storage.MuxedSongListensStorage
+	muxedRatings     *storage.MuxedRatingStorage
 	songCache        *SongCacheService
 
 	scrobbler *ScrobbleService
+	// names of services whose songs are scrobbled with the scrobbler
+	scrobbledServices map[string]bool
 }
 
 func NewSubsonicMuxService(
 	muxedSongListens *storage.MuxedSongListensStorage,
+	muxedRatings *storage.MuxedRatingStorage,
 	songCache *SongCacheService,
 	scrobbler *ScrobbleService,
 ) *SubsonicMuxService {
 	return &SubsonicMuxService{
-		services:         []*SubsonicNamedService{},
-		muxedSongListens: muxedSongListens,
-		songCache:        songCache,
-		scrobbler:        scrobbler,
+		services:          []*SubsonicNamedService{},
+		muxedSongListens:  muxedSongListens,
+		muxedRatings:      muxedRatings,
+		songCache:         songCache,
+		scrobbler:         scrobbler,
+		scrobbledServices: map[string]bool{},
 	}
 }
 
-func (svc *SubsonicMuxService) AddService(service *SubsonicNamedService) {
+func (svc *SubsonicMuxService) AddService(service *SubsonicNamedService, scrobble bool) {
 	svc.services = append(svc.services, service)
+	svc.scrobbledServices[service.Name()] = scrobble
 }
 
 func (svc *SubsonicMuxService) Search3(
+	username string,
 	query string,
 	artistCount int,
 	artistOffset int,
@@ -56,7 +65,7 @@ func (svc *SubsonicMuxService) Search3(
 ) (*responses.SearchResult3, error) {
 	if len(svc.services) == 1 {
 		for _, service := range svc.services {
-			return service.Search3(query, artistCount, artistOffset, albumCount, albumOffset, songCount, songOffset)
+			return service.Search3(username, query, artistCount, artistOffset, albumCount, albumOffset, songCount, songOffset)
 		}
 	}
 
@@ -70,7 +79,7 @@ func (svc *SubsonicMuxService) Search3(
 		serviceAlbumOffset := 0
 		serviceSongOffset := 0
 		for {
-			searchResult, err := service.Search3(query, fetchSize, serviceArtistOffset, fetchSize, serviceAlbumOffset, fetchSize, serviceSongOffset)
+			searchResult, err := service.Search3(username, query, fetchSize, serviceArtistOffset, fetchSize, serviceAlbumOffset, fetchSize, serviceSongOffset)
 			if err != nil {
 				return nil, err
 			}
@@ -101,21 +110,21 @@ func (svc *SubsonicMuxService) Search3(
 	}, nil
 }
 
-func (svc *SubsonicMuxService) GetSong(id string) (*responses.SubsonicChild, error) {
+func (svc *SubsonicMuxService) GetSong(username string, id string) (*responses.SubsonicChild, error) {
 	service, err := svc.findServiceByEntityId(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return service.GetSong(id)
+	return service.GetSong(username, id)
 }
 
-func (svc *SubsonicMuxService) GetRandomSongs(size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
+func (svc *SubsonicMuxService) GetRandomSongs(username string, size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
 	songs := []responses.SubsonicChild{}
 	for _, service := range svc.services {
 		// todo: a pretty bad implementation, but it makes at least a somewhat more balanced result
 		// when different services have a different count of total songs than just getting `size` songs from each one
-		more, err := service.GetRandomSongs(fetchSize, genre, fromYear, toYear)
+		more, err := service.GetRandomSongs(username, fetchSize, genre, fromYear, toYear)
 		if err != nil {
 			return nil, err
 		}
@@ -130,35 +139,83 @@ func (svc *SubsonicMuxService) GetRandomSongs(size int, genre string, fromYear *
 	return responses.NewRandomSongs(songs), nil
 }
 
-func (svc *SubsonicMuxService) GetAlbum(id string) (*responses.AlbumId3, error) {
+func (svc *SubsonicMuxService) GetSongsByGenre(username string, genre string, count int, offset int) (*responses.SongsByGenre, error) {
+	if len(svc.services) == 1 {
+		return svc.services[0].GetSongsByGenre(username, genre, count, offset)
+	}
+
+	songs := []responses.SubsonicChild{}
+	for _, service := range svc.services {
+		// same as in getAlbumList2 - fetching everything to keep the pagination stable between services
+		serviceOffset := 0
+		for {
+			more, err := service.GetSongsByGenre(username, genre, fetchSize, serviceOffset)
+			if err != nil {
+				return nil, err
+			}
+
+			songs = append(songs, more.Song...)
+
+			if len(more.Song) < fetchSize {
+				break
+			} else {
+				serviceOffset += len(more.Song)
+			}
+		}
+	}
+
+	sort.SliceStable(songs, func(i, j int) bool {
+		leftArtist, rightArtist := strings.ToLower(songs[i].Artist), strings.ToLower(songs[j].Artist)
+		if leftArtist != rightArtist {
+			return leftArtist < rightArtist
+		}
+
+		leftAlbum, rightAlbum := strings.ToLower(songs[i].Album), strings.ToLower(songs[j].Album)
+		if leftAlbum != rightAlbum {
+			return leftAlbum < rightAlbum
+		}
+
+		return songs[i].Track < songs[j].Track
+	})
+
+	songs = songs[min(offset, len(songs)):min(offset+count, len(songs))]
+
+	return responses.NewSongsByGenre(songs), nil
+}
+
+func (svc *SubsonicMuxService) GetAlbum(username string, id string) (*responses.AlbumId3, error) {
 	service, err := svc.findServiceByEntityId(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return service.GetAlbum(id)
+	return service.GetAlbum(username, id)
 }
 
 func (svc *SubsonicMuxService) GetAlbumList2(
+	username string,
 	type_ string,
 	size int,
 	offset int,
 	fromYear *int,
 	toYear *int,
+	genre string,
 ) (*responses.AlbumList2, error) {
 	if len(svc.services) == 1 {
 		for _, service := range svc.services {
-			return service.GetAlbumList2(type_, size, offset, fromYear, toYear)
+			return service.GetAlbumList2(username, type_, size, offset, fromYear, toYear, genre)
 		}
 	}
 
-	if type_ == LIST_RECENT || type_ == LIST_FREQUENT {
+	if type_ == LIST_RECENT || type_ == LIST_FREQUENT || type_ == LIST_HIGHEST {
 		var albumListenStats []storage.CachedAlbumId
 		var err error
 		if type_ == LIST_RECENT {
-			albumListenStats, err = svc.muxedSongListens.GetRecentAlbumListenStats(size, offset)
+			albumListenStats, err = svc.muxedSongListens.GetRecentAlbumListenStats(username, size, offset)
+		} else if type_ == LIST_FREQUENT {
+			albumListenStats, err = svc.muxedSongListens.GetFrequentAlbumListenStats(username, size, offset)
 		} else {
-			albumListenStats, err = svc.muxedSongListens.GetFrequentAlbumListenStats(size, offset)
+			albumListenStats, err = svc.muxedRatings.GetHighestRatedAlbums(username, size, offset)
 		}
 		if err != nil {
 			return nil, err
@@ -170,7 +227,7 @@ func (svc *SubsonicMuxService) GetAlbumList2(
 				return responses.AlbumId3{}, err
 			}
 
-			album, err := service.GetAlbumByRawId(item.Id)
+			album, err := service.GetAlbumByRawId(username, item.Id)
 			if err != nil {
 				return responses.AlbumId3{}, err
 			}
@@ -193,7 +250,7 @@ func (svc *SubsonicMuxService) GetAlbumList2(
 		// will be solved properly later by just caching the complete album list in the database
 		serviceOffset := 0
 		for {
-			more, err := service.GetAlbumList2(type_, fetchSize, serviceOffset, fromYear, toYear)
+			more, err := service.GetAlbumList2(username, type_, fetchSize, serviceOffset, fromYear, toYear, genre)
 			if err != nil {
 				return nil, err
 			}
@@ -215,7 +272,7 @@ func (svc *SubsonicMuxService) GetAlbumList2(
 		sort.Slice(albums, func(i, j int) bool {
 			return albums[i].Created.After(albums[j].Created)
 		})
-	case LIST_BY_NAME:
+	case LIST_BY_NAME, LIST_BY_GENRE:
 		sort.Slice(albums, func(i, j int) bool {
 			return strings.ToLower(albums[i].Name) < strings.ToLower(albums[j].Name)
 		})
@@ -278,20 +335,20 @@ func (svc *SubsonicMuxService) GetAlbumList2(
 	return responses.NewAlbumList2(albums), nil
 }
 
-func (svc *SubsonicMuxService) GetPlaylist(id string) (*responses.SubsonicPlaylist, error) {
+func (svc *SubsonicMuxService) GetPlaylist(username string, id string) (*responses.SubsonicPlaylist, error) {
 	service, err := svc.findServiceByEntityId(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return service.GetPlaylist(id)
+	return service.GetPlaylist(username, id)
 }
 
-func (svc *SubsonicMuxService) GetPlaylists() (*responses.SubsonicPlaylists, error) {
+func (svc *SubsonicMuxService) GetPlaylists(username string) (*responses.SubsonicPlaylists, error) {
 	playlists := []responses.SubsonicPlaylist{}
 
 	for _, service := range svc.services {
-		servicePlaylists, err := service.GetPlaylists()
+		servicePlaylists, err := service.GetPlaylists(username)
 		if err != nil {
 			return nil, err
 		}
@@ -302,30 +359,154 @@ func (svc *SubsonicMuxService) GetPlaylists() (*responses.SubsonicPlaylists, err
 	return responses.NewSubsonicPlaylists(playlists), nil
 }
 
-func (svc *SubsonicMuxService) GetArtist(id string) (*responses.Artist, error) {
+func (svc *SubsonicMuxService) CreatePlaylist(username string, playlistId string, name string, songIds []string) (*responses.SubsonicPlaylist, error) {
+	service, err := svc.findServiceForPlaylist(playlistId, songIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.CreatePlaylist(username, playlistId, name, songIds)
+}
+
+func (svc *SubsonicMuxService) UpdatePlaylist(username string, playlistId string, name string, songIdsToAdd []string, songIndexesToRemove []int) error {
+	service, err := svc.findServiceForPlaylist(playlistId, songIdsToAdd)
+	if err != nil {
+		return err
+	}
+
+	return service.UpdatePlaylist(username, playlistId, name, songIdsToAdd, songIndexesToRemove)
+}
+
+func (svc *SubsonicMuxService) DeletePlaylist(username string, playlistId string) error {
+	service, err := svc.findServiceByEntityId(playlistId)
+	if err != nil {
+		return err
+	}
+
+	return service.DeletePlaylist(username, playlistId)
+}
+
+// playlists are stored by the server owning their songs, so they can't mix songs from different servers;
+// new empty playlists go to the first (primary) server
+func (svc *SubsonicMuxService) findServiceForPlaylist(playlistId string, songIds []string) (*SubsonicNamedService, error) {
+	idsByService, err := svc.groupIdsByService(songIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var service *SubsonicNamedService
+	if playlistId != "" {
+		service, err = svc.findServiceByEntityId(playlistId)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(idsByService) > 0 {
+		for serviceName := range idsByService {
+			service, err = svc.findServiceByName(serviceName)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if len(svc.services) > 0 {
+		service = svc.services[0]
+	} else {
+		return nil, fmt.Errorf("no subsonic services available")
+	}
+
+	for serviceName := range idsByService {
+		if serviceName != service.Name() {
+			return nil, fmt.Errorf("playlist owned by `%s` can't contain songs from `%s`", service.Name(), serviceName)
+		}
+	}
+
+	return service, nil
+}
+
+func (svc *SubsonicMuxService) GetGenres() (*responses.Genres, error) {
+	if len(svc.services) == 1 {
+		for _, service := range svc.services {
+			return service.GetGenres()
+		}
+	}
+
+	genres := []responses.Genre{}
+	genreIndexes := map[string]int{}
+	for _, service := range svc.services {
+		serviceGenres, err := service.GetGenres()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, genre := range serviceGenres.Genre {
+			key := strings.ToLower(genre.Value)
+			if index, ok := genreIndexes[key]; ok {
+				genres[index].SongCount += genre.SongCount
+				genres[index].AlbumCount += genre.AlbumCount
+			} else {
+				genreIndexes[key] = len(genres)
+				genres = append(genres, genre)
+			}
+		}
+	}
+
+	sort.Slice(genres, func(i, j int) bool {
+		return strings.ToLower(genres[i].Value) < strings.ToLower(genres[j].Value)
+	})
+
+	return responses.NewGenres(genres), nil
+}
+
+func (svc *SubsonicMuxService) GetArtists(username string) (*responses.Artists, error) {
+	if len(svc.services) == 1 {
+		for _, service := range svc.services {
+			return service.GetArtists(username)
+		}
+	}
+
+	ignoredArticles := ""
+	artists := []responses.ArtistId3{}
+	for _, service := range svc.services {
+		serviceArtists, err := service.GetArtists(username)
+		if err != nil {
+			return nil, err
+		}
+
+		if ignoredArticles == "" {
+			ignoredArticles = serviceArtists.IgnoredArticles
+		}
+
+		for _, index := range serviceArtists.Index {
+			artists = append(artists, index.Artist...)
+		}
+	}
+
+	return responses.NewArtists(ignoredArticles, NewArtistIndex(artists, ignoredArticles)), nil
+}
+
+func (svc *SubsonicMuxService) GetArtist(username string, id string) (*responses.Artist, error) {
 	service, err := svc.findServiceByEntityId(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return service.GetArtist(id)
+	return service.GetArtist(username, id)
 }
 
-func (svc *SubsonicMuxService) Scrobble(id string, time_ time.Time, submission bool) error {
+func (svc *SubsonicMuxService) Scrobble(username string, id string, time_ time.Time, submission bool) error {
 	service, err := svc.findServiceByEntityId(id)
 	if err != nil {
 		return err
 	}
 
-	selfErr := svc.muxedSongListens.Record(service.Name(), service.RemovePrefix(id), time_, submission)
-	serviceErr := service.Scrobble(id, time_, submission)
-	scrobblerErr := svc.scrobbleWithScrobbler(service.Name(), service.RemovePrefix(id), time_, submission)
+	selfErr := svc.muxedSongListens.Record(username, service.Name(), service.RemovePrefix(id), time_, submission)
+	serviceErr := service.Scrobble(username, id, time_, submission)
+	scrobblerErr := svc.scrobbleWithScrobbler(username, service.Name(), service.RemovePrefix(id), time_, submission)
 
 	return errors.Join(selfErr, serviceErr, scrobblerErr)
 }
 
-func (svc *SubsonicMuxService) scrobbleWithScrobbler(serviceName string, id string, time_ time.Time, submission bool) error {
-	if svc.scrobbler == nil {
+func (svc *SubsonicMuxService) scrobbleWithScrobbler(username string, serviceName string, id string, time_ time.Time, submission bool) error {
+	if svc.scrobbler == nil || !svc.scrobbledServices[serviceName] {
 		return nil
 	}
 
@@ -335,28 +516,386 @@ func (svc *SubsonicMuxService) scrobbleWithScrobbler(serviceName string, id stri
 	}
 
 	if submission {
-		return svc.scrobbler.ScrobbleCompleted(time_, song.Artist, song.Album, song.Title)
+		return svc.scrobbler.ScrobbleCompleted(username, time_, song.Artist, song.Album, song.Title)
 	} else {
-		return svc.scrobbler.ScrobblePlaying(song.Artist, song.Album, song.Title)
+		return svc.scrobbler.ScrobblePlaying(username, song.Artist, song.Album, song.Title)
+	}
+}
+
+func (svc *SubsonicMuxService) Star(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.forEachServiceWithIds(ids, albumIds, artistIds, func(service *SubsonicNamedService, ids []string, albumIds []string, artistIds []string) error {
+		return service.Star(username, ids, albumIds, artistIds)
+	})
+}
+
+func (svc *SubsonicMuxService) Unstar(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.forEachServiceWithIds(ids, albumIds, artistIds, func(service *SubsonicNamedService, ids []string, albumIds []string, artistIds []string) error {
+		return service.Unstar(username, ids, albumIds, artistIds)
+	})
+}
+
+func (svc *SubsonicMuxService) forEachServiceWithIds(
+	ids []string,
+	albumIds []string,
+	artistIds []string,
+	action func(service *SubsonicNamedService, ids []string, albumIds []string, artistIds []string) error,
+) error {
+	idsByService, err := svc.groupIdsByService(ids)
+	if err != nil {
+		return err
+	}
+	albumIdsByService, err := svc.groupIdsByService(albumIds)
+	if err != nil {
+		return err
+	}
+	artistIdsByService, err := svc.groupIdsByService(artistIds)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, service := range svc.services {
+		serviceIds := idsByService[service.Name()]
+		serviceAlbumIds := albumIdsByService[service.Name()]
+		serviceArtistIds := artistIdsByService[service.Name()]
+
+		if len(serviceIds) == 0 && len(serviceAlbumIds) == 0 && len(serviceArtistIds) == 0 {
+			continue
+		}
+
+		errs = append(errs, action(service, serviceIds, serviceAlbumIds, serviceArtistIds))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (svc *SubsonicMuxService) groupIdsByService(ids []string) (map[string][]string, error) {
+	result := map[string][]string{}
+	for _, id := range ids {
+		service, err := svc.findServiceByEntityId(id)
+		if err != nil {
+			return nil, err
+		}
+
+		result[service.Name()] = append(result[service.Name()], id)
+	}
+
+	return result, nil
+}
+
+func (svc *SubsonicMuxService) SetRating(username string, id string, rating int) error {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return err
+	}
+
+	selfErr := svc.muxedRatings.Record(username, service.Name(), service.RemovePrefix(id), rating, time.Now())
+	serviceErr := service.SetRating(username, id, rating)
+
+	return errors.Join(selfErr, serviceErr)
+}
+
+func (svc *SubsonicMuxService) CreateBookmark(username string, id string, positionMs int64, comment string) error {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return err
+	}
+
+	return service.CreateBookmark(username, id, positionMs, comment)
+}
+
+func (svc *SubsonicMuxService) GetBookmarks(username string) (*responses.Bookmarks, error) {
+	if len(svc.services) == 1 {
+		for _, service := range svc.services {
+			return service.GetBookmarks(username)
+		}
+	}
+
+	bookmarks := []responses.Bookmark{}
+	for _, service := range svc.services {
+		more, err := service.GetBookmarks(username)
+		if err != nil {
+			return nil, err
+		}
+
+		bookmarks = append(bookmarks, more.Bookmark...)
+	}
+
+	slices.SortStableFunc(bookmarks, func(a responses.Bookmark, b responses.Bookmark) int {
+		return b.Changed.Compare(a.Changed)
+	})
+
+	return responses.NewBookmarks(bookmarks), nil
+}
+
+func (svc *SubsonicMuxService) DeleteBookmark(username string, id string) error {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return err
+	}
+
+	return service.DeleteBookmark(username, id)
+}
+
+func (svc *SubsonicMuxService) GetStarred2(username string) (*responses.Starred2, error) {
+	if len(svc.services) == 1 {
+		for _, service := range svc.services {
+			return service.GetStarred2(username)
+		}
+	}
+
+	artists := []responses.ArtistId3{}
+	albums := []responses.AlbumId3{}
+	songs := []responses.SubsonicChild{}
+
+	for _, service := range svc.services {
+		starred, err := service.GetStarred2(username)
+		if err != nil {
+			return nil, err
+		}
+
+		artists = append(artists, starred.Artist...)
+		albums = append(albums, starred.Album...)
+		songs = append(songs, starred.Song...)
+	}
+
+	return responses.NewStarred2(artists, albums, songs), nil
+}
+
+func (svc *SubsonicMuxService) GetPodcasts(id string, includeEpisodes bool) (*responses.Podcasts, error) {
+	if id != "" {
+		service, err := svc.findServiceByEntityId(id)
+		if err != nil {
+			return nil, err
+		}
+
+		return service.GetPodcasts(id, includeEpisodes)
+	}
+
+	channels := []responses.PodcastChannel{}
+	for _, service := range svc.services {
+		podcasts, err := service.GetPodcasts(id, includeEpisodes)
+		if err != nil {
+			return nil, err
+		}
+
+		channels = append(channels, podcasts.Channel...)
+	}
+
+	return responses.NewPodcasts(channels), nil
+}
+
+func (svc *SubsonicMuxService) GetNewestPodcasts(count int) (*responses.NewestPodcasts, error) {
+	if len(svc.services) == 1 {
+		for _, service := range svc.services {
+			return service.GetNewestPodcasts(count)
+		}
+	}
+
+	episodes := []responses.PodcastEpisode{}
+	for _, service := range svc.services {
+		podcasts, err := service.GetNewestPodcasts(count)
+		if err != nil {
+			return nil, err
+		}
+
+		episodes = append(episodes, podcasts.Episode...)
+	}
+
+	sort.SliceStable(episodes, func(i, j int) bool {
+		if episodes[i].PublishDate == nil || episodes[j].PublishDate == nil {
+			return episodes[j].PublishDate == nil && episodes[i].PublishDate != nil
+		}
+		return episodes[i].PublishDate.After(*episodes[j].PublishDate)
+	})
+
+	return responses.NewNewestPodcasts(episodes[:min(count, len(episodes))]), nil
+}
+
+// new channels always go to the first (primary) server
+func (svc *SubsonicMuxService) CreatePodcastChannel(url string) error {
+	if len(svc.services) == 0 {
+		return fmt.Errorf("no subsonic services available")
+	}
+
+	return svc.services[0].CreatePodcastChannel(url)
+}
+
+func (svc *SubsonicMuxService) DeletePodcastChannel(id string) error {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return err
+	}
+
+	return service.DeletePodcastChannel(id)
+}
+
+func (svc *SubsonicMuxService) RefreshPodcasts() error {
+	errs := []error{}
+	for _, service := range svc.services {
+		errs = append(errs, service.RefreshPodcasts())
+	}
+
+	return errors.Join(errs...)
+}
+
+func (svc *SubsonicMuxService) DownloadPodcastEpisode(id string) error {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return err
 	}
+
+	return service.DownloadPodcastEpisode(id)
+}
+
+// lyrics are looked up by artist and title, so the first service which knows the song wins
+func (svc *SubsonicMuxService) GetLyrics(artist string, title string) (*responses.Lyrics, error) {
+	errs := []error{}
+	for _, service := range svc.services {
+		lyrics, err := service.GetLyrics(artist, title)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if lyrics != nil && lyrics.Value != "" {
+			return lyrics, nil
+		}
+	}
+
+	if len(errs) == len(svc.services) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return responses.NewLyrics("", "", ""), nil
 }
 
-func (svc *SubsonicMuxService) GetCoverArt(id string) (mime string, reader io.ReadCloser, err error) {
+func (svc *SubsonicMuxService) GetLyricsBySongId(id string) (*responses.LyricsList, error) {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.GetLyricsBySongId(id)
+}
+
+// an unavailable service is skipped so that the others can still be browsed
+func (svc *SubsonicMuxService) GetMusicFolders() (*responses.MusicFolders, error) {
+	errs := []error{}
+	musicFolders := []responses.MusicFolder{}
+	for _, service := range svc.services {
+		serviceMusicFolders, err := service.GetMusicFolders()
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to get music folders of subsonic `%s`, skipping: %s", service.Name(), err))
+			errs = append(errs, err)
+			continue
+		}
+
+		musicFolders = append(musicFolders, serviceMusicFolders.MusicFolder...)
+	}
+
+	if len(errs) == len(svc.services) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return responses.NewMusicFolders(musicFolders), nil
+}
+
+func (svc *SubsonicMuxService) GetIndexes(username string, musicFolderId string) (*responses.Indexes, error) {
+	if musicFolderId != "" {
+		service, err := svc.findServiceByEntityId(musicFolderId)
+		if err != nil {
+			return nil, err
+		}
+
+		return service.GetIndexes(username, musicFolderId)
+	}
+
+	if len(svc.services) == 1 {
+		return svc.services[0].GetIndexes(username, musicFolderId)
+	}
+
+	errs := []error{}
+	lastModified := int64(0)
+	ignoredArticles := ""
+	directories := []responses.ArtistId3{}
+	for _, service := range svc.services {
+		serviceIndexes, err := service.GetIndexes(username, musicFolderId)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to get indexes of subsonic `%s`, skipping: %s", service.Name(), err))
+			errs = append(errs, err)
+			continue
+		}
+
+		lastModified = max(lastModified, serviceIndexes.LastModified)
+		if ignoredArticles == "" {
+			ignoredArticles = serviceIndexes.IgnoredArticles
+		}
+
+		for _, index := range serviceIndexes.Index {
+			directories = append(directories, index.Artist...)
+		}
+	}
+
+	if len(errs) == len(svc.services) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return responses.NewIndexes(lastModified, ignoredArticles, NewArtistIndex(directories, ignoredArticles)), nil
+}
+
+func (svc *SubsonicMuxService) GetMusicDirectory(username string, id string) (*responses.Directory, error) {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.GetMusicDirectory(username, id)
+}
+
+func (svc *SubsonicMuxService) GetCoverArt(id string, size int) (mime string, reader io.ReadCloser, err error) {
 	service, err := svc.findServiceByEntityId(id)
 	if err != nil {
 		return
 	}
 
-	return service.GetCoverArt(id)
+	return service.GetCoverArt(id, size)
+}
+
+func (svc *SubsonicMuxService) Stream(ctx context.Context, id string, options StreamOptions) (AudioStream, error) {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	return service.Stream(ctx, id, options)
+}
+
+func (svc *SubsonicMuxService) Download(ctx context.Context, id string) (AudioStream, error) {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	return service.Download(ctx, id)
+}
+
+func (svc *SubsonicMuxService) GetStreamSegments(id string) ([]int64, error) {
+	service, err := svc.findServiceByEntityId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.GetStreamSegments(id)
 }
 
-func (svc *SubsonicMuxService) Stream(ctx context.Context, id string) (AudioStream, error) {
+func (svc *SubsonicMuxService) StreamSegment(ctx context.Context, id string, index int, maxBitRate int) (AudioStream, error) {
 	service, err := svc.findServiceByEntityId(id)
 	if err != nil {
 		return AudioStream{}, err
 	}
 
-	return service.Stream(ctx, id)
+	return service.StreamSegment(ctx, id, index, maxBitRate)
 }
 
 func (svc *SubsonicMuxService) GetLicense() (*responses.License, error) {
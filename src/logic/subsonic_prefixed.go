@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"tapesonic/http/subsonic/responses"
 	"time"
@@ -21,8 +22,8 @@ func NewSubsonicNamedService(name string, delegate SubsonicService) *SubsonicNam
 	}
 }
 
-func (svc *SubsonicNamedService) Search3(query string, artistCount int, artistOffset int, albumCount int, albumOffset int, songCount int, songOffset int) (*responses.SearchResult3, error) {
-	search, err := svc.delegate.Search3(query, artistCount, artistOffset, albumCount, albumOffset, songCount, songOffset)
+func (svc *SubsonicNamedService) Search3(username string, query string, artistCount int, artistOffset int, albumCount int, albumOffset int, songCount int, songOffset int) (*responses.SearchResult3, error) {
+	search, err := svc.delegate.Search3(username, query, artistCount, artistOffset, albumCount, albumOffset, songCount, songOffset)
 	if err != nil {
 		return nil, err
 	}
@@ -40,12 +41,12 @@ func (svc *SubsonicNamedService) Search3(query string, artistCount int, artistOf
 	return search, nil
 }
 
-func (svc *SubsonicNamedService) GetSong(id string) (*responses.SubsonicChild, error) {
-	return svc.GetSongByRawId(svc.RemovePrefix(id))
+func (svc *SubsonicNamedService) GetSong(username string, id string) (*responses.SubsonicChild, error) {
+	return svc.GetSongByRawId(username, svc.RemovePrefix(id))
 }
 
-func (svc *SubsonicNamedService) GetSongByRawId(id string) (*responses.SubsonicChild, error) {
-	song, err := svc.delegate.GetSong(id)
+func (svc *SubsonicNamedService) GetSongByRawId(username string, id string) (*responses.SubsonicChild, error) {
+	song, err := svc.delegate.GetSong(username, id)
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +55,8 @@ func (svc *SubsonicNamedService) GetSongByRawId(id string) (*responses.SubsonicC
 	return &rewrittenSong, nil
 }
 
-func (svc *SubsonicNamedService) GetRandomSongs(size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
-	songs, err := svc.delegate.GetRandomSongs(size, genre, fromYear, toYear)
+func (svc *SubsonicNamedService) GetRandomSongs(username string, size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
+	songs, err := svc.delegate.GetRandomSongs(username, size, genre, fromYear, toYear)
 	if err != nil {
 		return nil, err
 	}
@@ -67,12 +68,25 @@ func (svc *SubsonicNamedService) GetRandomSongs(size int, genre string, fromYear
 	return songs, nil
 }
 
-func (svc *SubsonicNamedService) GetAlbum(id string) (*responses.AlbumId3, error) {
-	return svc.GetAlbumByRawId(svc.RemovePrefix(id))
+func (svc *SubsonicNamedService) GetSongsByGenre(username string, genre string, count int, offset int) (*responses.SongsByGenre, error) {
+	songs, err := svc.delegate.GetSongsByGenre(username, genre, count, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range songs.Song {
+		songs.Song[i] = svc.rewriteSongInfo(songs.Song[i])
+	}
+
+	return songs, nil
 }
 
-func (svc *SubsonicNamedService) GetAlbumByRawId(id string) (*responses.AlbumId3, error) {
-	album, err := svc.delegate.GetAlbum(id)
+func (svc *SubsonicNamedService) GetAlbum(username string, id string) (*responses.AlbumId3, error) {
+	return svc.GetAlbumByRawId(username, svc.RemovePrefix(id))
+}
+
+func (svc *SubsonicNamedService) GetAlbumByRawId(username string, id string) (*responses.AlbumId3, error) {
+	album, err := svc.delegate.GetAlbum(username, id)
 	if err != nil {
 		return nil, err
 	}
@@ -81,8 +95,8 @@ func (svc *SubsonicNamedService) GetAlbumByRawId(id string) (*responses.AlbumId3
 	return &rewrittenAlbum, nil
 }
 
-func (svc *SubsonicNamedService) GetAlbumList2(type_ string, size int, offset int, fromYear *int, toYear *int) (*responses.AlbumList2, error) {
-	albumList, err := svc.delegate.GetAlbumList2(type_, size, offset, fromYear, toYear)
+func (svc *SubsonicNamedService) GetAlbumList2(username string, type_ string, size int, offset int, fromYear *int, toYear *int, genre string) (*responses.AlbumList2, error) {
+	albumList, err := svc.delegate.GetAlbumList2(username, type_, size, offset, fromYear, toYear, genre)
 	if err != nil {
 		return nil, err
 	}
@@ -94,12 +108,12 @@ func (svc *SubsonicNamedService) GetAlbumList2(type_ string, size int, offset in
 	return albumList, nil
 }
 
-func (svc *SubsonicNamedService) GetPlaylist(id string) (*responses.SubsonicPlaylist, error) {
-	return svc.GetPlaylistByRawId(svc.RemovePrefix(id))
+func (svc *SubsonicNamedService) GetPlaylist(username string, id string) (*responses.SubsonicPlaylist, error) {
+	return svc.GetPlaylistByRawId(username, svc.RemovePrefix(id))
 }
 
-func (svc *SubsonicNamedService) GetPlaylistByRawId(id string) (*responses.SubsonicPlaylist, error) {
-	playlist, err := svc.delegate.GetPlaylist(id)
+func (svc *SubsonicNamedService) GetPlaylistByRawId(username string, id string) (*responses.SubsonicPlaylist, error) {
+	playlist, err := svc.delegate.GetPlaylist(username, id)
 	if err != nil {
 		return nil, err
 	}
@@ -108,8 +122,8 @@ func (svc *SubsonicNamedService) GetPlaylistByRawId(id string) (*responses.Subso
 	return &rewrittenPlaylist, nil
 }
 
-func (svc *SubsonicNamedService) GetPlaylists() (*responses.SubsonicPlaylists, error) {
-	playlists, err := svc.delegate.GetPlaylists()
+func (svc *SubsonicNamedService) GetPlaylists(username string) (*responses.SubsonicPlaylists, error) {
+	playlists, err := svc.delegate.GetPlaylists(username)
 	if err != nil {
 		return nil, err
 	}
@@ -121,12 +135,53 @@ func (svc *SubsonicNamedService) GetPlaylists() (*responses.SubsonicPlaylists, e
 	return playlists, nil
 }
 
-func (svc *SubsonicNamedService) GetArtist(id string) (*responses.Artist, error) {
-	return svc.GetArtistByRawId(svc.RemovePrefix(id))
+func (svc *SubsonicNamedService) CreatePlaylist(username string, playlistId string, name string, songIds []string) (*responses.SubsonicPlaylist, error) {
+	playlist, err := svc.delegate.CreatePlaylist(username, svc.RemovePrefix(playlistId), name, svc.removePrefixes(songIds))
+	if err != nil {
+		return nil, err
+	}
+	// servers implementing api versions before 1.14.0 don't return anything
+	if playlist == nil {
+		return nil, nil
+	}
+
+	rewrittenPlaylist := svc.rewritePlaylistInfo(*playlist)
+	return &rewrittenPlaylist, nil
 }
 
-func (svc *SubsonicNamedService) GetArtistByRawId(id string) (*responses.Artist, error) {
-	artist, err := svc.delegate.GetArtist(id)
+func (svc *SubsonicNamedService) UpdatePlaylist(username string, playlistId string, name string, songIdsToAdd []string, songIndexesToRemove []int) error {
+	return svc.delegate.UpdatePlaylist(username, svc.RemovePrefix(playlistId), name, svc.removePrefixes(songIdsToAdd), songIndexesToRemove)
+}
+
+func (svc *SubsonicNamedService) DeletePlaylist(username string, playlistId string) error {
+	return svc.delegate.DeletePlaylist(username, svc.RemovePrefix(playlistId))
+}
+
+func (svc *SubsonicNamedService) GetGenres() (*responses.Genres, error) {
+	return svc.delegate.GetGenres()
+}
+
+func (svc *SubsonicNamedService) GetArtists(username string) (*responses.Artists, error) {
+	artists, err := svc.delegate.GetArtists(username)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range artists.Index {
+		for j := range artists.Index[i].Artist {
+			artists.Index[i].Artist[j] = svc.rewriteArtistId3Info(artists.Index[i].Artist[j])
+		}
+	}
+
+	return artists, nil
+}
+
+func (svc *SubsonicNamedService) GetArtist(username string, id string) (*responses.Artist, error) {
+	return svc.GetArtistByRawId(username, svc.RemovePrefix(id))
+}
+
+func (svc *SubsonicNamedService) GetArtistByRawId(username string, id string) (*responses.Artist, error) {
+	artist, err := svc.delegate.GetArtist(username, id)
 	if err != nil {
 		return nil, err
 	}
@@ -135,28 +190,193 @@ func (svc *SubsonicNamedService) GetArtistByRawId(id string) (*responses.Artist,
 	return &rewrittenArtist, nil
 }
 
-func (svc *SubsonicNamedService) Scrobble(id string, time_ time.Time, submission bool) error {
-	return svc.ScrobbleByRawId(svc.RemovePrefix(id), time_, submission)
+func (svc *SubsonicNamedService) Scrobble(username string, id string, time_ time.Time, submission bool) error {
+	return svc.ScrobbleByRawId(username, svc.RemovePrefix(id), time_, submission)
+}
+
+func (svc *SubsonicNamedService) ScrobbleByRawId(username string, id string, time_ time.Time, submission bool) error {
+	return svc.delegate.Scrobble(username, id, time_, submission)
+}
+
+func (svc *SubsonicNamedService) Star(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.StarByRawIds(username, svc.removePrefixes(ids), svc.removePrefixes(albumIds), svc.removePrefixes(artistIds))
+}
+
+func (svc *SubsonicNamedService) StarByRawIds(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.delegate.Star(username, ids, albumIds, artistIds)
+}
+
+func (svc *SubsonicNamedService) Unstar(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.UnstarByRawIds(username, svc.removePrefixes(ids), svc.removePrefixes(albumIds), svc.removePrefixes(artistIds))
+}
+
+func (svc *SubsonicNamedService) UnstarByRawIds(username string, ids []string, albumIds []string, artistIds []string) error {
+	return svc.delegate.Unstar(username, ids, albumIds, artistIds)
+}
+
+func (svc *SubsonicNamedService) SetRating(username string, id string, rating int) error {
+	return svc.delegate.SetRating(username, svc.RemovePrefix(id), rating)
+}
+
+func (svc *SubsonicNamedService) CreateBookmark(username string, id string, positionMs int64, comment string) error {
+	return svc.delegate.CreateBookmark(username, svc.RemovePrefix(id), positionMs, comment)
+}
+
+func (svc *SubsonicNamedService) GetBookmarks(username string) (*responses.Bookmarks, error) {
+	bookmarks, err := svc.delegate.GetBookmarks(username)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range bookmarks.Bookmark {
+		bookmarks.Bookmark[i].Entry = svc.rewriteSongInfo(bookmarks.Bookmark[i].Entry)
+	}
+
+	return bookmarks, nil
+}
+
+func (svc *SubsonicNamedService) DeleteBookmark(username string, id string) error {
+	return svc.delegate.DeleteBookmark(username, svc.RemovePrefix(id))
+}
+
+func (svc *SubsonicNamedService) GetStarred2(username string) (*responses.Starred2, error) {
+	starred, err := svc.delegate.GetStarred2(username)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range starred.Artist {
+		starred.Artist[i] = svc.rewriteArtistId3Info(starred.Artist[i])
+	}
+	for i := range starred.Album {
+		starred.Album[i] = svc.rewriteAlbumInfo(starred.Album[i])
+	}
+	for i := range starred.Song {
+		starred.Song[i] = svc.rewriteSongInfo(starred.Song[i])
+	}
+
+	return starred, nil
+}
+
+func (svc *SubsonicNamedService) GetPodcasts(id string, includeEpisodes bool) (*responses.Podcasts, error) {
+	podcasts, err := svc.delegate.GetPodcasts(svc.RemovePrefix(id), includeEpisodes)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range podcasts.Channel {
+		podcasts.Channel[i] = svc.rewritePodcastChannelInfo(podcasts.Channel[i])
+	}
+
+	return podcasts, nil
+}
+
+func (svc *SubsonicNamedService) GetNewestPodcasts(count int) (*responses.NewestPodcasts, error) {
+	podcasts, err := svc.delegate.GetNewestPodcasts(count)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range podcasts.Episode {
+		podcasts.Episode[i] = svc.rewritePodcastEpisodeInfo(podcasts.Episode[i])
+	}
+
+	return podcasts, nil
+}
+
+func (svc *SubsonicNamedService) CreatePodcastChannel(url string) error {
+	return svc.delegate.CreatePodcastChannel(url)
 }
 
-func (svc *SubsonicNamedService) ScrobbleByRawId(id string, time_ time.Time, submission bool) error {
-	return svc.delegate.Scrobble(id, time_, submission)
+func (svc *SubsonicNamedService) DeletePodcastChannel(id string) error {
+	return svc.delegate.DeletePodcastChannel(svc.RemovePrefix(id))
 }
 
-func (svc *SubsonicNamedService) GetCoverArt(id string) (mime string, reader io.ReadCloser, err error) {
-	return svc.GetCoverArtByRawId(svc.RemovePrefix(id))
+func (svc *SubsonicNamedService) RefreshPodcasts() error {
+	return svc.delegate.RefreshPodcasts()
 }
 
-func (svc *SubsonicNamedService) GetCoverArtByRawId(id string) (mime string, reader io.ReadCloser, err error) {
-	return svc.delegate.GetCoverArt(id)
+func (svc *SubsonicNamedService) DownloadPodcastEpisode(id string) error {
+	return svc.delegate.DownloadPodcastEpisode(svc.RemovePrefix(id))
 }
 
-func (svc *SubsonicNamedService) Stream(ctx context.Context, id string) (AudioStream, error) {
-	return svc.StreamByRawId(ctx, svc.RemovePrefix(id))
+func (svc *SubsonicNamedService) GetLyrics(artist string, title string) (*responses.Lyrics, error) {
+	return svc.delegate.GetLyrics(artist, title)
 }
 
-func (svc *SubsonicNamedService) StreamByRawId(ctx context.Context, id string) (AudioStream, error) {
-	return svc.delegate.Stream(ctx, id)
+func (svc *SubsonicNamedService) GetLyricsBySongId(id string) (*responses.LyricsList, error) {
+	return svc.delegate.GetLyricsBySongId(svc.RemovePrefix(id))
+}
+
+func (svc *SubsonicNamedService) GetMusicFolders() (*responses.MusicFolders, error) {
+	musicFolders, err := svc.delegate.GetMusicFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range musicFolders.MusicFolder {
+		musicFolders.MusicFolder[i].Id = svc.addPrefix(musicFolders.MusicFolder[i].Id)
+	}
+
+	return musicFolders, nil
+}
+
+func (svc *SubsonicNamedService) GetIndexes(username string, musicFolderId string) (*responses.Indexes, error) {
+	indexes, err := svc.delegate.GetIndexes(username, svc.RemovePrefix(musicFolderId))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range indexes.Index {
+		for j := range indexes.Index[i].Artist {
+			indexes.Index[i].Artist[j] = svc.rewriteArtistId3Info(indexes.Index[i].Artist[j])
+		}
+	}
+
+	return indexes, nil
+}
+
+func (svc *SubsonicNamedService) GetMusicDirectory(username string, id string) (*responses.Directory, error) {
+	directory, err := svc.delegate.GetMusicDirectory(username, svc.RemovePrefix(id))
+	if err != nil {
+		return nil, err
+	}
+
+	directory.Id = svc.addPrefix(directory.Id)
+	directory.Parent = svc.addPrefix(directory.Parent)
+	for i := range directory.Child {
+		directory.Child[i] = svc.rewriteSongInfo(directory.Child[i])
+	}
+
+	return directory, nil
+}
+
+func (svc *SubsonicNamedService) GetCoverArt(id string, size int) (mime string, reader io.ReadCloser, err error) {
+	return svc.GetCoverArtByRawId(svc.RemovePrefix(id), size)
+}
+
+func (svc *SubsonicNamedService) GetCoverArtByRawId(id string, size int) (mime string, reader io.ReadCloser, err error) {
+	return svc.delegate.GetCoverArt(id, size)
+}
+
+func (svc *SubsonicNamedService) Stream(ctx context.Context, id string, options StreamOptions) (AudioStream, error) {
+	return svc.StreamByRawId(ctx, svc.RemovePrefix(id), options)
+}
+
+func (svc *SubsonicNamedService) StreamByRawId(ctx context.Context, id string, options StreamOptions) (AudioStream, error) {
+	return svc.delegate.Stream(ctx, id, options)
+}
+
+func (svc *SubsonicNamedService) Download(ctx context.Context, id string) (AudioStream, error) {
+	return svc.delegate.Download(ctx, svc.RemovePrefix(id))
+}
+
+func (svc *SubsonicNamedService) GetStreamSegments(id string) ([]int64, error) {
+	return svc.delegate.GetStreamSegments(svc.RemovePrefix(id))
+}
+
+func (svc *SubsonicNamedService) StreamSegment(ctx context.Context, id string, index int, maxBitRate int) (AudioStream, error) {
+	return svc.delegate.StreamSegment(ctx, svc.RemovePrefix(id), index, maxBitRate)
 }
 
 func (svc *SubsonicNamedService) GetLicense() (*responses.License, error) {
@@ -211,20 +431,48 @@ func (svc *SubsonicNamedService) GetRawPlaylist(playlist responses.SubsonicPlayl
 
 func (svc *SubsonicNamedService) rewriteSongInfo(song responses.SubsonicChild) responses.SubsonicChild {
 	song.Id = svc.addPrefix(song.Id)
+	song.Parent = svc.addPrefix(song.Parent)
 	song.CoverArt = svc.addPrefix(song.CoverArt)
 	song.AlbumId = svc.addPrefix(song.AlbumId)
 	song.ArtistId = svc.addPrefix(song.ArtistId)
+	song.Artists = slices.Clone(song.Artists)
+	for i := range song.Artists {
+		song.Artists[i] = svc.rewriteArtistId3Info(song.Artists[i])
+	}
 	return song
 }
 
 func (svc *SubsonicNamedService) GetRawSong(song responses.SubsonicChild) responses.SubsonicChild {
 	song.Id = svc.RemovePrefix(song.Id)
+	song.Parent = svc.RemovePrefix(song.Parent)
 	song.CoverArt = svc.RemovePrefix(song.CoverArt)
 	song.AlbumId = svc.RemovePrefix(song.AlbumId)
 	song.ArtistId = svc.RemovePrefix(song.ArtistId)
+	song.Artists = slices.Clone(song.Artists)
+	for i := range song.Artists {
+		song.Artists[i] = svc.GetRawArtistId3(song.Artists[i])
+	}
 	return song
 }
 
+func (svc *SubsonicNamedService) rewritePodcastChannelInfo(channel responses.PodcastChannel) responses.PodcastChannel {
+	channel.Id = svc.addPrefix(channel.Id)
+	channel.CoverArt = svc.addPrefix(channel.CoverArt)
+
+	for i := range channel.Episode {
+		channel.Episode[i] = svc.rewritePodcastEpisodeInfo(channel.Episode[i])
+	}
+
+	return channel
+}
+
+func (svc *SubsonicNamedService) rewritePodcastEpisodeInfo(episode responses.PodcastEpisode) responses.PodcastEpisode {
+	episode.SubsonicChild = svc.rewriteSongInfo(episode.SubsonicChild)
+	episode.StreamId = svc.addPrefix(episode.StreamId)
+	episode.ChannelId = svc.addPrefix(episode.ChannelId)
+	return episode
+}
+
 func (svc *SubsonicNamedService) rewriteArtistId3Info(artist responses.ArtistId3) responses.ArtistId3 {
 	artist.Id = svc.addPrefix(artist.Id)
 	artist.CoverArt = svc.addPrefix(artist.CoverArt)
@@ -239,6 +487,7 @@ func (svc *SubsonicNamedService) GetRawArtistId3(artist responses.ArtistId3) res
 
 func (svc *SubsonicNamedService) rewriteArtistInfo(artist responses.Artist) responses.Artist {
 	artist.Id = svc.addPrefix(artist.Id)
+	artist.CoverArt = svc.addPrefix(artist.CoverArt)
 
 	for i := range artist.Album {
 		artist.Album[i] = svc.rewriteAlbumInfo(artist.Album[i])
@@ -249,6 +498,7 @@ func (svc *SubsonicNamedService) rewriteArtistInfo(artist responses.Artist) resp
 
 func (svc *SubsonicNamedService) GetRawArtist(artist responses.Artist) responses.Artist {
 	artist.Id = svc.RemovePrefix(artist.Id)
+	artist.CoverArt = svc.RemovePrefix(artist.CoverArt)
 
 	for i := range artist.Album {
 		artist.Album[i] = svc.GetRawAlbum(artist.Album[i])
@@ -277,6 +527,14 @@ func (svc *SubsonicNamedService) RemovePrefix(id string) string {
 	return strings.TrimPrefix(id, svc.generatePrefix())
 }
 
+func (svc *SubsonicNamedService) removePrefixes(ids []string) []string {
+	result := []string{}
+	for _, id := range ids {
+		result = append(result, svc.RemovePrefix(id))
+	}
+	return result
+}
+
 func (svc *SubsonicNamedService) generatePrefix() string {
 	return fmt.Sprintf("%s_", svc.name)
 }
@@ -0,0 +1,478 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"tapesonic/http/subsonic/responses"
+	"tapesonic/storage"
+	"tapesonic/util"
+	"time"
+)
+
+// proxied servers are accessed with a single account shared by all users, so stars, ratings and bookmarks
+// are kept locally for each user and replace whatever the server returns; playlists can only be changed by admins
+type subsonicSharedAccountService struct {
+	SubsonicService
+
+	name string
+
+	users     *storage.UserStorage
+	starred   *storage.MuxedStarredItemStorage
+	ratings   *storage.MuxedRatingStorage
+	bookmarks *storage.MuxedBookmarkStorage
+}
+
+func NewSubsonicSharedAccountService(
+	name string,
+	delegate SubsonicService,
+	users *storage.UserStorage,
+	starred *storage.MuxedStarredItemStorage,
+	ratings *storage.MuxedRatingStorage,
+	bookmarks *storage.MuxedBookmarkStorage,
+) SubsonicService {
+	return &subsonicSharedAccountService{
+		SubsonicService: delegate,
+		name:            name,
+		users:           users,
+		starred:         starred,
+		ratings:         ratings,
+		bookmarks:       bookmarks,
+	}
+}
+
+func (svc *subsonicSharedAccountService) Search3(
+	username string,
+	query string,
+	artistCount int,
+	artistOffset int,
+	albumCount int,
+	albumOffset int,
+	songCount int,
+	songOffset int,
+) (*responses.SearchResult3, error) {
+	search, err := svc.SubsonicService.Search3(username, query, artistCount, artistOffset, albumCount, albumOffset, songCount, songOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToArtistIds3(search.Artist)
+	state.applyToAlbums(search.Album)
+	state.applyToSongs(search.Song)
+
+	return search, nil
+}
+
+func (svc *subsonicSharedAccountService) GetSong(username string, id string) (*responses.SubsonicChild, error) {
+	song, err := svc.SubsonicService.GetSong(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToSong(song)
+
+	return song, nil
+}
+
+func (svc *subsonicSharedAccountService) GetRandomSongs(username string, size int, genre string, fromYear *int, toYear *int) (*responses.RandomSongs, error) {
+	songs, err := svc.SubsonicService.GetRandomSongs(username, size, genre, fromYear, toYear)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToSongs(songs.Song)
+
+	return songs, nil
+}
+
+func (svc *subsonicSharedAccountService) GetSongsByGenre(username string, genre string, count int, offset int) (*responses.SongsByGenre, error) {
+	songs, err := svc.SubsonicService.GetSongsByGenre(username, genre, count, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToSongs(songs.Song)
+
+	return songs, nil
+}
+
+func (svc *subsonicSharedAccountService) GetAlbum(username string, id string) (*responses.AlbumId3, error) {
+	album, err := svc.SubsonicService.GetAlbum(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToAlbum(album)
+
+	return album, nil
+}
+
+func (svc *subsonicSharedAccountService) GetAlbumList2(username string, type_ string, size int, offset int, fromYear *int, toYear *int, genre string) (*responses.AlbumList2, error) {
+	albums, err := svc.SubsonicService.GetAlbumList2(username, type_, size, offset, fromYear, toYear, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToAlbums(albums.Album)
+
+	return albums, nil
+}
+
+func (svc *subsonicSharedAccountService) GetPlaylist(username string, id string) (*responses.SubsonicPlaylist, error) {
+	playlist, err := svc.SubsonicService.GetPlaylist(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToSongs(playlist.Entry)
+
+	return playlist, nil
+}
+
+func (svc *subsonicSharedAccountService) CreatePlaylist(username string, playlistId string, name string, songIds []string) (*responses.SubsonicPlaylist, error) {
+	if err := svc.checkCanEditPlaylists(username); err != nil {
+		return nil, err
+	}
+
+	return svc.SubsonicService.CreatePlaylist(username, playlistId, name, songIds)
+}
+
+func (svc *subsonicSharedAccountService) UpdatePlaylist(username string, playlistId string, name string, songIdsToAdd []string, songIndexesToRemove []int) error {
+	if err := svc.checkCanEditPlaylists(username); err != nil {
+		return err
+	}
+
+	return svc.SubsonicService.UpdatePlaylist(username, playlistId, name, songIdsToAdd, songIndexesToRemove)
+}
+
+func (svc *subsonicSharedAccountService) DeletePlaylist(username string, playlistId string) error {
+	if err := svc.checkCanEditPlaylists(username); err != nil {
+		return err
+	}
+
+	return svc.SubsonicService.DeletePlaylist(username, playlistId)
+}
+
+// every user sees the same playlists of the server, so only admins can change them
+func (svc *subsonicSharedAccountService) checkCanEditPlaylists(username string) error {
+	user, err := svc.users.Find(username)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Role != storage.USER_ROLE_ADMIN {
+		return ErrReadOnlyPlaylist
+	}
+
+	return nil
+}
+
+func (svc *subsonicSharedAccountService) GetArtists(username string) (*responses.Artists, error) {
+	artists, err := svc.SubsonicService.GetArtists(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range artists.Index {
+		state.applyToArtistIds3(artists.Index[i].Artist)
+	}
+
+	return artists, nil
+}
+
+func (svc *subsonicSharedAccountService) GetArtist(username string, id string) (*responses.Artist, error) {
+	artist, err := svc.SubsonicService.GetArtist(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToArtist(artist)
+
+	return artist, nil
+}
+
+func (svc *subsonicSharedAccountService) Star(username string, ids []string, albumIds []string, artistIds []string) error {
+	starredAt := time.Now()
+	return errors.Join(
+		svc.starred.Star(username, svc.name, storage.STARRED_ITEM_TYPE_SONG, ids, starredAt),
+		svc.starred.Star(username, svc.name, storage.STARRED_ITEM_TYPE_ALBUM, albumIds, starredAt),
+		svc.starred.Star(username, svc.name, storage.STARRED_ITEM_TYPE_ARTIST, artistIds, starredAt),
+	)
+}
+
+func (svc *subsonicSharedAccountService) Unstar(username string, ids []string, albumIds []string, artistIds []string) error {
+	return errors.Join(
+		svc.starred.Unstar(username, svc.name, storage.STARRED_ITEM_TYPE_SONG, ids),
+		svc.starred.Unstar(username, svc.name, storage.STARRED_ITEM_TYPE_ALBUM, albumIds),
+		svc.starred.Unstar(username, svc.name, storage.STARRED_ITEM_TYPE_ARTIST, artistIds),
+	)
+}
+
+// the mux records ratings of all services for its album lists as well, but this keeps the service usable on its own
+func (svc *subsonicSharedAccountService) SetRating(username string, id string, rating int) error {
+	return svc.ratings.Record(username, svc.name, id, rating, time.Now())
+}
+
+func (svc *subsonicSharedAccountService) CreateBookmark(username string, id string, positionMs int64, comment string) error {
+	return svc.bookmarks.Upsert(storage.MuxedBookmark{
+		Username:    username,
+		ServiceName: svc.name,
+		ItemId:      id,
+		PositionMs:  positionMs,
+		Comment:     comment,
+	})
+}
+
+func (svc *subsonicSharedAccountService) GetBookmarks(username string) (*responses.Bookmarks, error) {
+	bookmarks, err := svc.bookmarks.GetAllByUsername(username, svc.name)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBookmarks, err := util.ParallelMap(bookmarks, func(bookmark storage.MuxedBookmark) (*responses.Bookmark, error) {
+		song, err := svc.SubsonicService.GetSong(username, bookmark.ItemId)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to get bookmarked song id=%s of subsonic `%s`, skipping: %s", bookmark.ItemId, svc.name, err))
+			return nil, nil
+		}
+
+		return responses.NewBookmark(bookmark.PositionMs, username, bookmark.Comment, bookmark.CreatedAt, bookmark.UpdatedAt, *song), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []responses.Bookmark{}
+	for _, bookmark := range responseBookmarks {
+		if bookmark != nil {
+			state.applyToSong(&bookmark.Entry)
+			result = append(result, *bookmark)
+		}
+	}
+
+	return responses.NewBookmarks(result), nil
+}
+
+func (svc *subsonicSharedAccountService) DeleteBookmark(username string, id string) error {
+	return svc.bookmarks.Delete(username, svc.name, id)
+}
+
+// items which can't be fetched from the server anymore are skipped, but their stars are kept in case it's temporary
+func (svc *subsonicSharedAccountService) GetStarred2(username string) (*responses.Starred2, error) {
+	starredItems, err := svc.starred.GetStarredItems(username, svc.name)
+	if err != nil {
+		return nil, err
+	}
+
+	type starredResult struct {
+		artist *responses.ArtistId3
+		album  *responses.AlbumId3
+		song   *responses.SubsonicChild
+	}
+
+	results, err := util.ParallelMap(starredItems, func(item storage.MuxedStarredItem) (starredResult, error) {
+		var result starredResult
+		var err error
+
+		switch item.ItemType {
+		case storage.STARRED_ITEM_TYPE_ARTIST:
+			var artist *responses.Artist
+			if artist, err = svc.SubsonicService.GetArtist(username, item.ItemId); err == nil {
+				result.artist = responses.NewArtistId3(artist.Id, artist.Name)
+				result.artist.CoverArt = artist.CoverArt
+				result.artist.AlbumCount = len(artist.Album)
+				result.artist.ArtistImageUrl = artist.ArtistImageUrl
+			}
+		case storage.STARRED_ITEM_TYPE_ALBUM:
+			if result.album, err = svc.SubsonicService.GetAlbum(username, item.ItemId); err == nil {
+				result.album.Song = nil
+			}
+		default:
+			result.song, err = svc.SubsonicService.GetSong(username, item.ItemId)
+		}
+
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to get starred %s id=%s of subsonic `%s`, skipping: %s", item.ItemType, item.ItemId, svc.name, err))
+			return starredResult{}, nil
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	artists := []responses.ArtistId3{}
+	albums := []responses.AlbumId3{}
+	songs := []responses.SubsonicChild{}
+	for _, result := range results {
+		if result.artist != nil {
+			artists = append(artists, *result.artist)
+		}
+		if result.album != nil {
+			albums = append(albums, *result.album)
+		}
+		if result.song != nil {
+			songs = append(songs, *result.song)
+		}
+	}
+
+	state.applyToArtistIds3(artists)
+	state.applyToAlbums(albums)
+	state.applyToSongs(songs)
+
+	return responses.NewStarred2(artists, albums, songs), nil
+}
+
+func (svc *subsonicSharedAccountService) GetMusicDirectory(username string, id string) (*responses.Directory, error) {
+	directory, err := svc.SubsonicService.GetMusicDirectory(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := svc.getUserState(username)
+	if err != nil {
+		return nil, err
+	}
+
+	state.applyToSongs(directory.Child)
+
+	return directory, nil
+}
+
+type sharedAccountUserState struct {
+	// item type -> item id -> starred at
+	starred map[string]map[string]time.Time
+	ratings map[string]int
+}
+
+func (svc *subsonicSharedAccountService) getUserState(username string) (sharedAccountUserState, error) {
+	state := sharedAccountUserState{
+		starred: map[string]map[string]time.Time{},
+		ratings: map[string]int{},
+	}
+
+	starredItems, err := svc.starred.GetStarredItems(username, svc.name)
+	if err != nil {
+		return state, err
+	}
+	for _, item := range starredItems {
+		if state.starred[item.ItemType] == nil {
+			state.starred[item.ItemType] = map[string]time.Time{}
+		}
+		state.starred[item.ItemType][item.ItemId] = item.StarredAt
+	}
+
+	ratings, err := svc.ratings.GetRatings(username, svc.name)
+	if err != nil {
+		return state, err
+	}
+	for _, rating := range ratings {
+		state.ratings[rating.ItemId] = rating.Rating
+	}
+
+	return state, nil
+}
+
+func (state sharedAccountUserState) getStarredAt(itemType string, id string) *time.Time {
+	starredAt, ok := state.starred[itemType][id]
+	if !ok {
+		return nil
+	}
+	return &starredAt
+}
+
+// directory children can be albums as well as songs
+func (state sharedAccountUserState) applyToSong(song *responses.SubsonicChild) {
+	itemType := storage.STARRED_ITEM_TYPE_SONG
+	if song.IsDir {
+		itemType = storage.STARRED_ITEM_TYPE_ALBUM
+	}
+
+	song.Starred = state.getStarredAt(itemType, song.Id)
+	song.UserRating = state.ratings[song.Id]
+}
+
+func (state sharedAccountUserState) applyToSongs(songs []responses.SubsonicChild) {
+	for i := range songs {
+		state.applyToSong(&songs[i])
+	}
+}
+
+func (state sharedAccountUserState) applyToAlbum(album *responses.AlbumId3) {
+	album.Starred = state.getStarredAt(storage.STARRED_ITEM_TYPE_ALBUM, album.Id)
+	album.UserRating = state.ratings[album.Id]
+	state.applyToSongs(album.Song)
+}
+
+func (state sharedAccountUserState) applyToAlbums(albums []responses.AlbumId3) {
+	for i := range albums {
+		state.applyToAlbum(&albums[i])
+	}
+}
+
+func (state sharedAccountUserState) applyToArtistIds3(artists []responses.ArtistId3) {
+	for i := range artists {
+		artists[i].Starred = state.getStarredAt(storage.STARRED_ITEM_TYPE_ARTIST, artists[i].Id)
+	}
+}
+
+func (state sharedAccountUserState) applyToArtist(artist *responses.Artist) {
+	artist.Starred = state.getStarredAt(storage.STARRED_ITEM_TYPE_ARTIST, artist.Id)
+	artist.UserRating = state.ratings[artist.Id]
+	state.applyToAlbums(artist.Album)
+}
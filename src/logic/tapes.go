@@ -11,17 +11,20 @@ import (
 )
 
 type TapeService struct {
-	tapes  *storage.TapeStorage
-	tracks *storage.TrackStorage
+	tapes      *storage.TapeStorage
+	tracks     *storage.TrackStorage
+	thumbnails *ThumbnailService
 }
 
 func NewTapeService(
 	tapes *storage.TapeStorage,
 	tracks *storage.TrackStorage,
+	thumbnails *ThumbnailService,
 ) *TapeService {
 	return &TapeService{
-		tapes:  tapes,
-		tracks: tracks,
+		tapes:      tapes,
+		tracks:     tracks,
+		thumbnails: thumbnails,
 	}
 }
 
@@ -41,6 +44,15 @@ func (s *TapeService) Create(tape storage.Tape) (storage.Tape, []storage.Track,
 }
 
 func (s *TapeService) Update(tape storage.Tape) (storage.Tape, []storage.Track, error) {
+	if tape.Genres == nil {
+		// clients that don't know about genres shouldn't wipe them
+		existingTape, err := s.tapes.GetTape(tape.Id)
+		if err != nil {
+			return storage.Tape{}, []storage.Track{}, err
+		}
+		tape.Genres = existingTape.Genres
+	}
+
 	tape, err := s.tapes.Update(tape)
 	if err != nil {
 		return storage.Tape{}, []storage.Track{}, err
@@ -55,7 +67,11 @@ func (s *TapeService) Update(tape storage.Tape) (storage.Tape, []storage.Track,
 }
 
 func (s *TapeService) DeleteById(id uuid.UUID) error {
-	return s.tapes.DeleteById(id)
+	if err := s.tapes.DeleteById(id); err != nil {
+		return err
+	}
+
+	return s.thumbnails.DeleteUnused()
 }
 
 func (s *TapeService) GetList() ([]storage.Tape, error) {
@@ -3,29 +3,51 @@ package logic
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path"
 	"tapesonic/storage"
 	"tapesonic/util"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+
+	_ "image/gif"
+
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	THUMBNAIL_VARIANT_JPEG_QUALITY = 90
+
+	// thumbnails are created before the entities referencing them,
+	// so recently updated ones are never considered unused
+	UNUSED_THUMBNAIL_MIN_AGE = time.Hour
 )
 
 type ThumbnailService struct {
-	storage     *storage.ThumbnailStorage
-	contentPath string
+	storage      *storage.ThumbnailStorage
+	contentPath  string
+	variantsPath string
 }
 
 func NewThumbnailService(
 	storage *storage.ThumbnailStorage,
 	contentPath string,
+	variantsPath string,
 ) *ThumbnailService {
 	return &ThumbnailService{
-		storage:     storage,
-		contentPath: contentPath,
+		storage:      storage,
+		contentPath:  contentPath,
+		variantsPath: variantsPath,
 	}
 }
 
@@ -88,3 +110,150 @@ func (s *ThumbnailService) GetThumbnailContent(id uuid.UUID) (string, io.ReadClo
 
 	return util.FormatToMediaType(thumbnail.Format), reader, nil
 }
+
+// GetThumbnailVariant returns the thumbnail center-cropped to a square of the given size, size 0 keeps the original
+// dimensions; formats other than jpeg and png aren't supported by some clients, so they are always converted to jpeg
+func (s *ThumbnailService) GetThumbnailVariant(id uuid.UUID, size int) (string, io.ReadCloser, error) {
+	thumbnail, err := s.storage.GetById(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	format := "jpeg"
+	if thumbnail.Format == "png" {
+		format = "png"
+	}
+
+	if size <= 0 && thumbnail.Format == format {
+		return s.GetThumbnailContent(id)
+	}
+
+	// sizes above the original are served by the same file, otherwise every requested size would be cached separately
+	if size > 0 {
+		side, err := s.getSquareSide(thumbnail)
+		if err != nil {
+			return "", nil, err
+		}
+		size = min(size, side)
+	}
+
+	variantPath := path.Join(s.variantsPath, id.String(), fmt.Sprintf("%d.%s", max(size, 0), format))
+	reader, err := os.Open(variantPath)
+	if err == nil {
+		return util.FormatToMediaType(format), reader, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", nil, err
+	}
+
+	slog.Debug(fmt.Sprintf("Creating variant of thumbnail id=`%s`, size=%d, format=%s", id, size, format))
+
+	if err := s.createVariant(thumbnail, size, format, variantPath); err != nil {
+		return "", nil, fmt.Errorf("failed to create variant of thumbnail id=`%s`: %w", id, err)
+	}
+
+	reader, err = os.Open(variantPath)
+	if err != nil {
+		return "", nil, err
+	}
+	return util.FormatToMediaType(format), reader, nil
+}
+
+// DeleteUnused removes thumbnails which aren't referenced by any source, tape or podcast channel anymore
+func (s *ThumbnailService) DeleteUnused() error {
+	thumbnails, err := s.storage.GetUnused(time.Now().Add(-UNUSED_THUMBNAIL_MIN_AGE))
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, thumbnail := range thumbnails {
+		slog.Debug(fmt.Sprintf("Deleting unused thumbnail id=`%s`", thumbnail.Id))
+
+		if err := s.storage.DeleteById(thumbnail.Id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.Remove(path.Join(s.contentPath, thumbnail.FilePath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+		if err := os.RemoveAll(path.Join(s.variantsPath, thumbnail.Id.String())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// only the header is decoded, which is much cheaper than decoding the whole image
+func (s *ThumbnailService) getSquareSide(thumbnail storage.Thumbnail) (int, error) {
+	file, err := os.Open(path.Join(s.contentPath, thumbnail.FilePath))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, err
+	}
+
+	return min(config.Width, config.Height), nil
+}
+
+func (s *ThumbnailService) createVariant(thumbnail storage.Thumbnail, size int, format string, variantPath string) error {
+	file, err := os.Open(path.Join(s.contentPath, thumbnail.FilePath))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	original, _, err := image.Decode(file)
+	if err != nil {
+		return err
+	}
+
+	variant := original
+	if size > 0 {
+		variant = resizeToSquare(original, size)
+	}
+
+	if err := os.MkdirAll(path.Dir(variantPath), 0777); err != nil {
+		return err
+	}
+
+	// concurrent requests may create the same variant, renaming makes sure nobody reads a partially written file
+	tmpFile, err := os.CreateTemp(path.Dir(variantPath), "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	switch format {
+	case "png":
+		err = png.Encode(tmpFile, variant)
+	default:
+		err = jpeg.Encode(tmpFile, variant, &jpeg.Options{Quality: THUMBNAIL_VARIANT_JPEG_QUALITY})
+	}
+	if err = errors.Join(err, tmpFile.Close()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), variantPath)
+}
+
+// video thumbnails of music uploads are usually the square cover art with bars on the sides,
+// so cropping the center works better than letterboxing; images are never upscaled
+func resizeToSquare(original image.Image, size int) image.Image {
+	bounds := original.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	size = min(size, side)
+
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+
+	result := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(result, result.Bounds(), original, crop, draw.Src, nil)
+	return result
+}
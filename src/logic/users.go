@@ -0,0 +1,248 @@
+package logic
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"tapesonic/storage"
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrInvalidUserRole   = errors.New("invalid user role")
+)
+
+type User struct {
+	Username string
+	Email    string
+	Role     string
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == storage.USER_ROLE_ADMIN
+}
+
+type UserCredentials struct {
+	User
+	Password string
+}
+
+type UserService struct {
+	storage *storage.UserStorage
+	cipher  cipher.AEAD
+}
+
+func NewUserService(
+	storage *storage.UserStorage,
+	keyPath string,
+) (*UserService, error) {
+	key, err := loadOrCreateEncryptionKey(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption key from %s: %w", keyPath, err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserService{
+		storage: storage,
+		cipher:  gcm,
+	}, nil
+}
+
+// EnsureUser makes sure the user from the config exists and is an admin, so there's always a way to log in;
+// the password is only required to create the user, an empty one keeps the stored password
+func (s *UserService) EnsureUser(username string, password string) error {
+	if username == "" {
+		return nil
+	}
+
+	user, err := s.storage.Find(username)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		if password == "" {
+			return fmt.Errorf("password must be configured to create admin user %s", username)
+		}
+
+		slog.Info(fmt.Sprintf("Creating admin user %s", username))
+		_, err := s.Create(username, password, "", storage.USER_ROLE_ADMIN)
+		return err
+	}
+
+	return s.Update(username, password, user.Email, storage.USER_ROLE_ADMIN)
+}
+
+func (s *UserService) GetCredentials(username string) (*UserCredentials, error) {
+	user, err := s.storage.Find(username)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	password, err := s.decrypt(user.EncryptedPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt password of user %s: %w", username, err)
+	}
+
+	return &UserCredentials{
+		User:     toUser(*user),
+		Password: password,
+	}, nil
+}
+
+func (s *UserService) Get(username string) (*User, error) {
+	user, err := s.storage.Find(username)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	result := toUser(*user)
+	return &result, nil
+}
+
+func (s *UserService) GetAll() ([]User, error) {
+	users, err := s.storage.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []User{}
+	for _, user := range users {
+		result = append(result, toUser(user))
+	}
+	return result, nil
+}
+
+func (s *UserService) Create(username string, password string, email string, role string) (User, error) {
+	if username == "" || password == "" {
+		return User{}, errors.New("username and password must be provided")
+	}
+	if !isValidRole(role) {
+		return User{}, ErrInvalidUserRole
+	}
+
+	existing, err := s.storage.Find(username)
+	if err != nil {
+		return User{}, err
+	} else if existing != nil {
+		return User{}, ErrUserAlreadyExists
+	}
+
+	encryptedPassword, err := s.encrypt(password)
+	if err != nil {
+		return User{}, err
+	}
+
+	user, err := s.storage.Create(storage.User{
+		Username:          username,
+		EncryptedPassword: encryptedPassword,
+		Email:             email,
+		Role:              role,
+	})
+	return toUser(user), err
+}
+
+// Update keeps the current password if the new one is empty
+func (s *UserService) Update(username string, password string, email string, role string) error {
+	if !isValidRole(role) {
+		return ErrInvalidUserRole
+	}
+
+	user, err := s.storage.Find(username)
+	if err != nil {
+		return err
+	} else if user == nil {
+		return ErrUserNotFound
+	}
+
+	if password != "" {
+		if user.EncryptedPassword, err = s.encrypt(password); err != nil {
+			return err
+		}
+	}
+	user.Email = email
+	user.Role = role
+
+	return s.storage.Update(*user)
+}
+
+func (s *UserService) Delete(username string) error {
+	user, err := s.storage.Find(username)
+	if err != nil {
+		return err
+	} else if user == nil {
+		return ErrUserNotFound
+	}
+
+	return s.storage.Delete(username)
+}
+
+func (s *UserService) encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, s.cipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(s.cipher.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+}
+
+func (s *UserService) decrypt(ciphertext string) (string, error) {
+	data, err := hex.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := s.cipher.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("ciphertext is too short")
+	}
+
+	plaintext, err := s.cipher.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	return string(plaintext), err
+}
+
+func loadOrCreateEncryptionKey(keyPath string) ([]byte, error) {
+	content, err := os.ReadFile(keyPath)
+	if err == nil {
+		return hex.DecodeString(strings.TrimSpace(string(content)))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	slog.Info(fmt.Sprintf("Generating new encryption key at %s", keyPath))
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, os.WriteFile(keyPath, []byte(hex.EncodeToString(key)), 0600)
+}
+
+func isValidRole(role string) bool {
+	return role == storage.USER_ROLE_ADMIN || role == storage.USER_ROLE_STREAM
+}
+
+func toUser(user storage.User) User {
+	return User{
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+	}
+}
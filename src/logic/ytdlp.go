@@ -82,6 +82,11 @@ func (svc *YtdlpService) GetMetadata(ctx context.Context, url string) (ytdlp.Ytd
 	return result.metadata, nil
 }
 
+// stream urls of live streams expire, so those need to be re-resolved from time to time
+func (svc *YtdlpService) InvalidateMetadata(url string) error {
+	return svc.storage.Delete(url)
+}
+
 func (svc *YtdlpService) GetStreamInfo(ctx context.Context, url string, format string) (ytdlp.YtdlpFormat, error) {
 	metadata, err := svc.GetMetadata(ctx, url)
 	if err != nil {
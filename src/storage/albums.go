@@ -2,12 +2,15 @@ package storage
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var albumGenresExpression = genresExpression("tapes.genres", "album_source_genres.genres")
+
 type AlbumStorage struct {
 	db *gorm.DB
 }
@@ -17,8 +20,8 @@ func NewAlbumStorage(db *gorm.DB) (*AlbumStorage, error) {
 	return &AlbumStorage{db: db}, err
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbum(id uuid.UUID) (*SubsonicAlbumItem, error) {
-	albums, err := storage.getSubsonicAlbums(1, 0, fmt.Sprintf("tapes.id = '%s'", id.String()), "tapes.id")
+func (storage *AlbumStorage) GetSubsonicAlbum(username string, id uuid.UUID) (*SubsonicAlbumItem, error) {
+	albums, err := storage.getSubsonicAlbums(username, 1, 0, fmt.Sprintf("tapes.id = '%s'", id.String()), "tapes.id")
 	if err != nil {
 		return nil, err
 	}
@@ -29,44 +32,61 @@ func (storage *AlbumStorage) GetSubsonicAlbum(id uuid.UUID) (*SubsonicAlbumItem,
 	return &albums[0], nil
 }
 
-func (storage *AlbumStorage) SearchSubsonicAlbums(count int, offset int, query string) ([]SubsonicAlbumItem, error) {
+func (storage *AlbumStorage) SearchSubsonicAlbums(username string, count int, offset int, query string) ([]SubsonicAlbumItem, error) {
 	filter := MakeTextSearchCondition([]string{"tapes.artist", "tapes.name"}, query)
 	if filter == "" {
 		return []SubsonicAlbumItem{}, nil
 	}
 
-	return storage.getSubsonicAlbums(count, offset, filter, "tapes.id")
+	return storage.getSubsonicAlbums(username, count, offset, filter, "tapes.id")
+}
+
+func (storage *AlbumStorage) GetSubsonicAlbumsByArtist(username string, artistId string) ([]SubsonicAlbumItem, error) {
+	filter := fmt.Sprintf("%s = '%s'", artistIdExpression("tapes.artist"), EscapeTextLiteral(artistId))
+	return storage.getSubsonicAlbums(username, math.MaxInt32, 0, filter, "tapes.released_at ASC NULLS LAST, lower(tapes.name) ASC")
+}
+
+func (storage *AlbumStorage) GetSubsonicAlbumsSortId(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "", "tapes.id")
+}
+
+func (storage *AlbumStorage) GetSubsonicAlbumsSortRandom(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "", "random()")
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbumsSortId(count int, offset int) ([]SubsonicAlbumItem, error) {
-	return storage.getSubsonicAlbums(count, offset, "", "tapes.id")
+func (storage *AlbumStorage) GetSubsonicAlbumsSortNewest(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "", "tapes.created_at DESC")
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbumsSortRandom(count int, offset int) ([]SubsonicAlbumItem, error) {
-	return storage.getSubsonicAlbums(count, offset, "", "random()")
+func (storage *AlbumStorage) GetSubsonicAlbumsSortName(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "", "lower(tapes.name)")
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbumsSortNewest(count int, offset int) ([]SubsonicAlbumItem, error) {
-	return storage.getSubsonicAlbums(count, offset, "", "tapes.created_at DESC")
+func (storage *AlbumStorage) GetSubsonicAlbumsSortArtist(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "", "lower(tapes.artist)")
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbumsSortName(count int, offset int) ([]SubsonicAlbumItem, error) {
-	return storage.getSubsonicAlbums(count, offset, "", "lower(tapes.name)")
+func (storage *AlbumStorage) GetSubsonicAlbumsSortRecent(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "album_extra_info.last_listened_at IS NOT NULL", "album_extra_info.last_listened_at DESC")
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbumsSortArtist(count int, offset int) ([]SubsonicAlbumItem, error) {
-	return storage.getSubsonicAlbums(count, offset, "", "lower(tapes.artist)")
+func (storage *AlbumStorage) GetSubsonicAlbumsSortFrequent(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "album_extra_info.total_play_time > 0", "album_extra_info.total_play_time DESC")
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbumsSortRecent(count int, offset int) ([]SubsonicAlbumItem, error) {
-	return storage.getSubsonicAlbums(count, offset, "album_extra_info.last_listened_at IS NOT NULL", "album_extra_info.last_listened_at DESC")
+func (storage *AlbumStorage) GetSubsonicAlbumsSortStarred(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "starred_items.starred_at IS NOT NULL", "starred_items.starred_at DESC")
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbumsSortFrequent(count int, offset int) ([]SubsonicAlbumItem, error) {
-	return storage.getSubsonicAlbums(count, offset, "album_extra_info.total_play_time > 0", "album_extra_info.total_play_time DESC")
+func (storage *AlbumStorage) GetSubsonicAlbumsByGenre(username string, count int, offset int, genre string) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, genreCondition(albumGenresExpression, genre), "lower(tapes.name)")
 }
 
-func (storage *AlbumStorage) GetSubsonicAlbumsSortReleaseDate(count int, offset int, fromYear int, toYear int) ([]SubsonicAlbumItem, error) {
+func (storage *AlbumStorage) GetSubsonicAlbumsSortRating(username string, count int, offset int) ([]SubsonicAlbumItem, error) {
+	return storage.getSubsonicAlbums(username, count, offset, "ratings.rating IS NOT NULL", "ratings.rating DESC, ratings.rated_at DESC")
+}
+
+func (storage *AlbumStorage) GetSubsonicAlbumsSortReleaseDate(username string, count int, offset int, fromYear int, toYear int) ([]SubsonicAlbumItem, error) {
 	var order string
 	if fromYear <= toYear {
 		order = "tapes.released_at ASC"
@@ -77,38 +97,81 @@ func (storage *AlbumStorage) GetSubsonicAlbumsSortReleaseDate(count int, offset
 
 	filter := fmt.Sprintf("tapes.released_at IS NOT NULL AND cast(strftime('%%Y', tapes.released_at) AS INTEGER) BETWEEN %d AND %d", fromYear, toYear)
 
-	return storage.getSubsonicAlbums(count, offset, filter, order)
+	return storage.getSubsonicAlbums(username, count, offset, filter, order)
+}
+
+func (storage *AlbumStorage) getSubsonicAlbums(username string, count int, offset int, filter string, order string) ([]SubsonicAlbumItem, error) {
+	query := subsonicAlbumsQuery(username, filter)
+
+	if order != "" {
+		query += fmt.Sprintf("\nORDER BY %s", order)
+	}
+
+	query += fmt.Sprintf("\nLIMIT %d OFFSET %d", count, offset)
+
+	result := []SubsonicAlbumItem{}
+	return result, storage.db.Raw(query).Find(&result).Error
 }
 
-func (storage *AlbumStorage) getSubsonicAlbums(count int, offset int, filter string, order string) ([]SubsonicAlbumItem, error) {
-	query := `
-		WITH album_extra_info AS (
+func subsonicAlbumsQuery(username string, filter string) string {
+	query := fmt.Sprintf(
+		`
+			WITH album_extra_info AS (
+				SELECT
+					tape_to_tracks.tape_id AS tape_id,
+					count(*) AS song_count,
+					sum(tracks.end_offset_ms - tracks.start_offset_ms) / 1000 AS duration_sec,
+					max(track_listens.last_listened_at) AS last_listened_at,
+					sum(track_listens.listen_count) AS play_count,
+					sum(track_listens.listen_count * (tracks.end_offset_ms - tracks.start_offset_ms)) AS total_play_time
+				FROM tape_to_tracks
+				JOIN tracks ON tracks.id = tape_to_tracks.track_id
+				LEFT JOIN track_listens ON track_listens.username = '%s' AND track_listens.track_id = tape_to_tracks.track_id
+				GROUP BY tape_to_tracks.tape_id
+			),
+			album_source_genres AS (
+				SELECT
+					tape_to_tracks.tape_id AS tape_id,
+					json_group_array(DISTINCT source_genres.value) AS genres
+				FROM tape_to_tracks
+				JOIN tracks ON tracks.id = tape_to_tracks.track_id
+				JOIN sources ON sources.id = tracks.source_id
+				JOIN json_each(sources.genres) source_genres
+				WHERE source_genres.type = 'text'
+				GROUP BY tape_to_tracks.tape_id
+			)
 			SELECT
-				tape_to_tracks.tape_id AS tape_id,
-				count(*) AS song_count,
-				sum(tracks.end_offset_ms - tracks.start_offset_ms) / 1000 AS duration_sec,
-				max(track_listens.last_listened_at) AS last_listened_at,
-				sum(track_listens.listen_count) AS play_count,
-				sum(track_listens.listen_count * (tracks.end_offset_ms - tracks.start_offset_ms)) AS total_play_time
-			FROM tape_to_tracks
-			JOIN tracks ON tracks.id = tape_to_tracks.track_id
-			LEFT JOIN track_listens ON track_listens.track_id = tape_to_tracks.track_id
-			GROUP BY tape_to_tracks.tape_id
-		)
-		SELECT
-			tapes.id AS id,
-			tapes.name AS name,
-			tapes.artist AS artist,
-			tapes.released_at AS release_date,
-			tapes.thumbnail_id AS thumbnail_id,
-			tapes.created_at AS created_at,
-			tapes.updated_at AS updated_at,
-			album_extra_info.song_count AS song_count,
-			album_extra_info.duration_sec AS duration_sec,
-			album_extra_info.play_count AS play_count
-		FROM tapes
-		LEFT JOIN album_extra_info ON album_extra_info.tape_id = tapes.id
-	`
+				tapes.id AS id,
+				tapes.name AS name,
+				tapes.artist AS artist,
+				%s AS artist_id,
+				tapes.released_at AS release_date,
+				tapes.thumbnail_id AS thumbnail_id,
+				tapes.created_at AS created_at,
+				tapes.updated_at AS updated_at,
+				album_extra_info.song_count AS song_count,
+				album_extra_info.duration_sec AS duration_sec,
+				album_extra_info.play_count AS play_count,
+				%s AS genres,
+				starred_items.starred_at AS starred_at,
+				ratings.rating AS user_rating,
+				average_ratings.rating AS average_rating
+			FROM tapes
+			LEFT JOIN album_extra_info ON album_extra_info.tape_id = tapes.id
+			LEFT JOIN album_source_genres ON album_source_genres.tape_id = tapes.id
+			LEFT JOIN starred_items ON starred_items.username = '%s' AND starred_items.item_type = '%s' AND starred_items.item_id = tapes.id
+			LEFT JOIN ratings ON ratings.username = '%s' AND ratings.item_type = '%s' AND ratings.item_id = tapes.id
+			LEFT JOIN (SELECT item_id, avg(rating) AS rating FROM ratings WHERE item_type = '%s' GROUP BY item_id) average_ratings ON average_ratings.item_id = tapes.id
+		`,
+		EscapeTextLiteral(username),
+		artistIdExpression("tapes.artist"),
+		albumGenresExpression,
+		EscapeTextLiteral(username),
+		STARRED_ITEM_TYPE_ALBUM,
+		EscapeTextLiteral(username),
+		RATING_ITEM_TYPE_ALBUM,
+		RATING_ITEM_TYPE_ALBUM,
+	)
 
 	conditions := []string{fmt.Sprintf("tapes.type = '%s'", TAPE_TYPE_ALBUM)}
 	if filter != "" {
@@ -118,12 +181,5 @@ func (storage *AlbumStorage) getSubsonicAlbums(count int, offset int, filter str
 		query += fmt.Sprintf("\nWHERE %s", strings.Join(conditions, " AND "))
 	}
 
-	if order != "" {
-		query += fmt.Sprintf("\nORDER BY %s", order)
-	}
-
-	query += fmt.Sprintf("\nLIMIT %d OFFSET %d", count, offset)
-
-	result := []SubsonicAlbumItem{}
-	return result, storage.db.Raw(query).Find(&result).Error
+	return query
 }
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ApiKey struct {
+	Id uuid.UUID
+
+	Username string `gorm:"index"`
+	Name     string
+
+	// only the sha256 of the key is kept, the key itself is shown once when it's issued
+	KeyHash string `gorm:"uniqueIndex"`
+
+	LastUsedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ApiKeyStorage struct {
+	db *DbHelper
+}
+
+func NewApiKeyStorage(db *gorm.DB) (*ApiKeyStorage, error) {
+	if err := db.AutoMigrate(&ApiKey{}); err != nil {
+		return nil, err
+	}
+
+	return &ApiKeyStorage{db: NewDbHelper(db)}, nil
+}
+
+func (storage *ApiKeyStorage) Create(key ApiKey) (ApiKey, error) {
+	if key.Id == uuid.Nil {
+		key.Id = uuid.New()
+	}
+
+	return key, storage.db.Create(&key).Error
+}
+
+func (storage *ApiKeyStorage) FindByHash(keyHash string) (*ApiKey, error) {
+	result := ApiKey{}
+	if err := storage.db.Where("key_hash = ?", keyHash).Take(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+func (storage *ApiKeyStorage) GetAll() ([]ApiKey, error) {
+	result := []ApiKey{}
+	return result, storage.db.Order("lower(username), created_at").Find(&result).Error
+}
+
+func (storage *ApiKeyStorage) MarkUsed(id uuid.UUID, usedAt time.Time) error {
+	return storage.db.Model(&ApiKey{Id: id}).UpdateColumn("last_used_at", usedAt).Error
+}
+
+func (storage *ApiKeyStorage) DeleteById(id uuid.UUID) error {
+	return storage.db.Delete(&ApiKey{Id: id}).Error
+}
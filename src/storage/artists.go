@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// artists are not stored separately, but derived from the track/album artist names instead;
+// artist ID is just an encoded normalized name, so it stays stable as long as the name doesn't change
+func artistIdExpression(field string) string {
+	return fmt.Sprintf("hex(lower(trim(%s)))", field)
+}
+
+type ArtistStorage struct {
+	db *gorm.DB
+}
+
+func NewArtistStorage(db *gorm.DB) (*ArtistStorage, error) {
+	err := db.AutoMigrate()
+	return &ArtistStorage{db: db}, err
+}
+
+func (storage *ArtistStorage) GetSubsonicArtist(username string, id string) (*SubsonicArtistItem, error) {
+	artists, err := storage.getSubsonicArtists(username, 1, 0, fmt.Sprintf("artist_info.id = '%s'", EscapeTextLiteral(id)), "artist_info.id")
+	if err != nil {
+		return nil, err
+	}
+	if len(artists) == 0 {
+		return nil, fmt.Errorf("artist with id %s doesn't exist", id)
+	}
+
+	return &artists[0], nil
+}
+
+func (storage *ArtistStorage) SearchSubsonicArtists(username string, count int, offset int, query string) ([]SubsonicArtistItem, error) {
+	filter := MakeTextSearchCondition([]string{"artist_info.name"}, query)
+	if filter == "" {
+		return []SubsonicArtistItem{}, nil
+	}
+
+	return storage.getSubsonicArtists(username, count, offset, filter, "artist_info.id")
+}
+
+func (storage *ArtistStorage) GetSubsonicArtistsSortId(username string, count int, offset int) ([]SubsonicArtistItem, error) {
+	return storage.getSubsonicArtists(username, count, offset, "", "artist_info.id")
+}
+
+func (storage *ArtistStorage) GetSubsonicArtistsSortName(username string, count int, offset int) ([]SubsonicArtistItem, error) {
+	return storage.getSubsonicArtists(username, count, offset, "", "lower(artist_info.name)")
+}
+
+func (storage *ArtistStorage) GetSubsonicArtistsSortStarred(username string, count int, offset int) ([]SubsonicArtistItem, error) {
+	return storage.getSubsonicArtists(username, count, offset, "starred_items.starred_at IS NOT NULL", "starred_items.starred_at DESC")
+}
+
+func (storage *ArtistStorage) getSubsonicArtists(username string, count int, offset int, filter string, order string) ([]SubsonicArtistItem, error) {
+	query := fmt.Sprintf(
+		`
+			WITH artist_names AS (
+				SELECT
+					%s AS id,
+					trim(tapes.artist) AS name,
+					tapes.id AS album_id,
+					tapes.thumbnail_id AS thumbnail_id,
+					0 AS thumbnail_priority
+				FROM tapes
+				WHERE tapes.type = '%s' AND trim(tapes.artist) != ''
+				UNION ALL
+				SELECT
+					%s AS id,
+					trim(tracks.artist) AS name,
+					NULL AS album_id,
+					sources.thumbnail_id AS thumbnail_id,
+					1 AS thumbnail_priority
+				FROM tracks
+				JOIN sources ON sources.id = tracks.source_id
+				WHERE trim(tracks.artist) != ''
+			),
+			artist_info AS (
+				SELECT
+					artist_names.id AS id,
+					min(artist_names.name) AS name,
+					count(DISTINCT artist_names.album_id) AS album_count
+				FROM artist_names
+				GROUP BY artist_names.id
+			),
+			artist_thumbnails AS (
+				SELECT
+					artist_names.id AS id,
+					artist_names.thumbnail_id AS thumbnail_id,
+					row_number() OVER (PARTITION BY artist_names.id ORDER BY artist_names.thumbnail_priority ASC) AS rank
+				FROM artist_names
+				WHERE artist_names.thumbnail_id IS NOT NULL
+			)
+			SELECT
+				artist_info.id AS id,
+				artist_info.name AS name,
+				artist_info.album_count AS album_count,
+				artist_thumbnails.thumbnail_id AS thumbnail_id,
+				starred_items.starred_at AS starred_at
+			FROM artist_info
+			LEFT JOIN artist_thumbnails ON artist_thumbnails.id = artist_info.id AND artist_thumbnails.rank = 1
+			LEFT JOIN starred_items ON starred_items.username = '%s' AND starred_items.item_type = '%s' AND starred_items.item_id = artist_info.id
+		`,
+		artistIdExpression("tapes.artist"),
+		TAPE_TYPE_ALBUM,
+		artistIdExpression("tracks.artist"),
+		EscapeTextLiteral(username),
+		STARRED_ITEM_TYPE_ARTIST,
+	)
+
+	if filter != "" {
+		query += fmt.Sprintf("\nWHERE %s", filter)
+	}
+
+	if order != "" {
+		query += fmt.Sprintf("\nORDER BY %s", order)
+	}
+
+	query += fmt.Sprintf("\nLIMIT %d OFFSET %d", count, offset)
+
+	result := []SubsonicArtistItem{}
+	return result, storage.db.Raw(query).Find(&result).Error
+}
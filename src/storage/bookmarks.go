@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Bookmark struct {
+	Username string    `gorm:"primaryKey"`
+	TrackId  uuid.UUID `gorm:"primaryKey"`
+
+	// relative to the start of the source rather than the track, so the bookmark stays
+	// at the same moment of the recording when the track boundaries get edited
+	SourcePositionMs int64
+	Comment          string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type BookmarkItem struct {
+	Bookmark
+
+	StartOffsetMs int64
+	EndOffsetMs   int64
+}
+
+type BookmarkStorage struct {
+	db *DbHelper
+}
+
+func NewBookmarkStorage(db *gorm.DB) (*BookmarkStorage, error) {
+	if err := db.AutoMigrate(&Bookmark{}); err != nil {
+		return nil, err
+	}
+
+	return &BookmarkStorage{db: NewDbHelper(db)}, nil
+}
+
+func (storage *BookmarkStorage) Upsert(bookmark Bookmark) error {
+	return storage.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "username"}, {Name: "track_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"source_position_ms", "comment", "updated_at"}),
+	}).Create(&bookmark).Error
+}
+
+// GetAllByUsername skips bookmarks of tracks that don't exist anymore
+func (storage *BookmarkStorage) GetAllByUsername(username string) ([]BookmarkItem, error) {
+	result := []BookmarkItem{}
+	return result, storage.db.Raw(
+		`
+			SELECT bookmarks.*, tracks.start_offset_ms, tracks.end_offset_ms
+			FROM bookmarks
+			JOIN tracks ON tracks.id = bookmarks.track_id
+			WHERE bookmarks.username = ?
+			ORDER BY bookmarks.updated_at DESC
+		`,
+		username,
+	).Scan(&result).Error
+}
+
+func (storage *BookmarkStorage) Delete(username string, trackId uuid.UUID) error {
+	return storage.db.Where("username = ? AND track_id = ?", username, trackId).Delete(&Bookmark{}).Error
+}
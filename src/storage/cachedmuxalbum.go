@@ -43,9 +43,9 @@ func (storage *CachedMuxAlbumStorage) Save(item CachedMuxAlbum) (*CachedMuxAlbum
 	return &item, storage.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&item).Error
 }
 
-func (storage *CachedMuxAlbumStorage) Replace(items []CachedMuxAlbum) error {
+func (storage *CachedMuxAlbumStorage) Replace(serviceName string, items []CachedMuxAlbum) error {
 	return storage.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&CachedMuxAlbum{}).Error; err != nil {
+		if err := tx.Where("service_name = ?", serviceName).Delete(&CachedMuxAlbum{}).Error; err != nil {
 			return err
 		}
 
@@ -59,6 +59,11 @@ func (storage *CachedMuxAlbumStorage) Replace(items []CachedMuxAlbum) error {
 	})
 }
 
+// DeleteOtherServices removes items of services which aren't configured anymore
+func (storage *CachedMuxAlbumStorage) DeleteOtherServices(serviceNames []string) error {
+	return storage.db.Where("service_name NOT IN ?", serviceNames).Delete(&CachedMuxAlbum{}).Error
+}
+
 func (storage *CachedMuxAlbumStorage) Search(query string, count int, offset int) ([]CachedAlbumId, error) {
 	result := []CachedAlbumId{}
 
@@ -40,9 +40,9 @@ func (storage *CachedMuxArtistStorage) Save(item CachedMuxArtist) (*CachedMuxArt
 	return &item, storage.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&item).Error
 }
 
-func (storage *CachedMuxArtistStorage) Replace(items []CachedMuxArtist) error {
+func (storage *CachedMuxArtistStorage) Replace(serviceName string, items []CachedMuxArtist) error {
 	return storage.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&CachedMuxArtist{}).Error; err != nil {
+		if err := tx.Where("service_name = ?", serviceName).Delete(&CachedMuxArtist{}).Error; err != nil {
 			return err
 		}
 
@@ -56,6 +56,11 @@ func (storage *CachedMuxArtistStorage) Replace(items []CachedMuxArtist) error {
 	})
 }
 
+// DeleteOtherServices removes items of services which aren't configured anymore
+func (storage *CachedMuxArtistStorage) DeleteOtherServices(serviceNames []string) error {
+	return storage.db.Where("service_name NOT IN ?", serviceNames).Delete(&CachedMuxArtist{}).Error
+}
+
 func (storage *CachedMuxArtistStorage) Search(query string, count int, offset int) ([]CachedArtistId, error) {
 	result := []CachedArtistId{}
 
@@ -81,3 +86,14 @@ func (storage *CachedMuxArtistStorage) Search(query string, count int, offset in
 
 	return result, storage.db.Raw(sql).Find(&result).Error
 }
+
+func (storage *CachedMuxArtistStorage) FindByName(name string) ([]CachedMuxArtist, error) {
+	result := []CachedMuxArtist{}
+
+	searchName := strings.Join(ExtractSearchTerms(name), " ")
+	if searchName == "" {
+		return result, nil
+	}
+
+	return result, storage.db.Where("search_name = ?", searchName).Order("service_name, artist_id").Find(&result).Error
+}
@@ -51,9 +51,9 @@ func (storage *CachedMuxSongStorage) Save(item CachedMuxSong) (CachedMuxSong, er
 	return item, storage.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&item).Error
 }
 
-func (storage *CachedMuxSongStorage) Replace(items []CachedMuxSong) error {
+func (storage *CachedMuxSongStorage) Replace(serviceName string, items []CachedMuxSong) error {
 	return storage.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&CachedMuxSong{}).Error; err != nil {
+		if err := tx.Where("service_name = ?", serviceName).Delete(&CachedMuxSong{}).Error; err != nil {
 			return err
 		}
 
@@ -67,6 +67,11 @@ func (storage *CachedMuxSongStorage) Replace(items []CachedMuxSong) error {
 	})
 }
 
+// DeleteOtherServices removes items of services which aren't configured anymore
+func (storage *CachedMuxSongStorage) DeleteOtherServices(serviceNames []string) error {
+	return storage.db.Where("service_name NOT IN ?", serviceNames).Delete(&CachedMuxSong{}).Error
+}
+
 func (storage *CachedMuxSongStorage) GetById(serviceName string, songId string) (*CachedMuxSong, error) {
 	result := CachedMuxSong{
 		ServiceName: serviceName,
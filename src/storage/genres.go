@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// genres are stored as json arrays on sources and tapes; tape genres take precedence over the source ones
+func genresExpression(primary string, fallback string) string {
+	return fmt.Sprintf("CASE WHEN json_array_length(coalesce(%s, '[]')) > 0 THEN %s ELSE %s END", primary, primary, fallback)
+}
+
+func genreCondition(field string, genre string) string {
+	return fmt.Sprintf(
+		"EXISTS (SELECT 1 FROM json_each(%s) WHERE json_each.type = 'text' AND lower(json_each.value) = lower('%s'))",
+		field,
+		EscapeTextLiteral(genre),
+	)
+}
+
+type GenreStorage struct {
+	db *gorm.DB
+}
+
+func NewGenreStorage(db *gorm.DB) (*GenreStorage, error) {
+	err := db.AutoMigrate()
+	return &GenreStorage{db: db}, err
+}
+
+func (storage *GenreStorage) GetSubsonicGenres() ([]SubsonicGenreItem, error) {
+	query := fmt.Sprintf(
+		`
+			WITH genre_counts AS (
+				SELECT
+					song_genres.value AS value,
+					1 AS song_count,
+					0 AS album_count
+				FROM (%s) songs, json_each(songs.genres) song_genres
+				WHERE song_genres.type = 'text'
+				UNION ALL
+				SELECT
+					album_genres.value AS value,
+					0 AS song_count,
+					1 AS album_count
+				FROM (%s) albums, json_each(albums.genres) album_genres
+				WHERE album_genres.type = 'text'
+			)
+			SELECT
+				min(genre_counts.value) AS value,
+				sum(genre_counts.song_count) AS song_count,
+				sum(genre_counts.album_count) AS album_count
+			FROM genre_counts
+			GROUP BY lower(genre_counts.value)
+			ORDER BY lower(genre_counts.value)
+		`,
+		subsonicTracksQuery("", ""),
+		subsonicAlbumsQuery("", ""),
+	)
+
+	result := []SubsonicGenreItem{}
+	return result, storage.db.Raw(query).Find(&result).Error
+}
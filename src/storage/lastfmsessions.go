@@ -9,6 +9,10 @@ import (
 )
 
 type LastFmSession struct {
+	// tapesonic user the session belongs to, unique index is created by Migrate
+	// since sqlite can't add a unique column to an existing table
+	Owner string
+
 	SessionKey string
 	Username   string `gorm:"uniqueIndex"`
 
@@ -16,7 +20,6 @@ type LastFmSession struct {
 	UpdatedAt time.Time
 }
 
-// todo: this will get deleted when tapesonic becomes multi-user
 type LastFmSessionStorage struct {
 	db *DbHelper
 }
@@ -30,11 +33,16 @@ func (storage *LastFmSessionStorage) Save(session LastFmSession) (LastFmSession,
 	return session, storage.db.Clauses(clause.OnConflict{UpdateAll: true}, clause.Returning{}).Create(&session).Error
 }
 
-func (storage *LastFmSessionStorage) Find() (*LastFmSession, error) {
+func (storage *LastFmSessionStorage) Find(owner string) (*LastFmSession, error) {
 	result := LastFmSession{}
-	err := storage.db.Order("updated_at DESC").First(&result).Error
+	err := storage.db.Where("owner = ?", owner).Take(&result).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
 	return &result, err
 }
+
+func (storage *LastFmSessionStorage) GetAll() ([]LastFmSession, error) {
+	result := []LastFmSession{}
+	return result, storage.db.Order("owner").Find(&result).Error
+}
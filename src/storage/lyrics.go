@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TrackLyrics struct {
+	TrackId uuid.UUID `gorm:"primaryKey"`
+
+	// lyrics are sliced from the subtitles of the whole source, so they have to be refetched once the track bounds change
+	StartOffsetMs int64
+	EndOffsetMs   int64
+
+	Language string
+	Lines    []LyricsLine `gorm:"serializer:json"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type LyricsLine struct {
+	StartMs int64
+	Text    string
+}
+
+type LyricsStorage struct {
+	db *DbHelper
+}
+
+func NewLyricsStorage(db *gorm.DB) (*LyricsStorage, error) {
+	if err := db.AutoMigrate(&TrackLyrics{}); err != nil {
+		return nil, err
+	}
+
+	return &LyricsStorage{db: NewDbHelper(db)}, nil
+}
+
+func (storage *LyricsStorage) Upsert(lyrics []TrackLyrics) error {
+	if len(lyrics) == 0 {
+		return nil
+	}
+
+	return storage.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&lyrics).Error
+}
+
+func (storage *LyricsStorage) Find(trackId uuid.UUID) (*TrackLyrics, error) {
+	result := TrackLyrics{}
+	if err := storage.db.Where("track_id = ?", trackId).Take(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
@@ -83,3 +83,35 @@ func (ms *MediaStorage) GetTrackSources(trackId uuid.UUID) (TrackSourceDescripto
 
 	return sourceDescriptor, nil
 }
+
+// podcast episodes aren't tracks, but are streamed the same way as a track covering the whole source
+func (ms *MediaStorage) GetPodcastEpisodeSources(episodeId uuid.UUID) (TrackSourceDescriptor, error) {
+	query := fmt.Sprintf(
+		`
+			SELECT
+				source_files.media_path AS local_path,
+				source_files.format AS local_format,
+				source_files.codec AS local_codec,
+				sources.url AS remote_url,
+				sources.duration_ms AS source_duration_ms,
+				0 AS start_offset_ms,
+				sources.duration_ms AS end_offset_ms
+			FROM podcast_episodes
+			JOIN sources ON sources.id = podcast_episodes.source_id
+			LEFT JOIN source_files ON source_files.source_id = sources.id
+			WHERE podcast_episodes.id = '%s'
+		`,
+		episodeId.String(),
+	)
+
+	sourceDescriptor := TrackSourceDescriptor{}
+	if err := ms.db.Raw(query).Find(&sourceDescriptor).Error; err != nil {
+		return sourceDescriptor, err
+	}
+
+	if sourceDescriptor.LocalPath != "" {
+		sourceDescriptor.LocalPath = path.Join(ms.dir, sourceDescriptor.LocalPath)
+	}
+
+	return sourceDescriptor, nil
+}
@@ -1,9 +1,135 @@
 package storage
 
 import (
+	"fmt"
+	"log/slog"
+	"strings"
+
 	"gorm.io/gorm"
 )
 
-func Migrate(db *gorm.DB) error {
-	return nil
+// Migrate moves data created by earlier versions to the current schema, it's run after all storages got auto-migrated
+func Migrate(db *gorm.DB, defaultUsername string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return migrateToMultiUser(tx, defaultUsername)
+	})
+}
+
+// before tapesonic supported multiple users, listens, stars, ratings, playlists and last.fm sessions were global,
+// they are given to the configured user
+func migrateToMultiUser(tx *gorm.DB, defaultUsername string) error {
+	for _, index := range []string{"idx_track_listens_track_id", "idx_starred_items_item", "idx_ratings_item"} {
+		if err := tx.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %s", index)).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"muxed_ratings", "muxed_song_listens"} {
+		if err := rebuildWithUsernameKey(tx, table, defaultUsername); err != nil {
+			return err
+		}
+	}
+
+	if defaultUsername != "" {
+		for _, table := range []string{"track_listens", "starred_items", "ratings"} {
+			if err := tx.Exec(fmt.Sprintf("UPDATE %s SET username = ? WHERE username IS NULL OR username = ''", table), defaultUsername).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(
+			"UPDATE tapes SET owner = ? WHERE type = ? AND (owner IS NULL OR owner = '')",
+			defaultUsername,
+			TAPE_TYPE_PLAYLIST,
+		).Error; err != nil {
+			return err
+		}
+
+		// only the latest session was ever used, the rest are unreachable
+		if err := tx.Exec(
+			`
+				UPDATE last_fm_sessions SET owner = ?
+				WHERE rowid = (
+					SELECT rowid FROM last_fm_sessions
+					WHERE NOT EXISTS (SELECT 1 FROM last_fm_sessions WHERE owner = ?)
+					ORDER BY updated_at DESC
+					LIMIT 1
+				)
+			`,
+			defaultUsername,
+			defaultUsername,
+		).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM last_fm_sessions WHERE owner IS NULL OR owner = ''").Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_last_fm_sessions_owner ON last_fm_sessions(owner)").Error
+}
+
+// sqlite can't change the primary key of an existing table, so the table is recreated
+func rebuildWithUsernameKey(tx *gorm.DB, table string, defaultUsername string) error {
+	type columnInfo struct {
+		Name string
+		Pk   int
+	}
+
+	columns := []columnInfo{}
+	if err := tx.Raw(fmt.Sprintf("PRAGMA table_info(%s)", table)).Scan(&columns).Error; err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, column := range columns {
+		if column.Name == "username" && column.Pk > 0 {
+			return nil
+		}
+		if column.Name != "username" {
+			names = append(names, column.Name)
+		}
+	}
+
+	slog.Info(fmt.Sprintf("Migrating %s to per-user primary key", table))
+
+	columnList := strings.Join(names, ", ")
+
+	var model any
+	switch table {
+	case "muxed_ratings":
+		model = &MuxedRating{}
+	case "muxed_song_listens":
+		model = &MuxedSongListens{}
+	default:
+		return fmt.Errorf("unsupported table %s", table)
+	}
+
+	if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s RENAME TO %s_old", table, table)).Error; err != nil {
+		return err
+	}
+
+	// indexes keep their names after the rename and would clash with the recreated table
+	indexes := []string{}
+	if err := tx.Raw("SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", table+"_old").Scan(&indexes).Error; err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if err := tx.Exec(fmt.Sprintf("DROP INDEX %s", index)).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Migrator().CreateTable(model); err != nil {
+		return err
+	}
+
+	if err := tx.Exec(
+		fmt.Sprintf("INSERT INTO %s (username, %s) SELECT ?, %s FROM %s_old", table, columnList, columnList, table),
+		defaultUsername,
+	).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(fmt.Sprintf("DROP TABLE %s_old", table)).Error
 }
@@ -32,11 +32,23 @@ type SourceForHierarchy struct {
 	ThumbnailId *uuid.UUID
 }
 
+type SourceForDirectory struct {
+	Id uuid.UUID
+
+	Title    string
+	Uploader string
+
+	ThumbnailId *uuid.UUID
+
+	IsPlaylist bool
+}
+
 type SubsonicAlbumItem struct {
 	Id string
 
 	Name        string
 	Artist      string
+	ArtistId    string
 	ReleaseDate *time.Time
 
 	ThumbnailId *uuid.UUID
@@ -47,12 +59,30 @@ type SubsonicAlbumItem struct {
 	SongCount   int
 	DurationSec int
 	PlayCount   int
+
+	Genres []string `gorm:"serializer:json"`
+
+	StarredAt     *time.Time
+	UserRating    int
+	AverageRating float64
+}
+
+type SubsonicArtistItem struct {
+	Id   string
+	Name string
+
+	ThumbnailId *uuid.UUID
+
+	AlbumCount int
+
+	StarredAt *time.Time
 }
 
 type SubsonicPlaylistItem struct {
 	Id string
 
 	CreatedBy string
+	Owner     string
 
 	Name   string
 	Artist string
@@ -76,12 +106,27 @@ type SubsonicTrackItem struct {
 	AlbumTrackIndex    int
 	PlaylistTrackIndex int
 
-	Album  string
-	Artist string
-	Title  string
+	Album    string
+	Artist   string
+	ArtistId string
+	Title    string
 
-	DurationSec int
-	PlayCount   int
+	DurationSec  int
+	PlayCount    int
+	LastPlayedAt *time.Time
+
+	Genres []string `gorm:"serializer:json"`
+
+	StarredAt     *time.Time
+	UserRating    int
+	AverageRating float64
+}
+
+type SubsonicGenreItem struct {
+	Value string
+
+	SongCount  int
+	AlbumCount int
 }
 
 type CachedArtistId struct {
@@ -104,3 +149,21 @@ type CachedSongIdWithIndex struct {
 
 	TrackIndex int
 }
+
+type SubsonicPodcastEpisodeItem struct {
+	Id        string
+	ChannelId string
+
+	ChannelTitle string
+	Title        string
+	Description  string
+	Uploader     string
+
+	DurationSec int
+	PublishedAt time.Time
+
+	ThumbnailId *uuid.UUID
+
+	Downloaded     bool
+	DownloadStatus string
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// proxied servers are accessed with a single account shared by all users, so their bookmarks are kept here for each user
+type MuxedBookmarkStorage struct {
+	db *DbHelper
+}
+
+type MuxedBookmark struct {
+	Username    string `gorm:"primaryKey"`
+	ServiceName string `gorm:"primaryKey"`
+	ItemId      string `gorm:"primaryKey"`
+
+	PositionMs int64
+	Comment    string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewMuxedBookmarkStorage(db *gorm.DB) (*MuxedBookmarkStorage, error) {
+	err := db.AutoMigrate(
+		&MuxedBookmark{},
+	)
+	return &MuxedBookmarkStorage{db: NewDbHelper(db)}, err
+}
+
+func (storage *MuxedBookmarkStorage) Upsert(bookmark MuxedBookmark) error {
+	return storage.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "username"}, {Name: "service_name"}, {Name: "item_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position_ms", "comment", "updated_at"}),
+	}).Create(&bookmark).Error
+}
+
+func (storage *MuxedBookmarkStorage) GetAllByUsername(username string, serviceName string) ([]MuxedBookmark, error) {
+	result := []MuxedBookmark{}
+	return result, storage.db.Where("username = ? AND service_name = ?", username, serviceName).Order("updated_at DESC").Find(&result).Error
+}
+
+func (storage *MuxedBookmarkStorage) Delete(username string, serviceName string, itemId string) error {
+	return storage.db.Where("username = ? AND service_name = ? AND item_id = ?", username, serviceName, itemId).Delete(&MuxedBookmark{}).Error
+}
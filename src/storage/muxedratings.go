@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MuxedRatingStorage struct {
+	db *DbHelper
+}
+
+type MuxedRating struct {
+	Username    string `gorm:"primaryKey"`
+	ServiceName string `gorm:"primaryKey"`
+	ItemId      string `gorm:"primaryKey"`
+
+	Rating int
+
+	RatedAt time.Time
+}
+
+func NewMuxedRatingStorage(db *gorm.DB) (*MuxedRatingStorage, error) {
+	err := db.AutoMigrate(
+		&MuxedRating{},
+	)
+	return &MuxedRatingStorage{db: NewDbHelper(db)}, err
+}
+
+// rating of 0 removes the rating completely
+func (storage *MuxedRatingStorage) Record(username string, serviceName string, itemId string, rating int, ratedAt time.Time) error {
+	if rating == 0 {
+		return storage.db.Delete(&MuxedRating{Username: username, ServiceName: serviceName, ItemId: itemId}).Error
+	}
+
+	item := MuxedRating{
+		Username:    username,
+		ServiceName: serviceName,
+		ItemId:      itemId,
+		Rating:      rating,
+		RatedAt:     ratedAt,
+	}
+
+	return storage.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&item).Error
+}
+
+func (storage *MuxedRatingStorage) GetRatings(username string, serviceName string) ([]MuxedRating, error) {
+	result := []MuxedRating{}
+	return result, storage.db.Where("username = ? AND service_name = ?", username, serviceName).Find(&result).Error
+}
+
+func (storage *MuxedRatingStorage) GetHighestRatedAlbums(username string, count int, offset int) ([]CachedAlbumId, error) {
+	query := fmt.Sprintf(
+		`
+			SELECT
+				muxed_ratings.service_name AS service_name,
+				muxed_ratings.item_id AS id
+			FROM muxed_ratings
+			JOIN cached_mux_albums ON cached_mux_albums.service_name = muxed_ratings.service_name AND cached_mux_albums.album_id = muxed_ratings.item_id
+			WHERE muxed_ratings.username = '%s'
+			ORDER BY muxed_ratings.rating DESC, muxed_ratings.rated_at DESC
+			LIMIT %d
+			OFFSET %d
+		`,
+		EscapeTextLiteral(username),
+		count,
+		offset,
+	)
+
+	result := []CachedAlbumId{}
+	return result, storage.db.Raw(query).Find(&result).Error
+}
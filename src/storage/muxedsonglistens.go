@@ -12,6 +12,7 @@ type MuxedSongListensStorage struct {
 }
 
 type MuxedSongListens struct {
+	Username    string `gorm:"primaryKey"`
 	ServiceName string `gorm:"primaryKey"`
 	SongId      string `gorm:"primaryKey"`
 
@@ -27,13 +28,14 @@ func NewMuxedSongListensStorage(db *gorm.DB) (*MuxedSongListensStorage, error) {
 	return &MuxedSongListensStorage{db: NewDbHelper(db)}, err
 }
 
-func (storage *MuxedSongListensStorage) Record(serviceName string, songId string, listenedAt time.Time, incrementListenCount bool) error {
+func (storage *MuxedSongListensStorage) Record(username string, serviceName string, songId string, listenedAt time.Time, incrementListenCount bool) error {
 	return storage.db.ExclusiveTransaction(func(tx *gorm.DB) error {
 		item := MuxedSongListens{}
-		if err := tx.Where(&MuxedSongListens{ServiceName: serviceName, SongId: songId}).Find(&item).Error; err != nil {
+		if err := tx.Where(&MuxedSongListens{Username: username, ServiceName: serviceName, SongId: songId}).Find(&item).Error; err != nil {
 			return err
 		}
 
+		item.Username = username
 		item.ServiceName = serviceName
 		item.SongId = songId
 		if listenedAt.After(item.LastListenedAt) {
@@ -47,16 +49,16 @@ func (storage *MuxedSongListensStorage) Record(serviceName string, songId string
 	})
 }
 
-func (storage *MuxedSongListensStorage) GetRecentAlbumListenStats(count int, offset int) ([]CachedAlbumId, error) {
-	return storage.getAlbumListenStats(count, offset, "album_info.last_listened_at IS NOT NULL", "album_info.last_listened_at DESC")
+func (storage *MuxedSongListensStorage) GetRecentAlbumListenStats(username string, count int, offset int) ([]CachedAlbumId, error) {
+	return storage.getAlbumListenStats(username, count, offset, "album_info.last_listened_at IS NOT NULL", "album_info.last_listened_at DESC")
 }
 
-func (storage *MuxedSongListensStorage) GetFrequentAlbumListenStats(count int, offset int) ([]CachedAlbumId, error) {
-	return storage.getAlbumListenStats(count, offset, "album_info.total_play_time > 0", "album_info.total_play_time DESC")
+func (storage *MuxedSongListensStorage) GetFrequentAlbumListenStats(username string, count int, offset int) ([]CachedAlbumId, error) {
+	return storage.getAlbumListenStats(username, count, offset, "album_info.total_play_time > 0", "album_info.total_play_time DESC")
 }
 
-func (storage *MuxedSongListensStorage) getAlbumListenStats(count int, offset int, filter string, order string) ([]CachedAlbumId, error) {
-	query := `
+func (storage *MuxedSongListensStorage) getAlbumListenStats(username string, count int, offset int, filter string, order string) ([]CachedAlbumId, error) {
+	query := fmt.Sprintf(`
 		WITH album_info AS (
 			SELECT
 				cached_mux_songs.service_name AS service_name,
@@ -65,14 +67,14 @@ func (storage *MuxedSongListensStorage) getAlbumListenStats(count int, offset in
 				sum(muxed_song_listens.listen_count * cached_mux_songs.duration_sec) AS total_play_time
 			FROM muxed_song_listens
 			JOIN cached_mux_songs ON cached_mux_songs.service_name = muxed_song_listens.service_name AND cached_mux_songs.song_id = muxed_song_listens.song_id
-			WHERE cached_mux_songs.album_id != '' AND cached_mux_songs.album_id IS NOT NULL
+			WHERE muxed_song_listens.username = '%s' AND cached_mux_songs.album_id != '' AND cached_mux_songs.album_id IS NOT NULL
 			GROUP BY cached_mux_songs.service_name, cached_mux_songs.album_id
 		)
 		SELECT
 			album_info.service_name AS service_name,
 			album_info.album_id AS id
 		FROM album_info
-	`
+	`, EscapeTextLiteral(username))
 
 	if filter != "" {
 		query += fmt.Sprintf(" WHERE %s", filter)
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// proxied servers are accessed with a single account shared by all users, so their stars are kept here for each user
+type MuxedStarredItemStorage struct {
+	db *DbHelper
+}
+
+type MuxedStarredItem struct {
+	Username    string `gorm:"primaryKey"`
+	ServiceName string `gorm:"primaryKey"`
+	ItemType    string `gorm:"primaryKey"`
+	ItemId      string `gorm:"primaryKey"`
+
+	StarredAt time.Time
+}
+
+func NewMuxedStarredItemStorage(db *gorm.DB) (*MuxedStarredItemStorage, error) {
+	err := db.AutoMigrate(
+		&MuxedStarredItem{},
+	)
+	return &MuxedStarredItemStorage{db: NewDbHelper(db)}, err
+}
+
+func (storage *MuxedStarredItemStorage) Star(username string, serviceName string, itemType string, itemIds []string, starredAt time.Time) error {
+	if len(itemIds) == 0 {
+		return nil
+	}
+
+	items := []MuxedStarredItem{}
+	for _, itemId := range itemIds {
+		items = append(items, MuxedStarredItem{
+			Username:    username,
+			ServiceName: serviceName,
+			ItemType:    itemType,
+			ItemId:      itemId,
+			StarredAt:   starredAt,
+		})
+	}
+
+	return storage.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&items).Error
+}
+
+func (storage *MuxedStarredItemStorage) Unstar(username string, serviceName string, itemType string, itemIds []string) error {
+	if len(itemIds) == 0 {
+		return nil
+	}
+
+	return storage.db.
+		Where("username = ? AND service_name = ? AND item_type = ? AND item_id IN ?", username, serviceName, itemType, itemIds).
+		Delete(&MuxedStarredItem{}).
+		Error
+}
+
+func (storage *MuxedStarredItemStorage) GetStarredItems(username string, serviceName string) ([]MuxedStarredItem, error) {
+	result := []MuxedStarredItem{}
+	return result, storage.db.Where("username = ? AND service_name = ?", username, serviceName).Order("starred_at DESC").Find(&result).Error
+}
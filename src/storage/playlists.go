@@ -17,8 +17,8 @@ func NewPlaylistStorage(db *gorm.DB) (*PlaylistStorage, error) {
 	return &PlaylistStorage{db: db}, err
 }
 
-func (storage *PlaylistStorage) GetSubsonicPlaylist(id uuid.UUID) (*SubsonicPlaylistItem, error) {
-	playlists, err := storage.getSubsonicPlaylists(1, 0, fmt.Sprintf("tapes.id = '%s'", id.String()), "tapes.id")
+func (storage *PlaylistStorage) GetSubsonicPlaylist(username string, id uuid.UUID) (*SubsonicPlaylistItem, error) {
+	playlists, err := storage.getSubsonicPlaylists(username, 1, 0, fmt.Sprintf("tapes.id = '%s'", id.String()), "tapes.id")
 	if err != nil {
 		return nil, err
 	}
@@ -29,11 +29,12 @@ func (storage *PlaylistStorage) GetSubsonicPlaylist(id uuid.UUID) (*SubsonicPlay
 	return &playlists[0], nil
 }
 
-func (storage *PlaylistStorage) GetSubsonicPlaylists(count int, offset int) ([]SubsonicPlaylistItem, error) {
-	return storage.getSubsonicPlaylists(count, offset, "", "tapes.updated_at DESC")
+func (storage *PlaylistStorage) GetSubsonicPlaylists(username string, count int, offset int) ([]SubsonicPlaylistItem, error) {
+	return storage.getSubsonicPlaylists(username, count, offset, "", "tapes.updated_at DESC")
 }
 
-func (storage *PlaylistStorage) getSubsonicPlaylists(count int, offset int, filter string, order string) ([]SubsonicPlaylistItem, error) {
+// users only see shared playlists and their own ones
+func (storage *PlaylistStorage) getSubsonicPlaylists(username string, count int, offset int, filter string, order string) ([]SubsonicPlaylistItem, error) {
 	query := `
 		WITH playlist_extra_info AS (
 			SELECT
@@ -51,6 +52,7 @@ func (storage *PlaylistStorage) getSubsonicPlaylists(count int, offset int, filt
 
 	conditions := []string{
 		fmt.Sprintf("tapes.type = '%s'", TAPE_TYPE_PLAYLIST),
+		fmt.Sprintf("(tapes.owner IS NULL OR tapes.owner = '' OR tapes.owner = '%s')", EscapeTextLiteral(username)),
 	}
 
 	if filter != "" {
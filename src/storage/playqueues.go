@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlayQueue struct {
+	Username string `gorm:"primaryKey"`
+
+	// prefixed ids from the muxed service, so songs of the proxied server can be queued as well
+	SongIds    []string `gorm:"serializer:json"`
+	CurrentId  string
+	PositionMs int64
+
+	ChangedBy string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PlayQueueStorage struct {
+	db *DbHelper
+}
+
+func NewPlayQueueStorage(db *gorm.DB) (*PlayQueueStorage, error) {
+	if err := db.AutoMigrate(&PlayQueue{}); err != nil {
+		return nil, err
+	}
+
+	return &PlayQueueStorage{db: NewDbHelper(db)}, nil
+}
+
+func (storage *PlayQueueStorage) Upsert(queue PlayQueue) error {
+	return storage.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&queue).Error
+}
+
+func (storage *PlayQueueStorage) Find(username string) (*PlayQueue, error) {
+	result := PlayQueue{}
+	if err := storage.db.Where("username = ?", username).Take(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+func (storage *PlayQueueStorage) Delete(username string) error {
+	return storage.db.Where("username = ?", username).Delete(&PlayQueue{}).Error
+}
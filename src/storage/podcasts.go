@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PODCAST_STATUS_NEW         = "new"
+	PODCAST_STATUS_DOWNLOADING = "downloading"
+	PODCAST_STATUS_COMPLETED   = "completed"
+	PODCAST_STATUS_ERROR       = "error"
+)
+
+type PodcastChannel struct {
+	Id uuid.UUID
+
+	Url string `gorm:"uniqueIndex"`
+
+	Title       string
+	Description string
+
+	ThumbnailUrl string
+	ThumbnailId  *uuid.UUID
+	Thumbnail    *Thumbnail
+
+	Status       string
+	ErrorMessage string
+
+	RefreshedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PodcastEpisode struct {
+	Id uuid.UUID
+
+	ChannelId uuid.UUID `gorm:"uniqueIndex:idx_podcast_episodes_source"`
+	Channel   *PodcastChannel
+
+	SourceId uuid.UUID `gorm:"uniqueIndex:idx_podcast_episodes_source"`
+	Source   *Source
+
+	Title       string
+	Description string
+	DurationMs  int64
+
+	PublishedAt time.Time
+
+	// only tracks download attempts, downloaded files are looked up in source_files
+	DownloadStatus string
+	DownloadError  string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PodcastStorage struct {
+	db *DbHelper
+}
+
+func NewPodcastStorage(db *gorm.DB) (*PodcastStorage, error) {
+	if err := db.AutoMigrate(&PodcastChannel{}, &PodcastEpisode{}); err != nil {
+		return nil, err
+	}
+
+	return &PodcastStorage{db: NewDbHelper(db)}, nil
+}
+
+func (storage *PodcastStorage) CreateChannel(channel PodcastChannel) (PodcastChannel, error) {
+	if channel.Id == uuid.Nil {
+		channel.Id = uuid.New()
+	}
+
+	return channel, storage.db.Create(&channel).Error
+}
+
+func (storage *PodcastStorage) UpdateChannel(channel PodcastChannel) error {
+	return storage.db.Save(&channel).Error
+}
+
+func (storage *PodcastStorage) GetChannel(id uuid.UUID) (PodcastChannel, error) {
+	result := PodcastChannel{Id: id}
+	return result, storage.db.Take(&result).Error
+}
+
+func (storage *PodcastStorage) FindChannelByUrl(url string) (*PodcastChannel, error) {
+	result := PodcastChannel{}
+	if err := storage.db.Where("url = ?", url).Take(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+func (storage *PodcastStorage) GetChannels() ([]PodcastChannel, error) {
+	result := []PodcastChannel{}
+	return result, storage.db.Order("lower(title), id").Find(&result).Error
+}
+
+func (storage *PodcastStorage) DeleteChannel(id uuid.UUID) error {
+	return storage.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("channel_id = ?", id).Delete(&PodcastEpisode{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&PodcastChannel{Id: id}).Error
+	})
+}
+
+func (storage *PodcastStorage) CreateEpisode(episode PodcastEpisode) (PodcastEpisode, error) {
+	if episode.Id == uuid.Nil {
+		episode.Id = uuid.New()
+	}
+
+	return episode, storage.db.Create(&episode).Error
+}
+
+func (storage *PodcastStorage) GetEpisode(id uuid.UUID) (PodcastEpisode, error) {
+	result := PodcastEpisode{Id: id}
+	return result, storage.db.Take(&result).Error
+}
+
+func (storage *PodcastStorage) GetEpisodesByChannel(channelId uuid.UUID) ([]PodcastEpisode, error) {
+	result := []PodcastEpisode{}
+	return result, storage.db.Preload("Source").Where("channel_id = ?", channelId).Find(&result).Error
+}
+
+func (storage *PodcastStorage) SetEpisodeDownloadStatus(id uuid.UUID, status string, errorMessage string) error {
+	return storage.db.Exec(
+		"UPDATE podcast_episodes SET download_status = ?, download_error = ?, updated_at = ? WHERE id = ?",
+		status,
+		errorMessage,
+		time.Now(),
+		id,
+	).Error
+}
+
+func (storage *PodcastStorage) GetSubsonicEpisodesByChannel(channelId uuid.UUID) ([]SubsonicPodcastEpisodeItem, error) {
+	return storage.getSubsonicEpisodes(fmt.Sprintf("podcast_episodes.channel_id = '%s'", channelId), -1)
+}
+
+func (storage *PodcastStorage) GetSubsonicNewestEpisodes(count int) ([]SubsonicPodcastEpisodeItem, error) {
+	return storage.getSubsonicEpisodes("1 = 1", count)
+}
+
+func (storage *PodcastStorage) getSubsonicEpisodes(filter string, limit int) ([]SubsonicPodcastEpisodeItem, error) {
+	query := fmt.Sprintf(
+		`
+			SELECT
+				podcast_episodes.id AS id,
+				podcast_episodes.channel_id AS channel_id,
+				podcast_channels.title AS channel_title,
+				podcast_episodes.title AS title,
+				podcast_episodes.description AS description,
+				sources.uploader AS uploader,
+				podcast_episodes.duration_ms / 1000 AS duration_sec,
+				podcast_episodes.published_at AS published_at,
+				coalesce(sources.thumbnail_id, podcast_channels.thumbnail_id) AS thumbnail_id,
+				source_files.id IS NOT NULL AS downloaded,
+				podcast_episodes.download_status AS download_status
+			FROM podcast_episodes
+			JOIN podcast_channels ON podcast_channels.id = podcast_episodes.channel_id
+			JOIN sources ON sources.id = podcast_episodes.source_id
+			LEFT JOIN source_files ON source_files.source_id = podcast_episodes.source_id
+			WHERE %s
+			ORDER BY podcast_episodes.published_at DESC, podcast_episodes.id
+			LIMIT %d
+		`,
+		filter,
+		limit,
+	)
+
+	result := []SubsonicPodcastEpisodeItem{}
+	return result, storage.db.Raw(query).Find(&result).Error
+}
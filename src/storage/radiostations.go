@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RadioStation struct {
+	Id uuid.UUID
+
+	Name        string
+	Url         string
+	HomepageUrl string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RadioStationStorage struct {
+	db *DbHelper
+}
+
+func NewRadioStationStorage(db *gorm.DB) (*RadioStationStorage, error) {
+	if err := db.AutoMigrate(&RadioStation{}); err != nil {
+		return nil, err
+	}
+
+	return &RadioStationStorage{db: NewDbHelper(db)}, nil
+}
+
+func (storage *RadioStationStorage) Create(station RadioStation) (RadioStation, error) {
+	if station.Id == uuid.Nil {
+		station.Id = uuid.New()
+	}
+
+	return station, storage.db.Create(&station).Error
+}
+
+func (storage *RadioStationStorage) Update(station RadioStation) error {
+	return storage.db.Model(&station).Select("name", "url", "homepage_url").Updates(&station).Error
+}
+
+func (storage *RadioStationStorage) GetById(id uuid.UUID) (RadioStation, error) {
+	result := RadioStation{Id: id}
+	return result, storage.db.Take(&result).Error
+}
+
+func (storage *RadioStationStorage) GetAll() ([]RadioStation, error) {
+	result := []RadioStation{}
+	return result, storage.db.Order("lower(name), id").Find(&result).Error
+}
+
+func (storage *RadioStationStorage) DeleteById(id uuid.UUID) error {
+	return storage.db.Delete(&RadioStation{Id: id}).Error
+}
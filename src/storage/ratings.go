@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	RATING_ITEM_TYPE_SONG  = "song"
+	RATING_ITEM_TYPE_ALBUM = "album"
+)
+
+type RatingStorage struct {
+	db *DbHelper
+}
+
+type Rating struct {
+	Id int64
+
+	Username string `gorm:"uniqueIndex:idx_ratings_user_item"`
+	ItemType string `gorm:"uniqueIndex:idx_ratings_user_item"`
+	ItemId   string `gorm:"uniqueIndex:idx_ratings_user_item"`
+
+	Rating int
+
+	RatedAt time.Time
+}
+
+func NewRatingStorage(db *gorm.DB) (*RatingStorage, error) {
+	err := db.AutoMigrate(
+		&Rating{},
+	)
+	return &RatingStorage{db: NewDbHelper(db)}, err
+}
+
+// rating of 0 removes the rating completely
+func (storage *RatingStorage) SetRating(username string, itemType string, itemId string, rating int, ratedAt time.Time) error {
+	if rating == 0 {
+		return storage.db.Where("username = ? AND item_type = ? AND item_id = ?", username, itemType, itemId).Delete(&Rating{}).Error
+	}
+
+	item := Rating{
+		Username: username,
+		ItemType: itemType,
+		ItemId:   itemId,
+		Rating:   rating,
+		RatedAt:  ratedAt,
+	}
+
+	return storage.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "username"}, {Name: "item_type"}, {Name: "item_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "rated_at"}),
+	}).Create(&item).Error
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Share struct {
+	Id uuid.UUID
+
+	Username    string `gorm:"index"`
+	Description string
+
+	Items []ShareItem
+
+	ExpiresAt     *time.Time
+	LastVisitedAt *time.Time
+	VisitCount    int
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// exactly one of TapeId and TrackId is set
+type ShareItem struct {
+	ShareId   uuid.UUID `gorm:"primaryKey"`
+	ListIndex int       `gorm:"primaryKey"`
+
+	TapeId  *uuid.UUID
+	TrackId *uuid.UUID
+}
+
+type ShareStorage struct {
+	db *DbHelper
+}
+
+func NewShareStorage(db *gorm.DB) (*ShareStorage, error) {
+	if err := db.AutoMigrate(&Share{}, &ShareItem{}); err != nil {
+		return nil, err
+	}
+
+	return &ShareStorage{db: NewDbHelper(db)}, nil
+}
+
+func (storage *ShareStorage) Create(share Share) (Share, error) {
+	if share.Id == uuid.Nil {
+		share.Id = uuid.New()
+	}
+
+	for i := range share.Items {
+		share.Items[i].ShareId = share.Id
+		share.Items[i].ListIndex = i
+	}
+
+	return share, storage.db.Create(&share).Error
+}
+
+func (storage *ShareStorage) Update(share Share) error {
+	return storage.db.Model(&share).Select("description", "expires_at").Updates(&share).Error
+}
+
+func (storage *ShareStorage) Find(id uuid.UUID) (*Share, error) {
+	result := Share{}
+	err := storage.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("list_index") }).
+		Where("id = ?", id).
+		Take(&result).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+func (storage *ShareStorage) GetAllByUsername(username string) ([]Share, error) {
+	result := []Share{}
+	return result, storage.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("list_index") }).
+		Where("username = ?", username).
+		Order("created_at DESC").
+		Find(&result).
+		Error
+}
+
+// GetTrackIds returns ids of all shared tracks in order, with tapes expanded into their current tracks
+func (storage *ShareStorage) GetTrackIds(id uuid.UUID) ([]uuid.UUID, error) {
+	result := []uuid.UUID{}
+	return result, storage.db.Raw(
+		`
+			SELECT tracks.id FROM share_items
+			LEFT JOIN tape_to_tracks ON tape_to_tracks.tape_id = share_items.tape_id
+			JOIN tracks ON tracks.id = coalesce(share_items.track_id, tape_to_tracks.track_id)
+			WHERE share_items.share_id = ?
+			ORDER BY share_items.list_index, tape_to_tracks.list_index
+		`,
+		id,
+	).Scan(&result).Error
+}
+
+func (storage *ShareStorage) RecordVisit(id uuid.UUID, at time.Time) error {
+	return storage.db.Model(&Share{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"visit_count":     gorm.Expr("visit_count + 1"),
+			"last_visited_at": at,
+		}).
+		Error
+}
+
+func (storage *ShareStorage) DeleteById(id uuid.UUID) error {
+	return storage.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("share_id = ?", id).Delete(&ShareItem{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&Share{Id: id}).Error
+	})
+}
@@ -33,6 +33,10 @@ type Source struct {
 	UploadedAt  time.Time
 	ReleaseDate *time.Time
 
+	Genres []string `gorm:"serializer:json"`
+	// set when genres are edited by hand, so they aren't replaced by tags on refresh
+	GenresEdited bool
+
 	ThumbnailId *uuid.UUID
 	Thumbnail   *Thumbnail
 
@@ -126,6 +130,98 @@ func (storage *SourceStorage) GetHierarchy(id uuid.UUID) ([]SourceForHierarchy,
 	return result, storage.db.Raw(query).Find(&result).Error
 }
 
+// only sources which have tracks or child sources are visible in directories
+const sourceForDirectoryQuery = `
+	SELECT
+		sources.id AS id,
+		sources.title AS title,
+		sources.uploader AS uploader,
+		sources.thumbnail_id AS thumbnail_id,
+		EXISTS (SELECT 1 FROM source_hierarchies WHERE source_hierarchies.parent_id = sources.id) AS is_playlist
+	FROM sources
+	WHERE
+		(
+			EXISTS (SELECT 1 FROM tracks WHERE tracks.source_id = sources.id)
+			OR EXISTS (SELECT 1 FROM source_hierarchies WHERE source_hierarchies.parent_id = sources.id)
+		)
+`
+
+func (storage *SourceStorage) GetUploadersForDirectory() ([]string, error) {
+	query := fmt.Sprintf(
+		`
+			SELECT DISTINCT directory_sources.uploader
+			FROM (%s) directory_sources
+			ORDER BY lower(directory_sources.uploader)
+		`,
+		sourceForDirectoryQuery,
+	)
+
+	result := []string{}
+	return result, storage.db.Raw(query).Scan(&result).Error
+}
+
+// deleted sources and tapes aren't reflected here, which is fine for the index modification time
+func (storage *SourceStorage) GetLastUpdatedAtForDirectory() (*time.Time, error) {
+	var result *time.Time
+	for _, table := range []string{"sources", "tapes"} {
+		// max() would lose the column type, so the timestamp wouldn't be parsed
+		updatedAt := []time.Time{}
+		if err := storage.db.Raw(fmt.Sprintf("SELECT updated_at FROM %s ORDER BY updated_at DESC LIMIT 1", table)).Scan(&updatedAt).Error; err != nil {
+			return nil, err
+		}
+
+		if len(updatedAt) > 0 && (result == nil || updatedAt[0].After(*result)) {
+			result = &updatedAt[0]
+		}
+	}
+
+	return result, nil
+}
+
+func (storage *SourceStorage) GetForDirectoryByUploader(uploader string) ([]SourceForDirectory, error) {
+	query := fmt.Sprintf(
+		`
+			%s
+			AND sources.uploader = '%s'
+			ORDER BY sources.uploaded_at DESC, sources.album_index DESC, sources.id DESC
+		`,
+		sourceForDirectoryQuery,
+		EscapeTextLiteral(uploader),
+	)
+
+	result := []SourceForDirectory{}
+	return result, storage.db.Raw(query).Find(&result).Error
+}
+
+func (storage *SourceStorage) FindForDirectoryById(id uuid.UUID) (*SourceForDirectory, error) {
+	query := fmt.Sprintf(
+		`
+			%s
+			AND sources.id = '%s'
+		`,
+		sourceForDirectoryQuery,
+		id.String(),
+	)
+
+	result := []SourceForDirectory{}
+	if err := storage.db.Raw(query).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	return &result[0], nil
+}
+
+func (storage *SourceStorage) GetChildIds(parentId uuid.UUID) ([]uuid.UUID, error) {
+	result := []uuid.UUID{}
+	return result, storage.db.Model(&SourceHierarchy{}).
+		Where("parent_id = ?", parentId.String()).
+		Order("list_index").
+		Pluck("child_id", &result).Error
+}
+
 func (storage *SourceStorage) GetListForApi(managementPolicies []model.SourceManagementPolicy) ([]Source, error) {
 	conditions := []string{"1 = 1"}
 	params := map[string]any{}
@@ -179,6 +275,10 @@ func (storage *SourceStorage) SetManagementPolicyById(id uuid.UUID, managementPo
 	return storage.db.Exec("UPDATE sources SET management_policy = ? WHERE id = ?", managementPolicy, id).Error
 }
 
+func (storage *SourceStorage) SetGenresById(id uuid.UUID, genres []string) error {
+	return storage.db.Model(&Source{Id: id}).Select("genres", "genres_edited").Updates(&Source{Genres: genres, GenresEdited: true}).Error
+}
+
 func (storage *SourceStorage) FindNextForDownload() (*Source, error) {
 	sql := `
 		SELECT sources.*
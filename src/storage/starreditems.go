@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	STARRED_ITEM_TYPE_SONG   = "song"
+	STARRED_ITEM_TYPE_ALBUM  = "album"
+	STARRED_ITEM_TYPE_ARTIST = "artist"
+)
+
+type StarredItemStorage struct {
+	db *DbHelper
+}
+
+type StarredItem struct {
+	Id int64
+
+	Username string `gorm:"uniqueIndex:idx_starred_items_user_item"`
+	ItemType string `gorm:"uniqueIndex:idx_starred_items_user_item"`
+	ItemId   string `gorm:"uniqueIndex:idx_starred_items_user_item"`
+
+	StarredAt time.Time
+}
+
+func NewStarredItemStorage(db *gorm.DB) (*StarredItemStorage, error) {
+	err := db.AutoMigrate(
+		&StarredItem{},
+	)
+	return &StarredItemStorage{db: NewDbHelper(db)}, err
+}
+
+func (storage *StarredItemStorage) Star(username string, itemType string, itemIds []string, starredAt time.Time) error {
+	if len(itemIds) == 0 {
+		return nil
+	}
+
+	items := []StarredItem{}
+	for _, itemId := range itemIds {
+		items = append(items, StarredItem{
+			Username:  username,
+			ItemType:  itemType,
+			ItemId:    itemId,
+			StarredAt: starredAt,
+		})
+	}
+
+	return storage.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&items).Error
+}
+
+func (storage *StarredItemStorage) Unstar(username string, itemType string, itemIds []string) error {
+	if len(itemIds) == 0 {
+		return nil
+	}
+
+	return storage.db.Where("username = ? AND item_type = ? AND item_id IN ?", username, itemType, itemIds).Delete(&StarredItem{}).Error
+}
+
+func (storage *StarredItemStorage) GetStarredItems(username string, itemType string) ([]StarredItem, error) {
+	result := []StarredItem{}
+	return result, storage.db.Where("username = ? AND item_type = ?", username, itemType).Order("starred_at DESC").Find(&result).Error
+}
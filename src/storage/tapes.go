@@ -31,6 +31,11 @@ type Tape struct {
 	Artist     string
 	ReleasedAt *time.Time
 
+	Genres []string `gorm:"serializer:json"`
+
+	// playlists created by users through subsonic belong to them, all other tapes are shared
+	Owner string
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -88,7 +93,7 @@ func (storage *TapeStorage) Update(tape Tape) (Tape, error) {
 	}
 
 	err := storage.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Clauses(clause.Returning{}).Omit("created_at").Save(&tape).Error; err != nil {
+		if err := tx.Clauses(clause.Returning{}).Omit("created_at", "owner").Save(&tape).Error; err != nil {
 			return err
 		}
 		if err := tx.Where("tape_id = ?", tape.Id).Delete(&TapeToTrack{}).Error; err != nil {
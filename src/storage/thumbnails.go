@@ -68,3 +68,25 @@ func (s *ThumbnailStorage) GetById(id uuid.UUID) (Thumbnail, error) {
 	result := Thumbnail{Id: id}
 	return result, s.db.Find(&result).Error
 }
+
+func (s *ThumbnailStorage) GetUnused(updatedBefore time.Time) ([]Thumbnail, error) {
+	query := `
+		SELECT *
+		FROM thumbnails
+		WHERE thumbnails.updated_at < @updatedBefore
+			AND NOT EXISTS (SELECT 1 FROM sources WHERE sources.thumbnail_id = thumbnails.id)
+			AND NOT EXISTS (SELECT 1 FROM tapes WHERE tapes.thumbnail_id = thumbnails.id)
+			AND NOT EXISTS (SELECT 1 FROM podcast_channels WHERE podcast_channels.thumbnail_id = thumbnails.id)
+	`
+
+	args := map[string]any{
+		"updatedBefore": updatedBefore,
+	}
+
+	result := []Thumbnail{}
+	return result, s.db.Raw(query, args).Find(&result).Error
+}
+
+func (s *ThumbnailStorage) DeleteById(id uuid.UUID) error {
+	return s.db.Delete(&Thumbnail{Id: id}).Error
+}
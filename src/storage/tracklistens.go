@@ -14,8 +14,9 @@ type TrackListensStorage struct {
 type TrackListens struct {
 	Id int64
 
-	TrackId uuid.UUID `gorm:"uniqueIndex"`
-	Track   *Track
+	Username string    `gorm:"uniqueIndex:idx_track_listens_user_track"`
+	TrackId  uuid.UUID `gorm:"uniqueIndex:idx_track_listens_user_track"`
+	Track    *Track
 
 	ListenCount int
 
@@ -29,13 +30,14 @@ func NewTrackListensStorage(db *gorm.DB) (*TrackListensStorage, error) {
 	return &TrackListensStorage{db: NewDbHelper(db)}, err
 }
 
-func (storage *TrackListensStorage) Record(trackId uuid.UUID, listenedAt time.Time, incrementListenCount bool) error {
+func (storage *TrackListensStorage) Record(username string, trackId uuid.UUID, listenedAt time.Time, incrementListenCount bool) error {
 	return storage.db.ExclusiveTransaction(func(tx *gorm.DB) error {
 		item := TrackListens{}
-		if err := tx.Where(&TrackListens{TrackId: trackId}).Find(&item).Error; err != nil {
+		if err := tx.Where(&TrackListens{Username: username, TrackId: trackId}).Find(&item).Error; err != nil {
 			return err
 		}
 
+		item.Username = username
 		item.TrackId = trackId
 		if incrementListenCount {
 			item.ListenCount += 1
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -143,8 +144,8 @@ func (storage *TrackStorage) GetTracksWithSourcesByIds(ids []uuid.UUID) ([]Track
 	return tracks, storage.db.Preload("Source").Where("id IN ?", ids).Find(&tracks).Error
 }
 
-func (storage *TrackStorage) GetSubsonicTrack(id uuid.UUID) (*SubsonicTrackItem, error) {
-	tracks, err := storage.getSubsonicTracks(1, 0, fmt.Sprintf("id = '%s'", id.String()), "id")
+func (storage *TrackStorage) GetSubsonicTrack(username string, id uuid.UUID) (*SubsonicTrackItem, error) {
+	tracks, err := storage.getSubsonicTracks(username, 1, 0, fmt.Sprintf("id = '%s'", id.String()), "id")
 	if err != nil {
 		return nil, err
 	}
@@ -155,22 +156,44 @@ func (storage *TrackStorage) GetSubsonicTrack(id uuid.UUID) (*SubsonicTrackItem,
 	return &tracks[0], nil
 }
 
-func (storage *TrackStorage) SearchSubsonicTracks(count int, offset int, query string) ([]SubsonicTrackItem, error) {
+func (storage *TrackStorage) FindSubsonicTrackByArtistAndTitle(username string, artist string, title string) (*SubsonicTrackItem, error) {
+	filter := fmt.Sprintf(
+		"lower(artist) = lower('%s') AND lower(title) = lower('%s')",
+		EscapeTextLiteral(artist),
+		EscapeTextLiteral(title),
+	)
+
+	tracks, err := storage.getSubsonicTracks(username, 1, 0, filter, "id")
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+
+	return &tracks[0], nil
+}
+
+func (storage *TrackStorage) SearchSubsonicTracks(username string, count int, offset int, query string) ([]SubsonicTrackItem, error) {
 	filter := MakeTextSearchCondition([]string{"artist", "album", "title"}, query)
 	if filter == "" {
 		return []SubsonicTrackItem{}, nil
 	}
 
-	return storage.getSubsonicTracks(count, offset, filter, "id")
+	return storage.getSubsonicTracks(username, count, offset, filter, "id")
 }
 
-func (storage *TrackStorage) GetSubsonicTracksSortId(count int, offset int) ([]SubsonicTrackItem, error) {
-	return storage.getSubsonicTracks(count, offset, "", "id")
+func (storage *TrackStorage) GetSubsonicTracksSortId(username string, count int, offset int) ([]SubsonicTrackItem, error) {
+	return storage.getSubsonicTracks(username, count, offset, "", "id")
 }
 
-func (storage *TrackStorage) GetSubsonicTracksSortRandom(count int, fromYear *int, toYear *int) ([]SubsonicTrackItem, error) {
+func (storage *TrackStorage) GetSubsonicTracksSortRandom(username string, count int, genre string, fromYear *int, toYear *int) ([]SubsonicTrackItem, error) {
 	conditions := []string{}
 
+	if genre != "" {
+		conditions = append(conditions, genreCondition("genres", genre))
+	}
+
 	if fromYear != nil && toYear != nil {
 		conditions = append(conditions, fmt.Sprintf("cast(strftime('%%Y', album_release_date) AS INTEGER) BETWEEN %d AND %d", *fromYear, *toYear))
 	} else if fromYear != nil {
@@ -179,10 +202,18 @@ func (storage *TrackStorage) GetSubsonicTracksSortRandom(count int, fromYear *in
 		conditions = append(conditions, fmt.Sprintf("cast(strftime('%%Y', album_release_date) AS INTEGER) <= %d", *toYear))
 	}
 
-	return storage.getSubsonicTracks(count, 0, strings.Join(conditions, " AND "), "random()")
+	return storage.getSubsonicTracks(username, count, 0, strings.Join(conditions, " AND "), "random()")
 }
 
-func (storage *TrackStorage) GetSubsonicTracksByAlbum(albumId uuid.UUID) ([]SubsonicTrackItem, error) {
+func (storage *TrackStorage) GetSubsonicTracksByGenre(username string, count int, offset int, genre string) ([]SubsonicTrackItem, error) {
+	return storage.getSubsonicTracks(username, count, offset, genreCondition("genres", genre), "lower(artist), lower(album), album_track_index, id")
+}
+
+func (storage *TrackStorage) GetSubsonicTracksSortStarred(username string, count int, offset int) ([]SubsonicTrackItem, error) {
+	return storage.getSubsonicTracks(username, count, offset, "starred_at IS NOT NULL", "starred_at DESC")
+}
+
+func (storage *TrackStorage) GetSubsonicTracksByAlbum(username string, albumId uuid.UUID) ([]SubsonicTrackItem, error) {
 	query := fmt.Sprintf(
 		`
 			SELECT
@@ -194,17 +225,34 @@ func (storage *TrackStorage) GetSubsonicTracksByAlbum(albumId uuid.UUID) ([]Subs
 				tape_to_tracks.list_index AS playlist_track_index,
 				tapes.name AS album,
 				tracks.artist AS artist,
+				%s AS artist_id,
 				tracks.title AS title,
 				(tracks.end_offset_ms - tracks.start_offset_ms) / 1000 AS duration_sec,
-				track_listens.listen_count AS play_count
+				track_listens.listen_count AS play_count,
+				track_listens.last_listened_at AS last_played_at,
+				%s AS genres,
+				starred_items.starred_at AS starred_at,
+				ratings.rating AS user_rating,
+				average_ratings.rating AS average_rating
 			FROM tracks
 			JOIN sources ON sources.id = tracks.source_id
 			JOIN tape_to_tracks ON tape_to_tracks.track_id = tracks.id
 			JOIN tapes ON tapes.id = tape_to_tracks.tape_id
-			LEFT JOIN track_listens ON track_listens.track_id = tracks.id
+			LEFT JOIN track_listens ON track_listens.username = '%s' AND track_listens.track_id = tracks.id
+			LEFT JOIN starred_items ON starred_items.username = '%s' AND starred_items.item_type = '%s' AND starred_items.item_id = tracks.id
+			LEFT JOIN ratings ON ratings.username = '%s' AND ratings.item_type = '%s' AND ratings.item_id = tracks.id
+			LEFT JOIN (SELECT item_id, avg(rating) AS rating FROM ratings WHERE item_type = '%s' GROUP BY item_id) average_ratings ON average_ratings.item_id = tracks.id
 			WHERE tapes.id = '%s' AND tapes.type = '%s'
 			ORDER BY album_track_index ASC
 		`,
+		artistIdExpression("tracks.artist"),
+		genresExpression("tapes.genres", "sources.genres"),
+		EscapeTextLiteral(username),
+		EscapeTextLiteral(username),
+		STARRED_ITEM_TYPE_SONG,
+		EscapeTextLiteral(username),
+		RATING_ITEM_TYPE_SONG,
+		RATING_ITEM_TYPE_SONG,
 		albumId.String(),
 		TAPE_TYPE_ALBUM,
 	)
@@ -213,7 +261,7 @@ func (storage *TrackStorage) GetSubsonicTracksByAlbum(albumId uuid.UUID) ([]Subs
 	return result, storage.db.Raw(query).Find(&result).Error
 }
 
-func (storage *TrackStorage) GetSubsonicTracksByPlaylist(playlistId uuid.UUID) ([]SubsonicTrackItem, error) {
+func (storage *TrackStorage) GetSubsonicTracksByPlaylist(username string, playlistId uuid.UUID) ([]SubsonicTrackItem, error) {
 	query := fmt.Sprintf(
 		`
 			WITH filtered_tracks AS (
@@ -222,14 +270,23 @@ func (storage *TrackStorage) GetSubsonicTracksByPlaylist(playlistId uuid.UUID) (
 					sources.thumbnail_id AS thumbnail_id,
 					tape_to_tracks.list_index AS playlist_track_index,
 					tracks.artist AS artist,
+					%s AS artist_id,
 					tracks.title AS title,
 					(tracks.end_offset_ms - tracks.start_offset_ms) / 1000 AS duration_sec,
-					track_listens.listen_count AS play_count
+					track_listens.listen_count AS play_count,
+					track_listens.last_listened_at AS last_played_at,
+					sources.genres AS source_genres,
+					starred_items.starred_at AS starred_at,
+					ratings.rating AS user_rating,
+					average_ratings.rating AS average_rating
 				FROM tracks
 				JOIN sources ON sources.id = tracks.source_id
 				JOIN tape_to_tracks ON tape_to_tracks.track_id = tracks.id
 				JOIN tapes playlists ON playlists.id = tape_to_tracks.tape_id AND playlists.type = '%s'
-				LEFT JOIN track_listens ON track_listens.track_id = tracks.id
+				LEFT JOIN track_listens ON track_listens.username = '%s' AND track_listens.track_id = tracks.id
+				LEFT JOIN starred_items ON starred_items.username = '%s' AND starred_items.item_type = '%s' AND starred_items.item_id = tracks.id
+				LEFT JOIN ratings ON ratings.username = '%s' AND ratings.item_type = '%s' AND ratings.item_id = tracks.id
+				LEFT JOIN (SELECT item_id, avg(rating) AS rating FROM ratings WHERE item_type = '%s' GROUP BY item_id) average_ratings ON average_ratings.item_id = tracks.id
 				WHERE playlists.id = '%s'
 			)
 			SELECT
@@ -241,6 +298,7 @@ func (storage *TrackStorage) GetSubsonicTracksByPlaylist(playlistId uuid.UUID) (
 					albums.thumbnail_id AS album_thumbnail_id,
 					albums.name AS album,
 					tape_to_tracks.list_index AS album_track_index,
+					%s AS genres,
 					row_number() OVER (PARTITION BY filtered_tracks.id, filtered_tracks.playlist_track_index ORDER BY albums.created_at ASC NULLS LAST) AS rank
 				FROM filtered_tracks
 				LEFT JOIN tape_to_tracks ON tape_to_tracks.track_id = filtered_tracks.id
@@ -249,8 +307,16 @@ func (storage *TrackStorage) GetSubsonicTracksByPlaylist(playlistId uuid.UUID) (
 			WHERE enriched_tracks.rank = 1
 			ORDER BY enriched_tracks.playlist_track_index ASC
 		`,
+		artistIdExpression("tracks.artist"),
 		TAPE_TYPE_PLAYLIST,
+		EscapeTextLiteral(username),
+		EscapeTextLiteral(username),
+		STARRED_ITEM_TYPE_SONG,
+		EscapeTextLiteral(username),
+		RATING_ITEM_TYPE_SONG,
+		RATING_ITEM_TYPE_SONG,
 		playlistId.String(),
+		genresExpression("albums.genres", "filtered_tracks.source_genres"),
 		TAPE_TYPE_ALBUM,
 	)
 
@@ -258,24 +324,83 @@ func (storage *TrackStorage) GetSubsonicTracksByPlaylist(playlistId uuid.UUID) (
 	return result, storage.db.Raw(query).Find(&result).Error
 }
 
-func (storage *TrackStorage) getSubsonicTracks(count int, offset int, filter string, order string) ([]SubsonicTrackItem, error) {
+// tracks are ordered the same way as the given sources and by their position inside each source
+func (storage *TrackStorage) GetSubsonicTracksBySources(username string, sourceIds []uuid.UUID) ([]SubsonicTrackItem, error) {
+	if len(sourceIds) == 0 {
+		return []SubsonicTrackItem{}, nil
+	}
+
+	orderedTracks := []Track{}
+	if err := storage.db.Where("source_id IN ?", sourceIds).Order("start_offset_ms").Find(&orderedTracks).Error; err != nil {
+		return nil, err
+	}
+	if len(orderedTracks) == 0 {
+		return []SubsonicTrackItem{}, nil
+	}
+
+	trackIds := []string{}
+	for _, track := range orderedTracks {
+		trackIds = append(trackIds, fmt.Sprintf("'%s'", track.Id.String()))
+	}
+
+	tracks, err := storage.getSubsonicTracks(username, -1, 0, fmt.Sprintf("id IN (%s)", strings.Join(trackIds, ", ")), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	sourceIndexes := map[uuid.UUID]int{}
+	for i, sourceId := range sourceIds {
+		if _, ok := sourceIndexes[sourceId]; !ok {
+			sourceIndexes[sourceId] = i
+		}
+	}
+
+	trackIndexes := map[string]int{}
+	for i, track := range orderedTracks {
+		trackIndexes[track.Id.String()] = sourceIndexes[track.SourceId]*len(orderedTracks) + i
+	}
+
+	slices.SortFunc(tracks, func(a SubsonicTrackItem, b SubsonicTrackItem) int {
+		return trackIndexes[a.Id] - trackIndexes[b.Id]
+	})
+
+	return tracks, nil
+}
+
+func (storage *TrackStorage) getSubsonicTracks(username string, count int, offset int, filter string, order string) ([]SubsonicTrackItem, error) {
+	query := subsonicTracksQuery(username, filter) + fmt.Sprintf("\nORDER BY %s\nLIMIT %d OFFSET %d", order, count, offset)
+
+	result := []SubsonicTrackItem{}
+	return result, storage.db.Raw(query).Find(&result).Error
+}
+
+func subsonicTracksQuery(username string, filter string) string {
 	if filter == "" {
 		filter = "1 = 1"
 	}
 
-	query := fmt.Sprintf(
+	return fmt.Sprintf(
 		`
 			WITH filtered_tracks AS (
 				SELECT
 					tracks.id AS id,
 					sources.thumbnail_id AS thumbnail_id,
 					tracks.artist AS artist,
+					%s AS artist_id,
 					tracks.title AS title,
 					(tracks.end_offset_ms - tracks.start_offset_ms) / 1000 AS duration_sec,
-					track_listens.listen_count AS play_count
+					track_listens.listen_count AS play_count,
+					track_listens.last_listened_at AS last_played_at,
+					sources.genres AS source_genres,
+					starred_items.starred_at AS starred_at,
+					ratings.rating AS user_rating,
+					average_ratings.rating AS average_rating
 				FROM tracks
 				JOIN sources ON sources.id = tracks.source_id
-				LEFT JOIN track_listens ON track_listens.track_id = tracks.id
+				LEFT JOIN track_listens ON track_listens.username = '%s' AND track_listens.track_id = tracks.id
+				LEFT JOIN starred_items ON starred_items.username = '%s' AND starred_items.item_type = '%s' AND starred_items.item_id = tracks.id
+				LEFT JOIN ratings ON ratings.username = '%s' AND ratings.item_type = '%s' AND ratings.item_id = tracks.id
+				LEFT JOIN (SELECT item_id, avg(rating) AS rating FROM ratings WHERE item_type = '%s' GROUP BY item_id) average_ratings ON average_ratings.item_id = tracks.id
 			)
 			SELECT
 				enriched_tracks.*
@@ -287,23 +412,23 @@ func (storage *TrackStorage) getSubsonicTracks(count int, offset int, filter str
 					albums.name AS album,
 					tape_to_tracks.list_index AS album_track_index,
 					albums.released_at AS album_release_date,
+					%s AS genres,
 					row_number() OVER (PARTITION BY filtered_tracks.id ORDER BY albums.created_at ASC NULLS LAST) AS rank
 				FROM filtered_tracks
 				LEFT JOIN tape_to_tracks ON tape_to_tracks.track_id = filtered_tracks.id
 				LEFT JOIN tapes albums ON albums.id = tape_to_tracks.tape_id AND albums.type = '%s'
 			) enriched_tracks
 			WHERE enriched_tracks.rank = 1 AND %s
-			ORDER BY %s
-			LIMIT %d
-			OFFSET %d
 		`,
+		artistIdExpression("tracks.artist"),
+		EscapeTextLiteral(username),
+		EscapeTextLiteral(username),
+		STARRED_ITEM_TYPE_SONG,
+		EscapeTextLiteral(username),
+		RATING_ITEM_TYPE_SONG,
+		RATING_ITEM_TYPE_SONG,
+		genresExpression("albums.genres", "filtered_tracks.source_genres"),
 		TAPE_TYPE_ALBUM,
 		filter,
-		order,
-		count,
-		offset,
 	)
-
-	result := []SubsonicTrackItem{}
-	return result, storage.db.Raw(query).Find(&result).Error
 }
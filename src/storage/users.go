@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	USER_ROLE_ADMIN  = "admin"
+	USER_ROLE_STREAM = "stream"
+)
+
+type User struct {
+	Username string `gorm:"primaryKey"`
+
+	// encrypted instead of hashed since token authentication requires the plain password
+	EncryptedPassword string
+	Email             string
+	Role              string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type UserStorage struct {
+	db *DbHelper
+}
+
+func NewUserStorage(db *gorm.DB) (*UserStorage, error) {
+	if err := db.AutoMigrate(&User{}); err != nil {
+		return nil, err
+	}
+
+	return &UserStorage{db: NewDbHelper(db)}, nil
+}
+
+func (storage *UserStorage) Create(user User) (User, error) {
+	return user, storage.db.Create(&user).Error
+}
+
+func (storage *UserStorage) Update(user User) error {
+	return storage.db.Model(&user).Select("encrypted_password", "email", "role").Updates(&user).Error
+}
+
+func (storage *UserStorage) Find(username string) (*User, error) {
+	result := User{}
+	if err := storage.db.Where("username = ?", username).Take(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+func (storage *UserStorage) GetAll() ([]User, error) {
+	result := []User{}
+	return result, storage.db.Order("lower(username)").Find(&result).Error
+}
+
+// everything belonging to the user is deleted as well, so that a new user with the same name starts from scratch
+func (storage *UserStorage) Delete(username string) error {
+	return storage.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range []any{
+			&ApiKey{},
+			&StarredItem{},
+			&Rating{},
+			&MuxedRating{},
+			&MuxedStarredItem{},
+			&TrackListens{},
+			&MuxedSongListens{},
+			&Bookmark{},
+			&MuxedBookmark{},
+			&PlayQueue{},
+		} {
+			if err := tx.Where("username = ?", username).Delete(item).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("owner = ?", username).Delete(&LastFmSession{}).Error; err != nil {
+			return err
+		}
+
+		playlistIds := tx.Model(&Tape{}).Select("id").Where("type = ? AND owner = ?", TAPE_TYPE_PLAYLIST, username)
+		if err := tx.Where("tape_id IN (?)", playlistIds).Delete(&TapeToTrack{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("type = ? AND owner = ?", TAPE_TYPE_PLAYLIST, username).Delete(&Tape{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("share_id IN (?)", tx.Model(&Share{}).Select("id").Where("username = ?", username)).Delete(&ShareItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("username = ?", username).Delete(&Share{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("username = ?", username).Delete(&User{}).Error
+	})
+}
@@ -43,3 +43,7 @@ func (s *YtdlpMetadataStorage) Find(url string, minUpdatedAt time.Time) (*YtdlpM
 		return &result, err
 	}
 }
+
+func (s *YtdlpMetadataStorage) Delete(url string) error {
+	return s.db.Where("url = ?", url).Delete(&YtdlpMetadataCacheItem{}).Error
+}
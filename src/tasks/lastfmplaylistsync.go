@@ -47,19 +47,39 @@ func (h *LastFmPlaylistSyncHandler) Name() string {
 func (h *LastFmPlaylistSyncHandler) OnSchedule() error {
 	slog.Debug("Synchronizing last.fm playlists")
 
-	session, err := h.svc.GetCurrentSession()
+	sessions, err := h.svc.GetSessions()
 	if err != nil {
-		return fmt.Errorf("failed to get current last.fm session: %w", err)
+		return fmt.Errorf("failed to get last.fm sessions: %w", err)
 	}
 
-	if session == nil {
-		slog.Debug("No current last.fm session found, skipping playlist sync")
+	if len(sessions) == 0 {
+		slog.Debug("No last.fm sessions found, skipping playlist sync")
 		return nil
 	}
 
+	playlists := []storage.ExternalPlaylist{}
+	for _, session := range sessions {
+		sessionPlaylists, err := h.syncSession(session)
+		if err != nil {
+			return err
+		}
+		playlists = append(playlists, sessionPlaylists...)
+	}
+
+	err = h.playlists.Replace(providerLastfm, playlists)
+	if err != nil {
+		return fmt.Errorf("failed to replace last.fm playlists in the database: %w", err)
+	}
+
+	slog.Info("Done synchronizing last.fm playlists")
+	return nil
+}
+
+func (h *LastFmPlaylistSyncHandler) syncSession(session logic.LastFmSession) ([]storage.ExternalPlaylist, error) {
 	slog.Debug(fmt.Sprintf("Synchronizing last.fm playlists for %s", session.Username))
 
 	libraryPlaylist, err := h.processPlaylist(
+		session.Owner,
 		fmt.Sprintf("%s_library", session.Username),
 		"last.fm: Library",
 		func(page int) (lastfm.PlaylistWrapper, error) {
@@ -67,10 +87,11 @@ func (h *LastFmPlaylistSyncHandler) OnSchedule() error {
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to get library playlist for %s from last.fm: %w", session.Username, err)
+		return nil, fmt.Errorf("failed to get library playlist for %s from last.fm: %w", session.Username, err)
 	}
 
 	mixPlaylist, err := h.processPlaylist(
+		session.Owner,
 		fmt.Sprintf("%s_mix", session.Username),
 		"last.fm: Mix",
 		func(page int) (lastfm.PlaylistWrapper, error) {
@@ -78,10 +99,11 @@ func (h *LastFmPlaylistSyncHandler) OnSchedule() error {
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to get mix playlist for %s from last.fm: %w", session.Username, err)
+		return nil, fmt.Errorf("failed to get mix playlist for %s from last.fm: %w", session.Username, err)
 	}
 
 	recommendedPlaylist, err := h.processPlaylist(
+		session.Owner,
 		fmt.Sprintf("%s_recommended", session.Username),
 		"last.fm: Recommended",
 		func(page int) (lastfm.PlaylistWrapper, error) {
@@ -89,19 +111,14 @@ func (h *LastFmPlaylistSyncHandler) OnSchedule() error {
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to get recommended playlist for %s from last.fm: %w", session.Username, err)
+		return nil, fmt.Errorf("failed to get recommended playlist for %s from last.fm: %w", session.Username, err)
 	}
 
-	err = h.playlists.Replace(providerLastfm, []storage.ExternalPlaylist{libraryPlaylist, mixPlaylist, recommendedPlaylist})
-	if err != nil {
-		return fmt.Errorf("failed to replace last.fm playlists in the database: %w", err)
-	}
-
-	slog.Info("Done synchronizing last.fm playlists")
-	return nil
+	return []storage.ExternalPlaylist{libraryPlaylist, mixPlaylist, recommendedPlaylist}, nil
 }
 
 func (h *LastFmPlaylistSyncHandler) processPlaylist(
+	owner string,
 	playlistId string,
 	playlistName string,
 	fetch func(page int) (lastfm.PlaylistWrapper, error),
@@ -237,7 +254,7 @@ func (h *LastFmPlaylistSyncHandler) processPlaylist(
 		Provider:  providerLastfm,
 		RawId:     playlistId,
 		Name:      playlistName,
-		CreatedBy: "last.fm",
+		CreatedBy: fmt.Sprintf("last.fm (%s)", owner),
 
 		Tracks: tracks,
 	}, nil
@@ -0,0 +1,26 @@
+package tasks
+
+import (
+	"context"
+	"tapesonic/logic"
+)
+
+type RefreshPodcastsTaskHandler struct {
+	podcasts *logic.PodcastService
+}
+
+func NewRefreshPodcastsTaskHandler(
+	podcasts *logic.PodcastService,
+) *RefreshPodcastsTaskHandler {
+	return &RefreshPodcastsTaskHandler{
+		podcasts: podcasts,
+	}
+}
+
+func (h *RefreshPodcastsTaskHandler) Name() string {
+	return "REFRESH_PODCASTS"
+}
+
+func (h *RefreshPodcastsTaskHandler) OnSchedule() error {
+	return h.podcasts.RefreshAll(context.Background())
+}
@@ -1,8 +1,10 @@
 package tasks
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"tapesonic/logic"
 	"tapesonic/storage"
 	"time"
@@ -14,6 +16,10 @@ type SyncLibraryHandler struct {
 	songs   *storage.CachedMuxSongStorage
 	albums  *storage.CachedMuxAlbumStorage
 	artists *storage.CachedMuxArtistStorage
+
+	lock     sync.Mutex
+	scanning bool
+	count    int
 }
 
 func NewSyncLibraryHandler(
@@ -36,101 +42,183 @@ func (h *SyncLibraryHandler) Name() string {
 }
 
 func (h *SyncLibraryHandler) OnSchedule() error {
+	if !h.tryStartScan() {
+		slog.Debug("Library cache sync is already running, skipping")
+		return nil
+	}
+	defer h.finishScan()
+
+	return h.sync()
+}
+
+// Starts the library cache sync in background, returns false if it's already running
+func (h *SyncLibraryHandler) StartScan() bool {
+	if !h.tryStartScan() {
+		return false
+	}
+
+	go func() {
+		defer h.finishScan()
+
+		err := h.sync()
+		if err != nil {
+			slog.Error(fmt.Sprintf("Manually started library cache sync failed: %s", err.Error()))
+		}
+	}()
+
+	return true
+}
+
+func (h *SyncLibraryHandler) GetScanStatus() (scanning bool, count int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.scanning, h.count
+}
+
+func (h *SyncLibraryHandler) tryStartScan() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.scanning {
+		return false
+	}
+
+	h.scanning = true
+	h.count = 0
+	return true
+}
+
+func (h *SyncLibraryHandler) addScanned(count int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.count += count
+}
+
+func (h *SyncLibraryHandler) finishScan() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.scanning = false
+}
+
+func (h *SyncLibraryHandler) sync() error {
 	slog.Debug("Refreshing the library cache")
 
-	artists := []storage.CachedMuxArtist{}
-	albums := []storage.CachedMuxAlbum{}
-	songs := []storage.CachedMuxSong{}
+	serviceNames := []string{}
+	for _, subsonicProvider := range h.subsonicProviders {
+		serviceNames = append(serviceNames, subsonicProvider.Name())
+	}
 
-	batchSize := 500
+	if err := errors.Join(
+		h.artists.DeleteOtherServices(serviceNames),
+		h.albums.DeleteOtherServices(serviceNames),
+		h.songs.DeleteOtherServices(serviceNames),
+	); err != nil {
+		return fmt.Errorf("failed to remove unconfigured services from the library cache: %w", err)
+	}
+
+	// every service is synced separately, so an unavailable one keeps its previous cache instead of blocking the others
+	errs := []error{}
 	for _, subsonicProvider := range h.subsonicProviders {
-		thisArtists := []storage.CachedMuxArtist{}
-		thisAlbums := []storage.CachedMuxAlbum{}
-		thisSongs := []storage.CachedMuxSong{}
-
-		for {
-			slog.Debug(fmt.Sprintf("Requesting %d more contents from subsonic `%s` for the library cache sync", batchSize, subsonicProvider.Name()))
-			search, err := subsonicProvider.Search3("", batchSize, len(thisArtists), batchSize, len(thisAlbums), batchSize, len(thisSongs))
-			if err != nil {
-				return fmt.Errorf("failed to make a no-query search while syncing the library cache, aborting: %w", err)
-			}
-
-			slog.Debug(fmt.Sprintf("Got another %d artists, %d albums and %d songs from subsonic `%s` while syncing the library cache", len(search.Artist), len(search.Album), len(search.Song), subsonicProvider.Name()))
-
-			cachedAt := time.Now()
-
-			for _, artist := range search.Artist {
-				artist = subsonicProvider.GetRawArtistId3(artist)
-				thisArtists = append(
-					thisArtists,
-					storage.CachedMuxArtist{
-						ServiceName: subsonicProvider.Name(),
-						ArtistId:    artist.Id,
-						Name:        artist.Name,
-						CachedAt:    cachedAt,
-					},
-				)
-			}
-
-			for _, album := range search.Album {
-				album = subsonicProvider.GetRawAlbum(album)
-				thisAlbums = append(
-					thisAlbums,
-					storage.CachedMuxAlbum{
-						ServiceName: subsonicProvider.Name(),
-						AlbumId:     album.Id,
-						Artist:      album.Artist,
-						Title:       album.Name,
-						CachedAt:    cachedAt,
-					},
-				)
-			}
-
-			for _, song := range search.Song {
-				song = subsonicProvider.GetRawSong(song)
-				thisSongs = append(
-					thisSongs,
-					storage.CachedMuxSong{
-						ServiceName: subsonicProvider.Name(),
-						SongId:      song.Id,
-						AlbumId:     song.AlbumId,
-						Artist:      song.Artist,
-						Album:       song.Album,
-						Title:       song.Title,
-						DurationSec: song.Duration,
-						CachedAt:    cachedAt,
-					},
-				)
-			}
-
-			if len(search.Artist) < batchSize && len(search.Album) < batchSize && len(search.Song) < batchSize {
-				slog.Debug(fmt.Sprintf("Got a total of %d artists, %d albums, %d songs from subsonic `%s`", len(thisArtists), len(thisAlbums), len(thisSongs), subsonicProvider.Name()))
-				break
-			}
+		if err := h.syncProvider(subsonicProvider); err != nil {
+			errs = append(errs, fmt.Errorf("failed to sync the library cache of subsonic `%s`: %w", subsonicProvider.Name(), err))
+		}
+	}
+
+	if len(errs) == 0 {
+		slog.Info("Done refreshing the library cache")
+	}
+	return errors.Join(errs...)
+}
+
+func (h *SyncLibraryHandler) syncProvider(subsonicProvider *logic.SubsonicNamedService) error {
+	batchSize := 500
+
+	thisArtists := []storage.CachedMuxArtist{}
+	thisAlbums := []storage.CachedMuxAlbum{}
+	thisSongs := []storage.CachedMuxSong{}
+
+	for {
+		slog.Debug(fmt.Sprintf("Requesting %d more contents from subsonic `%s` for the library cache sync", batchSize, subsonicProvider.Name()))
+		search, err := subsonicProvider.Search3("", "", batchSize, len(thisArtists), batchSize, len(thisAlbums), batchSize, len(thisSongs))
+		if err != nil {
+			return fmt.Errorf("failed to make a no-query search while syncing the library cache: %w", err)
+		}
+
+		slog.Debug(fmt.Sprintf("Got another %d artists, %d albums and %d songs from subsonic `%s` while syncing the library cache", len(search.Artist), len(search.Album), len(search.Song), subsonicProvider.Name()))
+
+		cachedAt := time.Now()
+
+		for _, artist := range search.Artist {
+			artist = subsonicProvider.GetRawArtistId3(artist)
+			thisArtists = append(
+				thisArtists,
+				storage.CachedMuxArtist{
+					ServiceName: subsonicProvider.Name(),
+					ArtistId:    artist.Id,
+					Name:        artist.Name,
+					CachedAt:    cachedAt,
+				},
+			)
 		}
 
-		artists = append(artists, thisArtists...)
-		albums = append(albums, thisAlbums...)
-		songs = append(songs, thisSongs...)
+		for _, album := range search.Album {
+			album = subsonicProvider.GetRawAlbum(album)
+			thisAlbums = append(
+				thisAlbums,
+				storage.CachedMuxAlbum{
+					ServiceName: subsonicProvider.Name(),
+					AlbumId:     album.Id,
+					Artist:      album.Artist,
+					Title:       album.Name,
+					CachedAt:    cachedAt,
+				},
+			)
+		}
+
+		for _, song := range search.Song {
+			song = subsonicProvider.GetRawSong(song)
+			thisSongs = append(
+				thisSongs,
+				storage.CachedMuxSong{
+					ServiceName: subsonicProvider.Name(),
+					SongId:      song.Id,
+					AlbumId:     song.AlbumId,
+					Artist:      song.Artist,
+					Album:       song.Album,
+					Title:       song.Title,
+					DurationSec: song.Duration,
+					CachedAt:    cachedAt,
+				},
+			)
+		}
+
+		h.addScanned(len(search.Song))
+
+		if len(search.Artist) < batchSize && len(search.Album) < batchSize && len(search.Song) < batchSize {
+			slog.Debug(fmt.Sprintf("Got a total of %d artists, %d albums, %d songs from subsonic `%s`", len(thisArtists), len(thisAlbums), len(thisSongs), subsonicProvider.Name()))
+			break
+		}
 	}
 
-	slog.Debug(fmt.Sprintf("Got a total of %d artists, %d albums, %d songs while refreshing the library cache", len(artists), len(albums), len(songs)))
+	slog.Debug(fmt.Sprintf("Saving %d artists, %d albums, %d songs of subsonic `%s` to the library cache", len(thisArtists), len(thisAlbums), len(thisSongs), subsonicProvider.Name()))
 
-	err := h.artists.Replace(artists)
+	err := h.artists.Replace(subsonicProvider.Name(), thisArtists)
 	if err != nil {
 		return fmt.Errorf("failed to save refreshed artist cache: %w", err)
 	}
 
-	err = h.albums.Replace(albums)
+	err = h.albums.Replace(subsonicProvider.Name(), thisAlbums)
 	if err != nil {
 		return fmt.Errorf("failed to save refreshed album cache: %w", err)
 	}
 
-	err = h.songs.Replace(songs)
+	err = h.songs.Replace(subsonicProvider.Name(), thisSongs)
 	if err != nil {
 		return fmt.Errorf("failed to save refreshed song cache: %w", err)
 	}
 
-	slog.Info("Done refreshing the library cache")
 	return nil
 }
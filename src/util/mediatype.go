@@ -7,6 +7,8 @@ import (
 
 func FormatToMediaType(format string) string {
 	switch format {
+	case "aac":
+		return "audio/aac"
 	case "flac":
 		return "audio/flac"
 	case "mp3":
@@ -32,6 +34,14 @@ func FormatToMediaType(format string) string {
 	return "application/octet-stream"
 }
 
+func FormatToExtension(format string) string {
+	switch format {
+	case "matroska":
+		return "mka"
+	}
+	return format
+}
+
 func MediaTypeToFormat(mediaType string) string {
 	switch mediaType {
 	case "image/png":
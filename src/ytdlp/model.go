@@ -1,15 +1,16 @@
 package ytdlp
 
 type YtdlpFile struct {
-	Id         string  `json:"id"`
-	Title      string  `json:"title"`
-	Uploader   string  `json:"uploader"`
-	UploaderId string  `json:"uploader_id"`
-	Channel    string  `json:"channel"`
-	Timestamp  float64 `json:"timestamp"`
-	Url        string  `json:"url"`
-	WebpageUrl string  `json:"webpage_url"`
-	Thumbnail  string  `json:"thumbnail"`
+	Id          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Uploader    string  `json:"uploader"`
+	UploaderId  string  `json:"uploader_id"`
+	Channel     string  `json:"channel"`
+	Timestamp   float64 `json:"timestamp"`
+	Url         string  `json:"url"`
+	WebpageUrl  string  `json:"webpage_url"`
+	Thumbnail   string  `json:"thumbnail"`
 
 	PlaylistIndex int `json:"playlist_index"`
 
@@ -20,8 +21,14 @@ type YtdlpFile struct {
 	TrackNumber int    `json:"track_number"`
 
 	Duration float64 `json:"duration"`
+	IsLive   bool    `json:"is_live"`
 
 	ReleaseDate string `json:"release_date"`
+	UploadDate  string `json:"upload_date"`
+
+	Language          string                     `json:"language"`
+	Subtitles         map[string][]YtdlpSubtitle `json:"subtitles"`
+	AutomaticCaptions map[string][]YtdlpSubtitle `json:"automatic_captions"`
 
 	Ext                string                   `json:"ext"`
 	Formats            []YtdlpFormat            `json:"formats"`
@@ -55,6 +62,12 @@ type YtdlpChapter struct {
 	EndTime   float64 `json:"end_time"`
 }
 
+type YtdlpSubtitle struct {
+	Ext  string `json:"ext"`
+	Url  string `json:"url"`
+	Name string `json:"name"`
+}
+
 type YtdlpRequestedDownload struct {
 	ACodec   string `json:"acodec"`
 	Ext      string `json:"ext"`
@@ -0,0 +1,115 @@
+package ytdlp
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type VttCue struct {
+	StartMs int64
+	EndMs   int64
+	Lines   []string
+}
+
+var vttTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+func ParseVtt(content string) ([]VttCue, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimPrefix(content, "\ufeff")
+
+	blocks := strings.Split(content, "\n\n")
+	if !strings.HasPrefix(blocks[0], "WEBVTT") {
+		return nil, fmt.Errorf("not a WebVTT file")
+	}
+
+	cues := []VttCue{}
+	for _, block := range blocks[1:] {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+
+		// NOTE, STYLE and REGION blocks don't have timings, cue identifiers come before the timings
+		timingIndex := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timingIndex = i
+				break
+			}
+		}
+		if timingIndex == -1 || strings.HasPrefix(lines[0], "NOTE") {
+			continue
+		}
+
+		startMs, endMs, err := parseVttTimings(lines[timingIndex])
+		if err != nil {
+			return nil, err
+		}
+
+		cue := VttCue{
+			StartMs: startMs,
+			EndMs:   endMs,
+			Lines:   []string{},
+		}
+		for _, line := range lines[timingIndex+1:] {
+			line = strings.TrimSpace(html.UnescapeString(vttTagRegex.ReplaceAllString(line, "")))
+			if line != "" {
+				cue.Lines = append(cue.Lines, line)
+			}
+		}
+
+		cues = append(cues, cue)
+	}
+
+	return cues, nil
+}
+
+func parseVttTimings(line string) (int64, int64, error) {
+	start, rest, _ := strings.Cut(line, "-->")
+
+	// cue settings like `align:start position:0%` can follow the end timestamp
+	end := strings.Fields(rest)
+	if len(end) == 0 {
+		return 0, 0, fmt.Errorf("invalid WebVTT cue timings: %s", line)
+	}
+
+	startMs, err := parseVttTimestamp(strings.TrimSpace(start))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	endMs, err := parseVttTimestamp(end[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return startMs, endMs, nil
+}
+
+// timestamps are either hh:mm:ss.ttt or mm:ss.ttt
+func parseVttTimestamp(timestamp string) (int64, error) {
+	parts := strings.Split(timestamp, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid WebVTT timestamp: %s", timestamp)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid WebVTT timestamp: %s", timestamp)
+	}
+
+	result := int64(math.Round(seconds * 1000))
+	multiplier := int64(60 * 1000)
+	for i := len(parts) - 2; i >= 0; i-- {
+		value, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid WebVTT timestamp: %s", timestamp)
+		}
+
+		result += value * multiplier
+		multiplier *= 60
+	}
+
+	return result, nil
+}
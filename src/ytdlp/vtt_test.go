@@ -0,0 +1,63 @@
+package ytdlp_test
+
+import (
+	"slices"
+	"tapesonic/ytdlp"
+	"testing"
+)
+
+func TestParseVtt_Manual(t *testing.T) {
+	content := "WEBVTT\r\nKind: captions\r\nLanguage: en\r\n\r\n" +
+		"NOTE this block --> should be skipped\r\n\r\n" +
+		"1\r\n00:01.500 --> 00:04.000\r\nFirst <i>line</i>\r\nSecond line\r\n\r\n" +
+		"01:00:05.000 --> 01:00:07.250 align:start position:0%\r\nRock &amp; roll\r\n"
+
+	cues, err := ytdlp.ParseVtt(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []ytdlp.VttCue{
+		{StartMs: 1500, EndMs: 4000, Lines: []string{"First line", "Second line"}},
+		{StartMs: 3605000, EndMs: 3607250, Lines: []string{"Rock & roll"}},
+	}
+	compareCues(cues, expected, t)
+}
+
+func TestParseVtt_YoutubeAutoCaptions(t *testing.T) {
+	// rolling captions repeat the previous line and use lines with a single space as padding
+	content := "WEBVTT\nKind: captions\nLanguage: en\n\n" +
+		"00:00:00.000 --> 00:00:02.000 align:start position:0%\n \nhello<00:00:00.500><c> world</c>\n\n" +
+		"00:00:02.000 --> 00:00:02.010 align:start position:0%\nhello world\n \n\n" +
+		"00:00:02.010 --> 00:00:04.000 align:start position:0%\nhello world\nsecond<00:00:02.800><c> line</c>\n"
+
+	cues, err := ytdlp.ParseVtt(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []ytdlp.VttCue{
+		{StartMs: 0, EndMs: 2000, Lines: []string{"hello world"}},
+		{StartMs: 2000, EndMs: 2010, Lines: []string{"hello world"}},
+		{StartMs: 2010, EndMs: 4000, Lines: []string{"hello world", "second line"}},
+	}
+	compareCues(cues, expected, t)
+}
+
+func TestParseVtt_NotVtt(t *testing.T) {
+	if _, err := ytdlp.ParseVtt("1\n00:00:01,000 --> 00:00:02,000\nsrt\n"); err == nil {
+		t.Errorf("Expected an error for a non-WebVTT file")
+	}
+}
+
+func compareCues(actual []ytdlp.VttCue, expected []ytdlp.VttCue, t *testing.T) {
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d cues, got %d: %v", len(expected), len(actual), actual)
+	}
+
+	for i := range expected {
+		if actual[i].StartMs != expected[i].StartMs || actual[i].EndMs != expected[i].EndMs || !slices.Equal(actual[i].Lines, expected[i].Lines) {
+			t.Errorf("Cue %d: expected %v, got %v", i, expected[i], actual[i])
+		}
+	}
+}
@@ -14,6 +14,8 @@ export interface FullSourceRs {
 
     ReleaseDate: string | null;
 
+    Genres: string[] | null;
+
     ThumbnailId: string | null;
 }
 
@@ -73,6 +75,8 @@ export interface Tape {
     Artist: string;
     ReleasedAt: string | null;
 
+    Genres: string[] | null;
+
     Tracks: TrackRs[];
 }
 